@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/lib/pq"
@@ -11,14 +12,19 @@ import (
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/email"
 	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/audit_service"
 	"github.com/tcp_snm/flux/internal/service/auth_service"
 	"github.com/tcp_snm/flux/internal/service/contest_service"
+	"github.com/tcp_snm/flux/internal/service/lifecycle_service"
 	"github.com/tcp_snm/flux/internal/service/lock_service"
 	"github.com/tcp_snm/flux/internal/service/problem_service"
+	"github.com/tcp_snm/flux/internal/service/rating_service"
 	"github.com/tcp_snm/flux/internal/service/scheduler_service"
 	"github.com/tcp_snm/flux/internal/service/submission_service"
+	"github.com/tcp_snm/flux/internal/service/team_service"
 	"github.com/tcp_snm/flux/internal/service/tournament_service"
 	"github.com/tcp_snm/flux/internal/service/user_service"
+	"github.com/tcp_snm/flux/middleware"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
@@ -52,20 +58,48 @@ func initUserService(db *database.Queries) *user_service.UserService {
 	log.Info("initializing user service")
 	us := user_service.UserService{
 		DB: db,
+		// managers and head coordinators need the second factor
+		RequireTwoFactor: os.Getenv("REQUIRE_TWO_FACTOR") == "true",
 	}
 	err := us.IntializeUserServices()
 	if err != nil {
 		panic(err)
 	}
+	if us.RequireTwoFactor {
+		log.Infof("two factor authentication is required for %v", user_service.TwoFactorRoles)
+	}
 	return &us
 }
 
 func initAuthService(db *database.Queries, us *user_service.UserService) *auth_service.AuthService {
 	log.Info("initializing auth service")
-	return &auth_service.AuthService{
+	as := auth_service.AuthService{
 		DB:         db,
 		UserConfig: us,
 	}
+
+	// oidc login is optional
+	issuer := os.Getenv("OIDC_ISSUER")
+	if issuer == "" {
+		log.Info("OIDC_ISSUER not found, oidc login is disabled")
+		return &as
+	}
+	config := auth_service.OIDCConfig{
+		Issuer:        issuer,
+		ClientID:      os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret:  os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:   os.Getenv("OIDC_REDIRECT_URL"),
+		AllowedDomain: os.Getenv("OIDC_ALLOWED_DOMAIN"),
+		PostLoginURL:  os.Getenv("OIDC_POST_LOGIN_URL"),
+	}
+	if config.ClientID == "" || config.ClientSecret == "" ||
+		config.RedirectURL == "" || config.PostLoginURL == "" {
+		panic("OIDC_CLIENT_ID, OIDC_CLIENT_SECRET, OIDC_REDIRECT_URL and OIDC_POST_LOGIN_URL are required for oidc login")
+	}
+	as.OIDC = auth_service.NewOIDCProvider(config)
+	log.Infof("oidc login enabled with %s", issuer)
+
+	return &as
 }
 
 func initLockService(db *database.Queries, us *user_service.UserService) *lock_service.LockService {
@@ -88,11 +122,20 @@ func initProblemService(
 	}
 }
 
+func initTeamService(db *database.Queries, us *user_service.UserService) *team_service.TeamService {
+	log.Info("initializing team service")
+	return &team_service.TeamService{
+		DB:                db,
+		UserServiceConfig: us,
+	}
+}
+
 func initContestService(
 	db *database.Queries,
 	ls *lock_service.LockService,
 	us *user_service.UserService,
 	ps *problem_service.ProblemService,
+	tms *team_service.TeamService,
 ) *contest_service.ContestService {
 	log.Info("initializing contest service")
 	return &contest_service.ContestService{
@@ -100,6 +143,7 @@ func initContestService(
 		LockServiceConfig:    ls,
 		UserServiceConfig:    us,
 		ProblemServiceConfig: ps,
+		TeamServiceConfig:    tms,
 	}
 }
 
@@ -118,6 +162,65 @@ func initTournamentService(
 	}
 }
 
+func initRatingService(
+	db *database.Queries,
+	us *user_service.UserService,
+	cs *contest_service.ContestService,
+) *rating_service.RatingService {
+	log.Info("initializing rating service")
+	return &rating_service.RatingService{
+		DB:                   db,
+		UserServiceConfig:    us,
+		ContestServiceConfig: cs,
+	}
+}
+
+func initLifecycleService(
+	db *database.Queries,
+	us *user_service.UserService,
+	cs *contest_service.ContestService,
+	rs *rating_service.RatingService,
+	ts *tournament_service.TournamentService,
+) *lifecycle_service.LifecycleService {
+	log.Info("initializing lifecycle service")
+	ls := &lifecycle_service.LifecycleService{
+		DB:                   db,
+		UserServiceConfig:    us,
+		ContestServiceConfig: cs,
+	}
+
+	// post contest jobs
+	ls.Subscribe(
+		lifecycle_service.EventContestEnded,
+		func(ctx context.Context, event lifecycle_service.Event) error {
+			return rs.FinalizeEndedContest(ctx, event.ContestID)
+		},
+	)
+	ls.Subscribe(
+		lifecycle_service.EventContestEnded,
+		func(ctx context.Context, event lifecycle_service.Event) error {
+			return cs.SendResultsSummary(ctx, event.ContestID)
+		},
+	)
+	ls.Subscribe(
+		lifecycle_service.EventContestEnded,
+		func(ctx context.Context, event lifecycle_service.Event) error {
+			return ts.ResolveMatchContest(ctx, event.ContestID)
+		},
+	)
+
+	ls.Start(30 * time.Second)
+	return ls
+}
+
+func initAuditService(db *database.Queries, us *user_service.UserService) *audit_service.AuditService {
+	log.Info("initializing audit service")
+	return &audit_service.AuditService{
+		DB:                db,
+		UserServiceConfig: us,
+	}
+}
+
 func initServices(db *database.Queries) *api.Api {
 	log.Info("initializing api config")
 	us := initUserService(db)
@@ -128,10 +231,18 @@ func initServices(db *database.Queries) *api.Api {
 	log.Info("lock service created")
 	ps := initProblemService(db, ls, us)
 	log.Info("problem service created")
-	cs := initContestService(db, ls, us, ps)
+	tms := initTeamService(db, us)
+	log.Info("team service created")
+	cs := initContestService(db, ls, us, ps, tms)
 	log.Info("contest service created")
 	ts := initTournamentService(db, us, ls, cs)
 	log.Info("tournament service created")
+	rs := initRatingService(db, us, cs)
+	log.Info("rating service created")
+	lcs := initLifecycleService(db, us, cs, rs, ts)
+	log.Info("lifecycle service created")
+	auds := initAuditService(db, us)
+	log.Info("audit service created")
 
 	// initialize scheduler
 	scheduler := scheduler_service.Scheduler{
@@ -172,6 +283,15 @@ func initServices(db *database.Queries) *api.Api {
 
 	
 
+	// the address of the client is only taken from these proxies
+	trustedProxies, err := api.ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		panic(err)
+	}
+	if len(trustedProxies) == 0 {
+		log.Info("TRUSTED_PROXIES not found, using the address of the peer as the client address")
+	}
+
 	a := api.Api{
 		AuthServiceConfig:       as,
 		UserServiceConfig:       us,
@@ -180,6 +300,11 @@ func initServices(db *database.Queries) *api.Api {
 		ContestServiceConfig:    cs,
 		TournamentServiceConfig: ts,
 		SubmissionService:       &ss,
+		TeamServiceConfig:       tms,
+		RatingServiceConfig:     rs,
+		LifecycleServiceConfig:  lcs,
+		AuditServiceConfig:      auds,
+		TrustedProxies:          trustedProxies,
 	}
 	return &a
 }
@@ -197,6 +322,7 @@ func setup() {
 	pool, db := initDatabase()
 	service.InitializeServices(pool)
 	apiConfig = initServices(db)
+	middleware.SetAuthenticator(apiConfig.AuthServiceConfig)
 	email.StartEmailWorkers(1)
 }
 
@@ -208,7 +334,7 @@ func setCors(router *chi.Mux) {
 				AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 				AllowedHeaders:   []string{"*"},
 				AllowCredentials: false,
-				ExposedHeaders:   []string{"Link"},
+				ExposedHeaders:   []string{"Link", middleware.KeyRequestIDHeaderName},
 				MaxAge:           300,
 			},
 		),
@@ -222,6 +348,7 @@ func main() {
 	// initialize a new router
 	router := chi.NewRouter()
 	setCors(router)
+	router.Use(middleware.RequestIDMiddleware)
 
 	// mount v1 router
 	v1router := NewV1Router()
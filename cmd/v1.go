@@ -11,34 +11,56 @@ func NewV1Router() *chi.Mux {
 	// configure all endpoints
 	v1.Get("/healthz", middleware.JWTMiddleware(apiConfig.HandlerReadiness))
 	v1.Get("/me", middleware.JWTMiddleware(apiConfig.HandlerGetMe))
+	v1.Get("/me/notifications", middleware.JWTMiddleware(apiConfig.HandlerGetNotificationSettings))
+	v1.Put("/me/notifications", middleware.JWTMiddleware(apiConfig.HandlerSetNotificationSettings))
+	v1.Get("/me/permissions", middleware.JWTMiddleware(apiConfig.HandlerGetMyPermissions))
+	v1.Get("/me/sessions", middleware.JWTMiddleware(apiConfig.HandlerGetMySessions))
+	v1.Delete("/me/sessions", middleware.JWTMiddleware(apiConfig.HandlerRevokeMySession))
+	v1.Get("/me/tokens", middleware.JWTMiddleware(apiConfig.HandlerGetMyAccessTokens))
+	v1.Post("/me/tokens", middleware.JWTMiddleware(apiConfig.HandlerCreateAccessToken))
+	v1.Delete("/me/tokens", middleware.JWTMiddleware(apiConfig.HandlerRevokeAccessToken))
+	v1.Get("/me/totp", middleware.JWTMiddleware(apiConfig.HandlerGetTwoFactorStatus))
+	v1.Post("/me/totp", middleware.JWTMiddleware(apiConfig.HandlerStartTOTPEnrolment))
+	v1.Delete("/me/totp", middleware.JWTMiddleware(apiConfig.HandlerDisableTOTP))
+	v1.Post("/me/totp/confirm", middleware.JWTMiddleware(apiConfig.HandlerConfirmTOTPEnrolment))
+	v1.Post("/me/totp/recovery_codes", middleware.JWTMiddleware(apiConfig.HandlerRegenerateRecoveryCodes))
 
 	// auth layer
 	v1.Get("/auth/signup", apiConfig.HandlerSignUpSendMail)
 	v1.Post("/auth/signup", apiConfig.HandlerSignUp)
 	v1.Post("/auth/login", apiConfig.HandlerLogin)
+	v1.Get("/auth/oidc/login", apiConfig.HandlerStartOIDCLogin)
+	v1.Get("/auth/oidc/callback", apiConfig.HandlerOIDCCallback)
 	v1.Get("/auth/logout", middleware.JWTMiddleware(apiConfig.HandlerLogout))
 	v1.Get("/auth/reset-password-send-email", apiConfig.HandlerResetPasswordSendMail)
 	v1.Post("/auth/reset-password-verify", apiConfig.HandlerResetPassword)
+	v1.Delete("/auth/sessions", middleware.JWTMiddleware(apiConfig.HandlerRevokeUserSessions))
 
 	// locks layer
 	// get locks
 	v1.Get("/locks", middleware.JWTMiddleware(apiConfig.HandlerGetLockById))
 	v1.Post("/locks/search", middleware.JWTMiddleware(apiConfig.HandlerGetLocksByFilter))
+	v1.Get("/locks/grants", middleware.JWTMiddleware(apiConfig.HandlerGetLockGrants))
 	// create lock
 	v1.Post("/locks", middleware.JWTMiddleware(apiConfig.HandlerCreateLock))
 	// update lock
 	v1.Put("/locks", middleware.JWTMiddleware(apiConfig.HandlerUpdateLock))
+	v1.Put("/locks/release", middleware.JWTMiddleware(apiConfig.HandlerReleaseLock))
+	v1.Put("/locks/grants", middleware.JWTMiddleware(apiConfig.HandlerSetLockGrant))
 	// delete lock
 	v1.Delete("/locks", middleware.JWTMiddleware(apiConfig.HanlderDeleteLockById))
+	v1.Delete("/locks/grants", middleware.JWTMiddleware(apiConfig.HandlerRevokeLockGrant))
 
 	// problems layer
 	// search
 	v1.Get("/problems/standard", middleware.JWTMiddleware(apiConfig.HandlerGetStandardProblemById))
 	v1.Post("/problems/search", middleware.JWTMiddleware(apiConfig.HandlerGetProblemsByFilters))
+	v1.Get("/problems/subtasks", middleware.JWTMiddleware(apiConfig.HandlerGetProblemSubtasks))
 	// add
 	v1.Post("/problems/standard", middleware.JWTMiddleware(apiConfig.HandlerAddStandardProblem))
 	// update
 	v1.Put("/problems", middleware.JWTMiddleware(apiConfig.HandlerUpdateProblem))
+	v1.Put("/problems/subtasks", middleware.JWTMiddleware(apiConfig.HandlerSetProblemSubtasks))
 
 	// contest
 	// search
@@ -47,25 +69,109 @@ func NewV1Router() *chi.Mux {
 	v1.Get("/contests/users", middleware.JWTMiddleware(apiConfig.HandlerGetContestUsers))
 	v1.Post("/contests/search", middleware.JWTMiddleware(apiConfig.HandlerGetContestsByFilters))
 	v1.Get("/contests/user-registered", middleware.JWTMiddleware(apiConfig.HandlerGetUserRegisteredContests))
+	v1.Get("/contests/standings", middleware.JWTMiddleware(apiConfig.HandlerGetContestStandings))
+	v1.Get("/contests/export", middleware.JWTMiddleware(apiConfig.HandlerExportContestResults))
+	v1.Get("/contests/freeze", middleware.JWTMiddleware(apiConfig.HandlerGetContestFreeze))
+	v1.Get("/contests/virtual", middleware.JWTMiddleware(apiConfig.HandlerGetVirtualParticipation))
+	v1.Get("/contests/clarifications", middleware.JWTMiddleware(apiConfig.HandlerGetClarifications))
+	v1.Get("/contests/clarifications/unanswered", middleware.JWTMiddleware(apiConfig.HandlerGetUnansweredClarifications))
+	v1.Get("/contests/registration", middleware.JWTMiddleware(apiConfig.HandlerGetRegistrationSettings))
+	v1.Get("/contests/registration/requests", middleware.JWTMiddleware(apiConfig.HandlerGetRegistrationRequests))
+	v1.Get("/contests/teams", middleware.JWTMiddleware(apiConfig.HandlerGetContestTeams))
+	v1.Get("/contests/teams/settings", middleware.JWTMiddleware(apiConfig.HandlerGetContestTeamSettings))
+	v1.Get("/contests/rating", middleware.JWTMiddleware(apiConfig.HandlerGetContestRating))
+	v1.Get("/contests/lifecycle", middleware.JWTMiddleware(apiConfig.HandlerGetContestLifecycleJobs))
+	v1.Get("/contests/reminders", middleware.JWTMiddleware(apiConfig.HandlerGetContestReminders))
 	// create
 	v1.Post("/contests", middleware.JWTMiddleware(apiConfig.HandlerCreateContest))
+	v1.Post("/contests/clone", middleware.JWTMiddleware(apiConfig.HandlerCloneContest))
+	v1.Post("/contests/virtual", middleware.JWTMiddleware(apiConfig.HandlerStartVirtualParticipation))
+	v1.Post("/contests/clarifications", middleware.JWTMiddleware(apiConfig.HandlerAskClarification))
+	v1.Post("/contests/register", middleware.JWTMiddleware(apiConfig.HandlerRegisterSelf))
+	v1.Post("/contests/users", middleware.JWTMiddleware(apiConfig.HandlerAddUsersToContest))
+	v1.Post("/contests/teams", middleware.JWTMiddleware(apiConfig.HandlerRegisterTeamToContest))
+	v1.Post("/contests/broadcast", middleware.JWTMiddleware(apiConfig.HandlerBroadcastToParticipants))
 	// update
 	v1.Put("/contests/users", middleware.JWTMiddleware(apiConfig.HandlerSetUsersInContest))
 	v1.Put("/contests/problems", middleware.JWTMiddleware(apiConfig.HandlerSetProblemsInContest))
 	v1.Put("/contests", middleware.JWTMiddleware(apiConfig.HandlerUpdateContest))
+	v1.Put("/contests/clarifications", middleware.JWTMiddleware(apiConfig.HandlerAnswerClarification))
+	v1.Put("/contests/freeze", middleware.JWTMiddleware(apiConfig.HandlerSetContestFreeze))
+	v1.Post("/contests/standings/unfreeze", middleware.JWTMiddleware(apiConfig.HandlerUnfreezeContest))
+	v1.Post("/contests/standings/reveal", middleware.JWTMiddleware(apiConfig.HandlerRevealNextResult))
+	v1.Put("/contests/registration", middleware.JWTMiddleware(apiConfig.HandlerSetRegistrationSettings))
+	v1.Put("/contests/registration/requests", middleware.JWTMiddleware(apiConfig.HandlerReviewRegistrationRequest))
+	v1.Put("/contests/teams/settings", middleware.JWTMiddleware(apiConfig.HandlerSetContestTeamSettings))
+	v1.Put("/contests/rating", middleware.JWTMiddleware(apiConfig.HandlerSetContestRated))
+	v1.Post("/contests/rating/finalize", middleware.JWTMiddleware(apiConfig.HandlerFinalizeContestRatings))
+	v1.Post("/contests/rating/recompute", middleware.JWTMiddleware(apiConfig.HandlerRecomputeRatings))
+	v1.Put("/contests/reminders", middleware.JWTMiddleware(apiConfig.HandlerSetContestReminders))
 	// delete
 	v1.Delete("/contests", middleware.JWTMiddleware(apiConfig.HanlderDeleteContest))
+	v1.Delete("/contests/freeze", middleware.JWTMiddleware(apiConfig.HandlerDeleteContestFreeze))
+	v1.Delete("/contests/register", middleware.JWTMiddleware(apiConfig.HandlerUnregisterSelf))
+	v1.Delete("/contests/users", middleware.JWTMiddleware(apiConfig.HandlerRemoveUsersFromContest))
+	v1.Delete("/contests/teams", middleware.JWTMiddleware(apiConfig.HandlerUnregisterTeamFromContest))
+	v1.Delete("/contests/teams/settings", middleware.JWTMiddleware(apiConfig.HandlerDeleteContestTeamSettings))
+
+	// ratings
+	v1.Get("/ratings", middleware.JWTMiddleware(apiConfig.HandlerGetUserRating))
+
+	// teams
+	// search
+	v1.Get("/teams", middleware.JWTMiddleware(apiConfig.HandlerGetTeam))
+	v1.Get("/teams/me", middleware.JWTMiddleware(apiConfig.HandlerGetUserTeams))
+	v1.Get("/teams/invitations", middleware.JWTMiddleware(apiConfig.HandlerGetTeamInvitations))
+	// create
+	v1.Post("/teams", middleware.JWTMiddleware(apiConfig.HandlerCreateTeam))
+	v1.Post("/teams/invitations", middleware.JWTMiddleware(apiConfig.HandlerInviteToTeam))
+	// update
+	v1.Put("/teams/invitations", middleware.JWTMiddleware(apiConfig.HandlerRespondToTeamInvitation))
+	// delete
+	v1.Delete("/teams", middleware.JWTMiddleware(apiConfig.HandlerDeleteTeam))
+	v1.Delete("/teams/members", middleware.JWTMiddleware(apiConfig.HandlerRemoveTeamMember))
 
 	// tournaments
 	// search
 	v1.Get("/tournaments", middleware.JWTMiddleware(apiConfig.HandlerGetTournament))
 	v1.Get("/tournaments/rounds", middleware.JWTMiddleware(apiConfig.HandlerGetTournamentRound))
 	v1.Post("/tournaments/search", middleware.JWTMiddleware(apiConfig.HandlerGetTournamentsByFilters))
+	v1.Get("/tournaments/standings", middleware.JWTMiddleware(apiConfig.HandlerGetTournamentStandings))
+	v1.Get("/tournaments/rounds/advancement", middleware.JWTMiddleware(apiConfig.HandlerGetAdvancementRule))
+	v1.Post("/tournaments/rounds/advancement/preview", middleware.JWTMiddleware(apiConfig.HandlerPreviewAdvancement))
+	v1.Get("/tournaments/bracket", middleware.JWTMiddleware(apiConfig.HandlerGetBracket))
 	// create
 	v1.Post("/tournaments", middleware.JWTMiddleware(apiConfig.HandlerCreateTournament))
 	v1.Post("/tournaments/rounds", middleware.JWTMiddleware(apiConfig.HandlerCreateTournamentRound))
+	v1.Post("/tournaments/bracket", middleware.JWTMiddleware(apiConfig.HandlerCreateBracket))
 	// update
 	v1.Put("/tournaments/contests", middleware.JWTMiddleware(apiConfig.HandlerChangeTournamentContest))
+	v1.Put("/tournaments/rounds/advancement", middleware.JWTMiddleware(apiConfig.HandlerSetAdvancementRule))
+	v1.Put("/tournaments/rounds/scoring", middleware.JWTMiddleware(apiConfig.HandlerSetRoundScoring))
+	v1.Post("/tournaments/rounds/advance", middleware.JWTMiddleware(apiConfig.HandlerAdvanceRound))
+	v1.Post("/tournaments/bracket/start", middleware.JWTMiddleware(apiConfig.HandlerStartBracketRound))
+	v1.Post("/tournaments/bracket/resolve", middleware.JWTMiddleware(apiConfig.HandlerResolveBracketRound))
+	v1.Put("/tournaments", middleware.JWTMiddleware(apiConfig.HandlerUpdateTournament))
+	v1.Put("/tournaments/publish", middleware.JWTMiddleware(apiConfig.HandlerPublishTournament))
+	v1.Put("/tournaments/rounds", middleware.JWTMiddleware(apiConfig.HandlerUpdateTournamentRound))
+	v1.Put("/tournaments/rounds/publish", middleware.JWTMiddleware(apiConfig.HandlerPublishTournamentRound))
+	v1.Put("/tournaments/rounds/order", middleware.JWTMiddleware(apiConfig.HandlerReorderTournamentRounds))
+	// delete
+	v1.Delete("/tournaments", middleware.JWTMiddleware(apiConfig.HandlerDeleteTournament))
+	v1.Delete("/tournaments/rounds", middleware.JWTMiddleware(apiConfig.HandlerDeleteTournamentRound))
+
+	// roles
+	// search
+	v1.Get("/roles", middleware.JWTMiddleware(apiConfig.HandlerGetRoles))
+	v1.Get("/roles/permissions", middleware.JWTMiddleware(apiConfig.HandlerGetPermissions))
+	v1.Post("/roles/members/search", middleware.JWTMiddleware(apiConfig.HandlerGetRoleMembers))
+	// create
+	v1.Post("/roles", middleware.JWTMiddleware(apiConfig.HandlerCreateRole))
+	// update
+	v1.Put("/roles/members", middleware.JWTMiddleware(apiConfig.HandlerGrantRole))
+	v1.Put("/roles/permissions", middleware.JWTMiddleware(apiConfig.HandlerSetRolePermissions))
+	// delete
+	v1.Delete("/roles/members", middleware.JWTMiddleware(apiConfig.HandlerRevokeRole))
 
 	// bots
 	v1.Post("/bots", middleware.JWTMiddleware(apiConfig.AddBot))
@@ -76,5 +182,10 @@ func NewV1Router() *chi.Mux {
 
 	// submit
 	v1.Post("/submit", middleware.JWTMiddleware(apiConfig.HandlerSubmit))
+	v1.Post("/submissions/subtask_results", middleware.JWTMiddleware(apiConfig.HandlerRecordSubtaskResults))
+
+	// audit logs
+	// search
+	v1.Post("/audit/search", middleware.JWTMiddleware(apiConfig.HandlerGetAuditLogs))
 	return v1
 }
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service/auth_service"
+)
+
+func (a *Api) HandlerCreateAccessToken(w http.ResponseWriter, r *http.Request) {
+	// decode the body
+	var request auth_service.CreateAccessTokenRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	accessToken, err := a.AuthServiceConfig.CreateAccessToken(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal, the token cannot be shown again so never log it
+	response, err := json.Marshal(accessToken)
+	if err != nil {
+		log.Errorf("cannot marshal access token %v, %v", accessToken.ID, err)
+		http.Error(
+			w,
+			"access token was created, but there was an error preparing response",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusCreated, response)
+}
+
+func (a *Api) HandlerGetMyAccessTokens(w http.ResponseWriter, r *http.Request) {
+	accessTokens, err := a.AuthServiceConfig.GetMyAccessTokens(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(accessTokens)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", accessTokens, err)
+		http.Error(w, flux_errors.ErrInternal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerRevokeAccessToken(w http.ResponseWriter, r *http.Request) {
+	// get the id
+	tokenID, err := uuid.Parse(r.URL.Query().Get("token_id"))
+	if err != nil {
+		http.Error(w, "invalid token id provided", http.StatusBadRequest)
+		return
+	}
+
+	err = a.AuthServiceConfig.RevokeAccessToken(r.Context(), tokenID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("access token revoked successfully"))
+}
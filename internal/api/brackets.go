@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service/tournament_service"
+)
+
+func (a *Api) HandlerGetBracket(w http.ResponseWriter, r *http.Request) {
+	// parse
+	tournamentID, err := uuid.Parse(r.URL.Query().Get("tournament_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the bracket
+	bracket, err := a.TournamentServiceConfig.GetBracket(r.Context(), tournamentID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(bracket)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", bracket, err.Error())
+		http.Error(
+			w, "cannot send bracket, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerCreateBracket(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request tournament_service.CreateBracketRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// create the bracket
+	bracket, err := a.TournamentServiceConfig.CreateBracket(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(bracket)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", bracket, err.Error())
+		http.Error(w, "bracket created but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusCreated, response)
+}
+
+func (a *Api) HandlerStartBracketRound(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request tournament_service.StartBracketRoundRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// create the contests of the round
+	bracket, err := a.TournamentServiceConfig.StartBracketRound(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(bracket)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", bracket, err.Error())
+		http.Error(w, "round started but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerResolveBracketRound(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request tournament_service.ResolveBracketRoundRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// decide the ended matches
+	bracket, err := a.TournamentServiceConfig.ResolveBracketRound(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(bracket)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", bracket, err.Error())
+		http.Error(w, "round resolved but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+)
+
+func (a *Api) HandlerAskClarification(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request contest_service.AskClarificationRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// ask
+	clarification, err := a.ContestServiceConfig.AskClarification(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(clarification)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", clarification, err.Error())
+		http.Error(
+			w, "clarification created but error in preparing response",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusCreated, response)
+}
+
+func (a *Api) HandlerAnswerClarification(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request contest_service.AnswerClarificationRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// answer
+	clarification, err := a.ContestServiceConfig.AnswerClarification(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(clarification)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", clarification, err.Error())
+		http.Error(
+			w, "clarification answered but error in preparing response",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+// HandlerGetClarifications is also the poll endpoint for clients. Passing the
+// updated_at of the latest clarification they have as since returns only the updates.
+func (a *Api) HandlerGetClarifications(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestID, err := uuid.Parse(r.URL.Query().Get("contest_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the optional since timestamp
+	var since *time.Time
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, sinceStr)
+		if err != nil {
+			http.Error(w, "invalid since, expected RFC3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		since = &parsed
+	}
+
+	// get clarifications
+	clarifications, err := a.ContestServiceConfig.GetClarifications(r.Context(), contestID, since)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(clarifications)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", clarifications, err.Error())
+		http.Error(
+			w, "cannot send clarifications, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerGetUnansweredClarifications(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestID, err := uuid.Parse(r.URL.Query().Get("contest_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get clarifications
+	clarifications, err := a.ContestServiceConfig.GetUnansweredClarifications(r.Context(), contestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(clarifications)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", clarifications, err.Error())
+		http.Error(
+			w, "cannot send clarifications, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+)
+
+func (a *Api) HandlerCloneContest(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request contest_service.CloneContestRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// clone contest
+	contest, err := a.ContestServiceConfig.CloneContest(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(contest)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", contest, err.Error())
+		http.Error(
+			w, "contest cloned but error in preparing response",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	// respond
+	respondWithJson(w, http.StatusCreated, response)
+}
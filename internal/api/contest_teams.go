@@ -0,0 +1,161 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+)
+
+func (a *Api) HandlerSetContestTeamSettings(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request contest_service.ContestTeamSettings
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// set the settings
+	settings, err := a.ContestServiceConfig.SetContestTeamSettings(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(settings)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", settings, err.Error())
+		http.Error(w, "team settings set but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerGetContestTeamSettings(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestID, err := uuid.Parse(r.URL.Query().Get("contest_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the settings
+	settings, err := a.ContestServiceConfig.GetContestTeamSettings(r.Context(), contestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(settings)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", settings, err.Error())
+		http.Error(
+			w, "cannot send team settings, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerDeleteContestTeamSettings(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestID, err := uuid.Parse(r.URL.Query().Get("contest_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// delete the settings
+	err = a.ContestServiceConfig.DeleteContestTeamSettings(r.Context(), contestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("team settings deleted successfully"))
+}
+
+func (a *Api) HandlerGetContestTeams(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestID, err := uuid.Parse(r.URL.Query().Get("contest_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the teams
+	teams, err := a.ContestServiceConfig.GetContestTeams(r.Context(), contestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(teams)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", teams, err.Error())
+		http.Error(
+			w, "cannot send teams, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerRegisterTeamToContest(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	type params struct {
+		ContestID uuid.UUID `json:"contest_id"`
+		TeamID    uuid.UUID `json:"team_id"`
+	}
+	var request params
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// register the team
+	err = a.ContestServiceConfig.RegisterTeamToContest(r.Context(), request.ContestID, request.TeamID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusCreated, []byte("team registered successfully"))
+}
+
+func (a *Api) HandlerUnregisterTeamFromContest(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestID, err := uuid.Parse(r.URL.Query().Get("contest_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the team id
+	teamID, err := uuid.Parse(r.URL.Query().Get("team_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// unregister the team
+	err = a.ContestServiceConfig.UnregisterTeamFromContest(r.Context(), contestID, teamID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("team unregistered successfully"))
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+func (a *Api) HandlerDeleteTournament(w http.ResponseWriter, r *http.Request) {
+	// parse
+	tournamentID, err := uuid.Parse(r.URL.Query().Get("tournament_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// delete the tournament
+	err = a.TournamentServiceConfig.DeleteTournament(r.Context(), tournamentID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("tournament deleted successfully"))
+}
+
+func (a *Api) HandlerDeleteTournamentRound(w http.ResponseWriter, r *http.Request) {
+	// parse
+	tournamentID, err := uuid.Parse(r.URL.Query().Get("tournament_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	roundNumber, err := strconv.Atoi(r.URL.Query().Get("round_number"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// delete the round
+	err = a.TournamentServiceConfig.DeleteTournamentRound(r.Context(), tournamentID, int32(roundNumber))
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("round deleted successfully"))
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+)
+
+func (a *Api) HandlerExportContestResults(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestID, err := uuid.Parse(r.URL.Query().Get("contest_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// csv is the default format
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = contest_service.ExportFormatCSV
+	}
+
+	// unofficial participants are excluded by default
+	includeUnofficial := false
+	if includeStr := r.URL.Query().Get("include_unofficial"); includeStr != "" {
+		includeUnofficial, err = strconv.ParseBool(includeStr)
+		if err != nil {
+			http.Error(w, "invalid include_unofficial", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// export
+	results, err := a.ContestServiceConfig.ExportContestResults(
+		r.Context(),
+		contest_service.ExportResultsRequest{
+			ContestID:         contestID,
+			Format:            format,
+			IncludeUnofficial: includeUnofficial,
+		},
+	)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// respond as a file
+	w.Header().Set("Content-Type", results.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", results.FileName))
+	w.WriteHeader(http.StatusOK)
+	w.Write(results.Data)
+}
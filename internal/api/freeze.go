@@ -0,0 +1,144 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+)
+
+func (a *Api) HandlerSetContestFreeze(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request contest_service.ContestFreeze
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// set the freeze
+	freeze, err := a.ContestServiceConfig.SetContestFreeze(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(freeze)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", freeze, err.Error())
+		http.Error(w, "freeze set but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerGetContestFreeze(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestID, err := uuid.Parse(r.URL.Query().Get("contest_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the freeze
+	freeze, err := a.ContestServiceConfig.GetContestFreeze(r.Context(), contestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(freeze)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", freeze, err.Error())
+		http.Error(
+			w, "cannot send freeze, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerDeleteContestFreeze(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestID, err := uuid.Parse(r.URL.Query().Get("contest_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// delete the freeze
+	err = a.ContestServiceConfig.DeleteContestFreeze(r.Context(), contestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("freeze deleted successfully"))
+}
+
+func (a *Api) HandlerUnfreezeContest(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	type params struct {
+		ContestID uuid.UUID `json:"contest_id"`
+	}
+	var request params
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// unfreeze
+	standings, err := a.ContestServiceConfig.UnfreezeContest(r.Context(), request.ContestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(standings)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", standings, err.Error())
+		http.Error(w, "contest unfrozen but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerRevealNextResult(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	type params struct {
+		ContestID uuid.UUID `json:"contest_id"`
+	}
+	var request params
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// reveal
+	reveal, err := a.ContestServiceConfig.RevealNextResult(r.Context(), request.ContestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(reveal)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", reveal, err.Error())
+		http.Error(w, "result revealed but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
@@ -18,7 +18,7 @@ func (a *Api) HandlerGetMe(w http.ResponseWriter, r *http.Request) {
 	// marshal
 	response, err := json.Marshal(user)
 	if err != nil {
-		log.Errorf("cannot marshal %s, %v", user, err)
+		log.Errorf("cannot marshal %v, %v", user, err)
 		http.Error(w, flux_errors.ErrInternal.Error(), http.StatusInternalServerError)
 		return
 	}
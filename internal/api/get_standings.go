@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+func (a *Api) HandlerGetContestStandings(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestIDStr := r.URL.Query().Get("contest_id")
+
+	// parse
+	contestID, err := uuid.Parse(contestIDStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// virtual participants are excluded by default
+	includeVirtual := false
+	if includeVirtualStr := r.URL.Query().Get("include_virtual"); includeVirtualStr != "" {
+		includeVirtual, err = strconv.ParseBool(includeVirtualStr)
+		if err != nil {
+			http.Error(w, "invalid include_virtual", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// get the standings
+	standings, err := a.ContestServiceConfig.GetContestStandings(
+		r.Context(),
+		contestID,
+		includeVirtual,
+	)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(standings)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", standings, err.Error())
+		http.Error(
+			w, "cannot send standings, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+func (a *Api) HandlerGetContestLifecycleJobs(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestID, err := uuid.Parse(r.URL.Query().Get("contest_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the jobs
+	jobs, err := a.LifecycleServiceConfig.GetContestLifecycleJobs(r.Context(), contestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(jobs)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", jobs, err.Error())
+		http.Error(
+			w, "cannot send lifecycle jobs, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service/lock_service"
+)
+
+func (a *Api) HandlerGetLockGrants(w http.ResponseWriter, r *http.Request) {
+	// get the id
+	lockIdStr := r.URL.Query().Get("lock_id")
+	lockId, err := uuid.Parse(lockIdStr)
+	if err != nil {
+		http.Error(w, "invalid lock id provided", http.StatusBadRequest)
+		return
+	}
+
+	grants, err := a.LockServiceConfig.GetLockGrants(r.Context(), lockId)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	bytes, err := json.Marshal(grants)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", grants, err.Error())
+		http.Error(w, "failed to prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, bytes)
+}
+
+func (a *Api) HandlerSetLockGrant(w http.ResponseWriter, r *http.Request) {
+	// decode the body
+	var request lock_service.SetLockGrantRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	grant, err := a.LockServiceConfig.SetLockGrant(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	bytes, err := json.Marshal(grant)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", grant, err.Error())
+		http.Error(
+			w,
+			"access was granted, but there was an error preparing response",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, bytes)
+}
+
+func (a *Api) HandlerRevokeLockGrant(w http.ResponseWriter, r *http.Request) {
+	// get the id
+	grantIdStr := r.URL.Query().Get("grant_id")
+	grantId, err := uuid.Parse(grantIdStr)
+	if err != nil {
+		http.Error(w, "invalid grant id provided", http.StatusBadRequest)
+		return
+	}
+
+	err = a.LockServiceConfig.RevokeLockGrant(r.Context(), grantId)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("grant revoked successfully"))
+}
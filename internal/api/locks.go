@@ -101,3 +101,34 @@ func (a *Api) HanlderDeleteLockById(w http.ResponseWriter, r *http.Request) {
 
 	respondWithJson(w, http.StatusOK, []byte("lock deleted successfully"))
 }
+
+func (a *Api) HandlerReleaseLock(w http.ResponseWriter, r *http.Request) {
+	// get the id
+	lockIdStr := r.URL.Query().Get("lock_id")
+	lockId, err := uuid.Parse(lockIdStr)
+	if err != nil {
+		http.Error(w, "invalid lock id provided", http.StatusBadRequest)
+		return
+	}
+
+	// release the lock
+	lock, err := a.LockServiceConfig.ReleaseLock(r.Context(), lockId)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	bytes, err := json.Marshal(lock)
+	if err != nil {
+		log.Error(err)
+		http.Error(
+			w,
+			"lock was released, but there was an error preparing response",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, bytes)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/tcp_snm/flux/internal/service/auth_service"
@@ -22,6 +23,9 @@ func (a *Api) HandlerLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	request.UserAgent = r.UserAgent()
+	request.IPAddress = a.clientIP(r)
+
 	// validate the user and gen a jwt token
 	userLoginResponse, jwtToken, tokenExpiry, err := a.AuthServiceConfig.Login(
 		r.Context(),
@@ -40,6 +44,17 @@ func (a *Api) HandlerLogin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// set jwt session cookie
+	setSessionCookie(w, jwtToken, tokenExpiry)
+
+	log.WithFields(log.Fields{
+		"user_name": userLoginResponse.UserName,
+		"roll_no":   userLoginResponse.RollNo,
+	}).Info("logged in")
+
+	respondWithJson(w, http.StatusOK, responseBytes)
+}
+
+func setSessionCookie(w http.ResponseWriter, jwtToken string, tokenExpiry time.Time) {
 	cookie := &http.Cookie{
 		Name:     middleware.KeyJwtSessionCookieName,
 		Value:    jwtToken,
@@ -50,11 +65,4 @@ func (a *Api) HandlerLogin(w http.ResponseWriter, r *http.Request) {
 		SameSite: http.SameSiteLaxMode, // Recommended: Protects against CSRF
 	}
 	http.SetCookie(w, cookie)
-
-	log.WithFields(log.Fields{
-		"user_name": userLoginResponse.UserName,
-		"roll_no":   userLoginResponse.RollNo,
-	}).Info("logged in")
-
-	respondWithJson(w, http.StatusOK, responseBytes)
 }
@@ -8,6 +8,12 @@ import (
 )
 
 func (a *Api) HandlerLogout(w http.ResponseWriter, r *http.Request) {
+	// the token must stop working even if the cookie is kept
+	err := a.AuthServiceConfig.Logout(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
 
 	expiredCookie := &http.Cookie{
 		Name:     middleware.KeyJwtSessionCookieName, // must match login cookie name
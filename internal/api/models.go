@@ -1,11 +1,17 @@
 package api
 
 import (
+	"net/netip"
+
+	"github.com/tcp_snm/flux/internal/service/audit_service"
 	"github.com/tcp_snm/flux/internal/service/auth_service"
 	"github.com/tcp_snm/flux/internal/service/contest_service"
+	"github.com/tcp_snm/flux/internal/service/lifecycle_service"
 	"github.com/tcp_snm/flux/internal/service/lock_service"
 	"github.com/tcp_snm/flux/internal/service/problem_service"
+	"github.com/tcp_snm/flux/internal/service/rating_service"
 	"github.com/tcp_snm/flux/internal/service/submission_service"
+	"github.com/tcp_snm/flux/internal/service/team_service"
 	"github.com/tcp_snm/flux/internal/service/tournament_service"
 	"github.com/tcp_snm/flux/internal/service/user_service"
 )
@@ -18,4 +24,10 @@ type Api struct {
 	ContestServiceConfig    *contest_service.ContestService
 	TournamentServiceConfig *tournament_service.TournamentService
 	SubmissionService       *submission_service.SubmissionService
+	TeamServiceConfig       *team_service.TeamService
+	RatingServiceConfig     *rating_service.RatingService
+	LifecycleServiceConfig  *lifecycle_service.LifecycleService
+	AuditServiceConfig      *audit_service.AuditService
+	// proxies allowed to tell the address of the client
+	TrustedProxies []netip.Prefix
 }
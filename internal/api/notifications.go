@@ -0,0 +1,144 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+func (a *Api) HandlerGetContestReminders(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestID, err := uuid.Parse(r.URL.Query().Get("contest_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the reminders
+	reminders, err := a.ContestServiceConfig.GetContestReminders(r.Context(), contestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(reminders)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", reminders, err.Error())
+		http.Error(
+			w, "cannot send reminders, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerSetContestReminders(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request contest_service.SetRemindersRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// set the reminders
+	reminders, err := a.ContestServiceConfig.SetContestReminders(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(reminders)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", reminders, err.Error())
+		http.Error(w, "reminders set but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerBroadcastToParticipants(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request contest_service.BroadcastRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// broadcast
+	sent, err := a.ContestServiceConfig.BroadcastToParticipants(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	res := map[string]int{"recipients": sent}
+	response, err := json.Marshal(res)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", res, err.Error())
+		http.Error(w, "message broadcasted but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerGetNotificationSettings(w http.ResponseWriter, r *http.Request) {
+	// get the settings
+	settings, err := a.UserServiceConfig.GetNotificationSettings(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(settings)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", settings, err.Error())
+		http.Error(
+			w, "cannot send notification settings, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerSetNotificationSettings(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request user_service.NotificationSettings
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// update the settings
+	settings, err := a.UserServiceConfig.SetNotificationSettings(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(settings)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", settings, err.Error())
+		http.Error(w, "notification settings updated but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
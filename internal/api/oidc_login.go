@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service/auth_service"
+	"github.com/tcp_snm/flux/middleware"
+)
+
+func (a *Api) HandlerStartOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	rememberForMonth := r.URL.Query().Get("remember_for_month") == "true"
+
+	loginStart, err := a.AuthServiceConfig.StartOIDCLogin(r.Context(), rememberForMonth)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// the callback accepts the state only from this browser
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.KeyOIDCStateCookieName,
+		Value:    loginStart.State,
+		Path:     "/",
+		MaxAge:   int((10 * time.Minute).Seconds()),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode, // sent on the redirect back from the provider
+	})
+
+	http.Redirect(w, r, loginStart.AuthURL, http.StatusFound)
+}
+
+func (a *Api) HandlerOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := auth_service.OIDCCallbackRequest{
+		Code:      query.Get("code"),
+		State:     query.Get("state"),
+		UserAgent: r.UserAgent(),
+		IPAddress: a.clientIP(r),
+	}
+	if stateCookie, err := r.Cookie(middleware.KeyOIDCStateCookieName); err == nil {
+		request.BrowserState = stateCookie.Value
+	}
+
+	// the state cannot be used again
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.KeyOIDCStateCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	user, jwtToken, tokenExpiry, err := a.AuthServiceConfig.FinishOIDCLogin(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// set jwt session cookie
+	setSessionCookie(w, jwtToken, tokenExpiry)
+
+	log.WithFields(log.Fields{
+		"user_name": user.UserName,
+		"roll_no":   user.RollNo,
+	}).Info("logged in with oidc")
+
+	http.Redirect(w, r, a.AuthServiceConfig.OIDC.PostLoginURL(), http.StatusFound)
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+func (a *Api) HandlerGetMyPermissions(w http.ResponseWriter, r *http.Request) {
+	permissions, err := a.UserServiceConfig.GetMyPermissions(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(permissions)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", permissions, err)
+		http.Error(w, flux_errors.ErrInternal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerGetPermissions(w http.ResponseWriter, r *http.Request) {
+	permissions, err := a.UserServiceConfig.GetPermissions(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(permissions)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", permissions, err)
+		http.Error(w, flux_errors.ErrInternal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerSetRolePermissions(w http.ResponseWriter, r *http.Request) {
+	// decode the body
+	var request user_service.SetRolePermissionsRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	role, err := a.UserServiceConfig.SetRolePermissions(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(role)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", role, err)
+		http.Error(
+			w,
+			"permissions were set, but there was an error preparing response",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service/rating_service"
+)
+
+func (a *Api) HandlerGetContestRating(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestID, err := uuid.Parse(r.URL.Query().Get("contest_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the rated status
+	rating, err := a.RatingServiceConfig.GetContestRating(r.Context(), contestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(rating)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", rating, err.Error())
+		http.Error(
+			w, "cannot send contest rating, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerSetContestRated(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request rating_service.SetContestRatedRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// set the rated status
+	rating, err := a.RatingServiceConfig.SetContestRated(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(rating)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", rating, err.Error())
+		http.Error(w, "rated status set but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerFinalizeContestRatings(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	type params struct {
+		ContestID uuid.UUID `json:"contest_id"`
+	}
+	var request params
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// finalize
+	rating, err := a.RatingServiceConfig.FinalizeContestRatings(r.Context(), request.ContestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(rating)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", rating, err.Error())
+		http.Error(w, "ratings finalized but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerRecomputeRatings(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	type params struct {
+		ContestID uuid.UUID `json:"contest_id"`
+	}
+	var request params
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// recompute
+	rating, err := a.RatingServiceConfig.RecomputeRatings(r.Context(), request.ContestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(rating)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", rating, err.Error())
+		http.Error(w, "ratings recomputed but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerGetUserRating(w http.ResponseWriter, r *http.Request) {
+	// get the user name
+	userName := r.URL.Query().Get("user_name")
+	if userName == "" {
+		http.Error(w, "user_name is required", http.StatusBadRequest)
+		return
+	}
+
+	// get the rating
+	rating, err := a.RatingServiceConfig.GetUserRating(r.Context(), userName)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(rating)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", rating, err.Error())
+		http.Error(
+			w, "cannot send user rating, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
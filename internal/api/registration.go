@@ -0,0 +1,230 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+)
+
+func (a *Api) HandlerSetRegistrationSettings(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request contest_service.RegistrationSettings
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// set the settings
+	settings, err := a.ContestServiceConfig.SetRegistrationSettings(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(settings)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", settings, err.Error())
+		http.Error(w, "registration settings set but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerGetRegistrationSettings(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestID, err := uuid.Parse(r.URL.Query().Get("contest_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the settings
+	settings, err := a.ContestServiceConfig.GetRegistrationSettings(r.Context(), contestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(settings)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", settings, err.Error())
+		http.Error(
+			w, "cannot send registration settings, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerRegisterSelf(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	type params struct {
+		ContestID uuid.UUID `json:"contest_id"`
+	}
+	var request params
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// register
+	registration, err := a.ContestServiceConfig.RegisterSelf(r.Context(), request.ContestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(registration)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", registration, err.Error())
+		http.Error(w, "registration done but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusCreated, response)
+}
+
+func (a *Api) HandlerUnregisterSelf(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestID, err := uuid.Parse(r.URL.Query().Get("contest_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// unregister
+	err = a.ContestServiceConfig.UnregisterSelf(r.Context(), contestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("unregistered successfully"))
+}
+
+func (a *Api) HandlerGetRegistrationRequests(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestID, err := uuid.Parse(r.URL.Query().Get("contest_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the optional status filter
+	var status *string
+	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
+		status = &statusStr
+	}
+
+	// get the requests
+	requests, err := a.ContestServiceConfig.GetRegistrationRequests(r.Context(), contestID, status)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(requests)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", requests, err.Error())
+		http.Error(
+			w, "cannot send registration requests, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerReviewRegistrationRequest(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request contest_service.ReviewRegistrationRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// review
+	registration, err := a.ContestServiceConfig.ReviewRegistrationRequest(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(registration)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", registration, err.Error())
+		http.Error(w, "request reviewed but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerAddUsersToContest(w http.ResponseWriter, r *http.Request) {
+	// get the users from body
+	type params struct {
+		ContestID uuid.UUID `json:"contest_id"`
+		UserNames []string  `json:"user_names"`
+	}
+	var request params
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// add users
+	err = a.ContestServiceConfig.AddUsersToContest(
+		r.Context(),
+		request.ContestID,
+		request.UserNames,
+	)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("users added successfully"))
+}
+
+func (a *Api) HandlerRemoveUsersFromContest(w http.ResponseWriter, r *http.Request) {
+	// get the users from body
+	type params struct {
+		ContestID uuid.UUID `json:"contest_id"`
+		UserNames []string  `json:"user_names"`
+	}
+	var request params
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// remove users
+	err = a.ContestServiceConfig.RemoveUsersFromContest(
+		r.Context(),
+		request.ContestID,
+		request.UserNames,
+	)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("users removed successfully"))
+}
@@ -14,6 +14,8 @@ func (a *Api) HandlerResetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	request.IPAddress = a.clientIP(r)
+
 	// reset password
 	err = a.AuthServiceConfig.ResetPassword(
 		r.Context(),
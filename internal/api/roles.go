@@ -0,0 +1,129 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+func (a *Api) HandlerGetRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := a.UserServiceConfig.GetRoles(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(roles)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", roles, err)
+		http.Error(w, flux_errors.ErrInternal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerCreateRole(w http.ResponseWriter, r *http.Request) {
+	// decode the body
+	var request user_service.CreateRoleRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	role, err := a.UserServiceConfig.CreateRole(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(role)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", role, err)
+		http.Error(
+			w,
+			"role was created, but there was an error preparing response",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusCreated, response)
+}
+
+func (a *Api) HandlerGetRoleMembers(w http.ResponseWriter, r *http.Request) {
+	// decode the body
+	var request user_service.GetRoleMembersRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	members, err := a.UserServiceConfig.GetRoleMembers(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(members)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", members, err)
+		http.Error(w, flux_errors.ErrInternal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerGrantRole(w http.ResponseWriter, r *http.Request) {
+	// decode the body
+	var request user_service.RoleAssignmentRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	member, err := a.UserServiceConfig.GrantRole(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(member)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", member, err)
+		http.Error(
+			w,
+			"role was granted, but there was an error preparing response",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerRevokeRole(w http.ResponseWriter, r *http.Request) {
+	request := user_service.RoleAssignmentRequest{
+		UserName: r.URL.Query().Get("user_name"),
+		RoleName: r.URL.Query().Get("role_name"),
+	}
+
+	err := a.UserServiceConfig.RevokeRole(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("role revoked successfully"))
+}
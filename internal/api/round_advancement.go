@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service/tournament_service"
+)
+
+func (a *Api) HandlerGetAdvancementRule(w http.ResponseWriter, r *http.Request) {
+	// parse
+	tournamentID, err := uuid.Parse(r.URL.Query().Get("tournament_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	roundNumber, err := strconv.Atoi(r.URL.Query().Get("round_number"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the rule
+	rule, err := a.TournamentServiceConfig.GetAdvancementRule(
+		r.Context(),
+		tournamentID,
+		int32(roundNumber),
+	)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(rule)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", rule, err.Error())
+		http.Error(
+			w, "cannot send advancement rule, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerSetAdvancementRule(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request tournament_service.AdvancementRule
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// set the rule
+	rule, err := a.TournamentServiceConfig.SetAdvancementRule(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(rule)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", rule, err.Error())
+		http.Error(w, "advancement rule set but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerPreviewAdvancement(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request tournament_service.AdvanceRoundRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// evaluate the rule
+	preview, err := a.TournamentServiceConfig.PreviewAdvancement(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(preview)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", preview, err.Error())
+		http.Error(
+			w, "cannot send advancement preview, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerAdvanceRound(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request tournament_service.AdvanceRoundRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// advance the qualifiers
+	result, err := a.TournamentServiceConfig.AdvanceRound(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(result)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", result, err.Error())
+		http.Error(w, "round advanced but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
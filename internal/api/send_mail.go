@@ -12,7 +12,12 @@ func (a *Api) HandlerSignUpSendMail(w http.ResponseWriter, r *http.Request) {
 	userMail := queryParams.Get("email")
 
 	// call the service to verify the email to create a token
-	err := a.AuthServiceConfig.SendVerificationEmail(r.Context(), userMail, email.PurposeEmailSignUp)
+	err := a.AuthServiceConfig.SendVerificationEmail(
+		r.Context(),
+		userMail,
+		email.PurposeEmailSignUp,
+		a.clientIP(r),
+	)
 	if err != nil {
 		handlerError(err, w)
 		return
@@ -43,6 +48,7 @@ func (a *Api) HandlerResetPasswordSendMail(w http.ResponseWriter, r *http.Reques
 		r.Context(),
 		userName,
 		rollNo,
+		a.clientIP(r),
 	)
 	if err != nil {
 		handlerError(err, w)
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+)
+
+func (a *Api) HandlerGetMySessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := a.AuthServiceConfig.GetMySessions(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(sessions)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", sessions, err)
+		http.Error(w, flux_errors.ErrInternal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerRevokeMySession(w http.ResponseWriter, r *http.Request) {
+	// get the id
+	sessionID, err := uuid.Parse(r.URL.Query().Get("session_id"))
+	if err != nil {
+		http.Error(w, "invalid session id provided", http.StatusBadRequest)
+		return
+	}
+
+	err = a.AuthServiceConfig.RevokeMySession(r.Context(), sessionID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("session revoked successfully"))
+}
+
+func (a *Api) HandlerRevokeUserSessions(w http.ResponseWriter, r *http.Request) {
+	// get the user name
+	userName := r.URL.Query().Get("user_name")
+	if userName == "" {
+		http.Error(w, "user_name must be provided", http.StatusBadRequest)
+		return
+	}
+
+	sessionIDs, err := a.AuthServiceConfig.RevokeUserSessions(r.Context(), userName)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(sessionIDs)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", sessionIDs, err)
+		http.Error(
+			w,
+			"sessions were revoked, but there was an error preparing response",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service/problem_service"
+	"github.com/tcp_snm/flux/internal/service/submission_service"
+)
+
+func (a *Api) HandlerGetProblemSubtasks(w http.ResponseWriter, r *http.Request) {
+	// get problem id
+	problemID, err := strconv.Atoi(r.URL.Query().Get("problem_id"))
+	if err != nil {
+		http.Error(w, "invalid problem id, problem id must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	// get the subtasks
+	subtasks, err := a.ProblemServiceConfig.GetProblemSubtasks(r.Context(), int32(problemID))
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(subtasks)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", subtasks, err.Error())
+		http.Error(
+			w, "cannot send subtasks, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerSetProblemSubtasks(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request problem_service.SetSubtasksRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// set the subtasks
+	subtasks, err := a.ProblemServiceConfig.SetProblemSubtasks(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(subtasks)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", subtasks, err.Error())
+		http.Error(w, "subtasks set but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+// HandlerRecordSubtaskResults is called by the judge with the verdicts of an
+// evaluated submission
+func (a *Api) HandlerRecordSubtaskResults(w http.ResponseWriter, r *http.Request) {
+	// decode the body
+	var request submission_service.RecordSubtaskResultsRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	subtaskScore, err := a.SubmissionService.RecordSubtaskResults(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(subtaskScore)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", subtaskScore, err)
+		http.Error(
+			w,
+			"score was recorded, but there was an error preparing response",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service/team_service"
+)
+
+func (a *Api) HandlerCreateTeam(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request team_service.Team
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// create the team
+	team, err := a.TeamServiceConfig.CreateTeam(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(team)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", team, err.Error())
+		http.Error(w, "team created but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusCreated, response)
+}
+
+func (a *Api) HandlerGetTeam(w http.ResponseWriter, r *http.Request) {
+	// get the team id
+	teamID, err := uuid.Parse(r.URL.Query().Get("team_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the team
+	team, err := a.TeamServiceConfig.GetTeamByID(r.Context(), teamID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(team)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", team, err.Error())
+		http.Error(
+			w, "cannot send team, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerGetUserTeams(w http.ResponseWriter, r *http.Request) {
+	// get the teams
+	teams, err := a.TeamServiceConfig.GetUserTeams(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(teams)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", teams, err.Error())
+		http.Error(
+			w, "cannot send teams, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerDeleteTeam(w http.ResponseWriter, r *http.Request) {
+	// get the team id
+	teamID, err := uuid.Parse(r.URL.Query().Get("team_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// delete the team
+	err = a.TeamServiceConfig.DeleteTeam(r.Context(), teamID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("team deleted successfully"))
+}
+
+func (a *Api) HandlerInviteToTeam(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request team_service.InviteUserRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// invite
+	invitation, err := a.TeamServiceConfig.InviteUser(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(invitation)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", invitation, err.Error())
+		http.Error(w, "user invited but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusCreated, response)
+}
+
+func (a *Api) HandlerGetTeamInvitations(w http.ResponseWriter, r *http.Request) {
+	// get the invitations
+	invitations, err := a.TeamServiceConfig.GetUserInvitations(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(invitations)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", invitations, err.Error())
+		http.Error(
+			w, "cannot send invitations, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerRespondToTeamInvitation(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request team_service.RespondToInvitationRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// respond
+	err = a.TeamServiceConfig.RespondToInvitation(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("responded to invitation successfully"))
+}
+
+func (a *Api) HandlerRemoveTeamMember(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	type params struct {
+		TeamID   uuid.UUID `json:"team_id"`
+		UserName string    `json:"user_name"`
+	}
+	var request params
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// remove the member
+	err = a.TeamServiceConfig.RemoveTeamMember(r.Context(), request.TeamID, request.UserName)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("member removed successfully"))
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service/tournament_service"
+)
+
+func (a *Api) HandlerGetTournamentStandings(w http.ResponseWriter, r *http.Request) {
+	// parse
+	tournamentID, err := uuid.Parse(r.URL.Query().Get("tournament_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the standings
+	standings, err := a.TournamentServiceConfig.GetTournamentStandings(r.Context(), tournamentID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(standings)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", standings, err.Error())
+		http.Error(
+			w, "cannot send standings, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerSetRoundScoring(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request tournament_service.RoundScoring
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// set the scoring
+	scoring, err := a.TournamentServiceConfig.SetRoundScoring(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(scoring)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", scoring, err.Error())
+		http.Error(w, "round scoring set but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service/auth_service"
+)
+
+func (a *Api) HandlerGetTwoFactorStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := a.AuthServiceConfig.GetTwoFactorStatus(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(status)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", status, err)
+		http.Error(w, flux_errors.ErrInternal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerStartTOTPEnrolment(w http.ResponseWriter, r *http.Request) {
+	enrolment, err := a.AuthServiceConfig.StartTOTPEnrolment(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal, never log the secret
+	response, err := json.Marshal(enrolment)
+	if err != nil {
+		log.Errorf("cannot marshal totp enrolment, %v", err)
+		http.Error(w, flux_errors.ErrInternal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusCreated, response)
+}
+
+func (a *Api) HandlerConfirmTOTPEnrolment(w http.ResponseWriter, r *http.Request) {
+	// decode the body
+	var request auth_service.ConfirmTOTPRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	recoveryCodes, err := a.AuthServiceConfig.ConfirmTOTPEnrolment(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithRecoveryCodes(w, recoveryCodes)
+}
+
+func (a *Api) HandlerDisableTOTP(w http.ResponseWriter, r *http.Request) {
+	// decode the body
+	var request auth_service.TwoFactorRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = a.AuthServiceConfig.DisableTOTP(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("two factor authentication disabled successfully"))
+}
+
+func (a *Api) HandlerRegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	// decode the body
+	var request auth_service.TwoFactorRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	recoveryCodes, err := a.AuthServiceConfig.RegenerateRecoveryCodes(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithRecoveryCodes(w, recoveryCodes)
+}
+
+func respondWithRecoveryCodes(w http.ResponseWriter, recoveryCodes auth_service.RecoveryCodes) {
+	// marshal, the codes cannot be shown again so never log them
+	response, err := json.Marshal(recoveryCodes)
+	if err != nil {
+		log.Errorf("cannot marshal recovery codes, %v", err)
+		http.Error(
+			w,
+			"recovery codes were created, but there was an error preparing response",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service/tournament_service"
+)
+
+func (a *Api) HandlerUpdateTournament(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request tournament_service.UpdateTournamentRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// update the tournament
+	tournament, err := a.TournamentServiceConfig.UpdateTournament(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(tournament)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", tournament, err.Error())
+		http.Error(w, "tournament updated but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerPublishTournament(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request tournament_service.PublishTournamentRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// publish the tournament
+	tournament, err := a.TournamentServiceConfig.SetTournamentPublished(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(tournament)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", tournament, err.Error())
+		http.Error(w, "tournament published but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerUpdateTournamentRound(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request tournament_service.UpdateTournamentRoundRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// update the round
+	round, err := a.TournamentServiceConfig.UpdateTournamentRound(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(round)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", round, err.Error())
+		http.Error(w, "round updated but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerPublishTournamentRound(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request tournament_service.PublishTournamentRoundRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// publish the round
+	round, err := a.TournamentServiceConfig.SetTournamentRoundPublished(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(round)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", round, err.Error())
+		http.Error(w, "round published but cannot prepare response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerReorderTournamentRounds(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request tournament_service.ReorderTournamentRoundsRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// reorder the rounds
+	err = a.TournamentServiceConfig.ReorderTournamentRounds(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("rounds reordered successfully"))
+}
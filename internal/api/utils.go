@@ -3,7 +3,9 @@ package api
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"net/netip"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
@@ -50,10 +52,14 @@ func handlerError(err error, w http.ResponseWriter) {
 			fallthrough
 		case errors.Is(err, flux_errors.ErrInvalidRequestCredentials):
 			fallthrough
+		case errors.Is(err, flux_errors.ErrTwoFactorRequired):
+			fallthrough
 		case errors.Is(err, flux_errors.ErrUnAuthorized):
 			fallthrough
 		case errors.Is(err, flux_errors.ErrInvalidUserCredentials):
 			statusCode = http.StatusUnauthorized
+		case errors.Is(err, flux_errors.ErrTooManyAttempts):
+			statusCode = http.StatusTooManyRequests
 		case errors.Is(err, flux_errors.ErrEmailServiceStopped):
 			fallthrough
 		default:
@@ -64,3 +70,80 @@ func handlerError(err error, w http.ResponseWriter) {
 		return
 	}
 }
+
+// clientIP returns the address the request came from, without the port
+func (a *Api) clientIP(r *http.Request) string {
+	return ClientIP(r.RemoteAddr, r.Header.Values("X-Forwarded-For"), a.TrustedProxies)
+}
+
+// ClientIP returns the address of the client behind the trusted proxies. The
+// forwarded addresses are only taken from a trusted peer, walking from the
+// nearest hop until an address that is not a trusted proxy, as the farther
+// ones may be set by the client itself.
+func ClientIP(remoteAddr string, forwardedFor []string, trusted []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !isTrustedProxy(addr, trusted) {
+		return host
+	}
+
+	// the nearest hop is appended last
+	var hops []string
+	for _, header := range forwardedFor {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// cannot trust anything farther than a malformed hop
+			return addr.String()
+		}
+		addr = hop.Unmap()
+		if !isTrustedProxy(addr, trusted) {
+			return addr.String()
+		}
+	}
+
+	// all the hops are proxies, the farthest one is the client
+	return addr.String()
+}
+
+func isTrustedProxy(addr netip.Addr, trusted []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTrustedProxies parses comma separated addresses and cidr ranges of the
+// proxies allowed to forward the address of the client
+func ParseTrustedProxies(value string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q, %w", entry, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q, %w", entry, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
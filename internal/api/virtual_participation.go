@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+func (a *Api) HandlerStartVirtualParticipation(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	type params struct {
+		ContestID uuid.UUID `json:"contest_id"`
+	}
+	var request params
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// start the participation
+	participation, err := a.ContestServiceConfig.StartVirtualParticipation(
+		r.Context(),
+		request.ContestID,
+	)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(participation)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", participation, err.Error())
+		http.Error(
+			w, "virtual participation started but error in preparing response",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusCreated, response)
+}
+
+func (a *Api) HandlerGetVirtualParticipation(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestIDStr := r.URL.Query().Get("contest_id")
+
+	// parse
+	contestID, err := uuid.Parse(contestIDStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the participation
+	participation, err := a.ContestServiceConfig.GetVirtualParticipation(r.Context(), contestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(participation)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", participation, err.Error())
+		http.Error(
+			w, "cannot send virtual participation, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
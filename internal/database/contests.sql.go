@@ -336,18 +336,32 @@ func (q *Queries) GetUserRegisteredContests(ctx context.Context, userID uuid.UUI
 const isUserRegisteredInContest = `-- name: IsUserRegisteredInContest :one
 SELECT EXISTS(
     SELECT contest_id, user_id FROM
-     contest_registered_users WHERE contest_id=$1 AND user_id=$1
+     contest_registered_users WHERE contest_id=$1 AND user_id=$2
 )
 `
 
-func (q *Queries) IsUserRegisteredInContest(ctx context.Context, contestID uuid.UUID) (bool, error) {
-	row := q.db.QueryRow(ctx, isUserRegisteredInContest, contestID)
+type IsUserRegisteredInContestParams struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	UserID    uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) IsUserRegisteredInContest(ctx context.Context, arg IsUserRegisteredInContestParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isUserRegisteredInContest, arg.ContestID, arg.UserID)
 	var exists bool
 	err := row.Scan(&exists)
 	return exists, err
 }
 
-const registerUserToContest = `-- name: RegisterUserToContest :one
+const publishContest = `-- name: PublishContest :exec
+UPDATE contests SET is_published=true WHERE id=$1
+`
+
+func (q *Queries) PublishContest(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, publishContest, id)
+	return err
+}
+
+const registerUserToContest = `-- name: RegisterUserToContest :execrows
 INSERT INTO contest_registered_users (
     user_id,
     contest_id
@@ -355,7 +369,7 @@ INSERT INTO contest_registered_users (
     $1,
     $2
 )
-RETURNING user_id, contest_id
+ON CONFLICT (user_id, contest_id) DO NOTHING
 `
 
 type RegisterUserToContestParams struct {
@@ -363,11 +377,12 @@ type RegisterUserToContestParams struct {
 	ContestID uuid.UUID `json:"contest_id"`
 }
 
-func (q *Queries) RegisterUserToContest(ctx context.Context, arg RegisterUserToContestParams) (ContestRegisteredUser, error) {
-	row := q.db.QueryRow(ctx, registerUserToContest, arg.UserID, arg.ContestID)
-	var i ContestRegisteredUser
-	err := row.Scan(&i.UserID, &i.ContestID)
-	return i, err
+func (q *Queries) RegisterUserToContest(ctx context.Context, arg RegisterUserToContestParams) (int64, error) {
+	result, err := q.db.Exec(ctx, registerUserToContest, arg.UserID, arg.ContestID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const unRegisterContestUsers = `-- name: UnRegisterContestUsers :exec
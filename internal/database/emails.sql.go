@@ -7,8 +7,38 @@ package database
 
 import (
 	"context"
+
+	"github.com/google/uuid"
 )
 
+const getContestParticipantMailIDs = `-- name: GetContestParticipantMailIDs :many
+SELECT email FROM users u WHERE id IN (
+    SELECT user_id FROM contest_registered_users WHERE contest_id=$1
+    UNION
+    SELECT user_id FROM contest_team_members WHERE contest_id=$1
+) AND COALESCE((SELECT contest_emails FROM user_notification_settings s WHERE s.user_id = u.id), TRUE)
+`
+
+func (q *Queries) GetContestParticipantMailIDs(ctx context.Context, contestID uuid.UUID) ([]string, error) {
+	rows, err := q.db.Query(ctx, getContestParticipantMailIDs, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		items = append(items, email)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getManagerMailIDs = `-- name: GetManagerMailIDs :many
 SELECT email FROM users u JOIN user_roles ur ON u.id = ur.user_id WHERE role_name=$1
 `
@@ -12,21 +12,53 @@ import (
 	"github.com/google/uuid"
 )
 
+const addLockChild = `-- name: AddLockChild :exec
+INSERT INTO lock_children (parent_id, child_id)
+VALUES ($1, $2)
+`
+
+type AddLockChildParams struct {
+	ParentID uuid.UUID `json:"parent_id"`
+	ChildID  uuid.UUID `json:"child_id"`
+}
+
+func (q *Queries) AddLockChild(ctx context.Context, arg AddLockChildParams) error {
+	_, err := q.db.Exec(ctx, addLockChild, arg.ParentID, arg.ChildID)
+	return err
+}
+
+const addLockContestDependency = `-- name: AddLockContestDependency :exec
+INSERT INTO lock_contest_dependencies (lock_id, contest_id)
+VALUES ($1, $2)
+`
+
+type AddLockContestDependencyParams struct {
+	LockID    uuid.UUID `json:"lock_id"`
+	ContestID uuid.UUID `json:"contest_id"`
+}
+
+func (q *Queries) AddLockContestDependency(ctx context.Context, arg AddLockContestDependencyParams) error {
+	_, err := q.db.Exec(ctx, addLockContestDependency, arg.LockID, arg.ContestID)
+	return err
+}
+
 const createLock = `-- name: CreateLock :one
 INSERT INTO locks (
     name,
     created_by,
     description,
     lock_type,
-    timeout
+    timeout,
+    operator
 ) VALUES (
     $1, -- name
     $2, -- created_by
     $3, -- description
-    $4, -- lock_type: either timer or manual
-    $5  -- timeout: null only if manual
+    $4, -- lock_type: timer, manual or composite
+    $5, -- timeout: not null only if timer
+    $6  -- operator: not null only if composite
 )
-RETURNING id, name, created_by, created_at, description, access, lock_type, timeout
+RETURNING id, name, created_by, created_at, description, access, lock_type, timeout, released_at, operator
 `
 
 type CreateLockParams struct {
@@ -35,6 +67,7 @@ type CreateLockParams struct {
 	Description string     `json:"description"`
 	LockType    LockType   `json:"lock_type"`
 	Timeout     *time.Time `json:"timeout"`
+	Operator    *string    `json:"operator"`
 }
 
 func (q *Queries) CreateLock(ctx context.Context, arg CreateLockParams) (Lock, error) {
@@ -44,6 +77,7 @@ func (q *Queries) CreateLock(ctx context.Context, arg CreateLockParams) (Lock, e
 		arg.Description,
 		arg.LockType,
 		arg.Timeout,
+		arg.Operator,
 	)
 	var i Lock
 	err := row.Scan(
@@ -55,6 +89,8 @@ func (q *Queries) CreateLock(ctx context.Context, arg CreateLockParams) (Lock, e
 		&i.Access,
 		&i.LockType,
 		&i.Timeout,
+		&i.ReleasedAt,
+		&i.Operator,
 	)
 	return i, err
 }
@@ -69,8 +105,38 @@ func (q *Queries) DeleteLockById(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const getContestEndTimes = `-- name: GetContestEndTimes :many
+SELECT id, end_time FROM contests
+WHERE id = ANY($1::uuid[])
+`
+
+type GetContestEndTimesRow struct {
+	ID      uuid.UUID `json:"id"`
+	EndTime time.Time `json:"end_time"`
+}
+
+func (q *Queries) GetContestEndTimes(ctx context.Context, contestIds []uuid.UUID) ([]GetContestEndTimesRow, error) {
+	rows, err := q.db.Query(ctx, getContestEndTimes, contestIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetContestEndTimesRow
+	for rows.Next() {
+		var i GetContestEndTimesRow
+		if err := rows.Scan(&i.ID, &i.EndTime); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getLockById = `-- name: GetLockById :one
-SELECT id, name, created_by, created_at, description, access, lock_type, timeout FROM locks WHERE id=$1
+SELECT id, name, created_by, created_at, description, access, lock_type, timeout, released_at, operator FROM locks WHERE id=$1
 `
 
 func (q *Queries) GetLockById(ctx context.Context, groupD uuid.UUID) (Lock, error) {
@@ -85,12 +151,82 @@ func (q *Queries) GetLockById(ctx context.Context, groupD uuid.UUID) (Lock, erro
 		&i.Access,
 		&i.LockType,
 		&i.Timeout,
+		&i.ReleasedAt,
+		&i.Operator,
 	)
 	return i, err
 }
 
+const getLockChildren = `-- name: GetLockChildren :many
+SELECT l.id, l.name, l.created_by, l.created_at, l.description, l.access, l.lock_type, l.timeout, l.released_at, l.operator FROM locks l
+JOIN lock_children lc ON l.id = lc.child_id
+WHERE lc.parent_id = $1
+`
+
+func (q *Queries) GetLockChildren(ctx context.Context, parentID uuid.UUID) ([]Lock, error) {
+	rows, err := q.db.Query(ctx, getLockChildren, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Lock
+	for rows.Next() {
+		var i Lock
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.Description,
+			&i.Access,
+			&i.LockType,
+			&i.Timeout,
+			&i.ReleasedAt,
+			&i.Operator,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLockContestDependencies = `-- name: GetLockContestDependencies :many
+SELECT c.id, c.end_time FROM lock_contest_dependencies d
+JOIN contests c ON d.contest_id = c.id
+WHERE d.lock_id = $1
+`
+
+type GetLockContestDependenciesRow struct {
+	ID      uuid.UUID `json:"id"`
+	EndTime time.Time `json:"end_time"`
+}
+
+func (q *Queries) GetLockContestDependencies(ctx context.Context, lockID uuid.UUID) ([]GetLockContestDependenciesRow, error) {
+	rows, err := q.db.Query(ctx, getLockContestDependencies, lockID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLockContestDependenciesRow
+	for rows.Next() {
+		var i GetLockContestDependenciesRow
+		if err := rows.Scan(&i.ID, &i.EndTime); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getLocksByFilter = `-- name: GetLocksByFilter :many
-SELECT id, name, created_by, created_at, description, access, lock_type, timeout FROM locks
+SELECT id, name, created_by, created_at, description, access, lock_type, timeout, released_at, operator FROM locks
 WHERE
     (
         $1::text IS NULL OR
@@ -134,6 +270,8 @@ func (q *Queries) GetLocksByFilter(ctx context.Context, arg GetLocksByFilterPara
 			&i.Access,
 			&i.LockType,
 			&i.Timeout,
+			&i.ReleasedAt,
+			&i.Operator,
 		); err != nil {
 			return nil, err
 		}
@@ -145,6 +283,30 @@ func (q *Queries) GetLocksByFilter(ctx context.Context, arg GetLocksByFilterPara
 	return items, nil
 }
 
+const releaseLock = `-- name: ReleaseLock :one
+UPDATE locks SET released_at = NOW()
+WHERE id = $1
+RETURNING id, name, created_by, created_at, description, access, lock_type, timeout, released_at, operator
+`
+
+func (q *Queries) ReleaseLock(ctx context.Context, id uuid.UUID) (Lock, error) {
+	row := q.db.QueryRow(ctx, releaseLock, id)
+	var i Lock
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.Description,
+		&i.Access,
+		&i.LockType,
+		&i.Timeout,
+		&i.ReleasedAt,
+		&i.Operator,
+	)
+	return i, err
+}
+
 const updateLockDetails = `-- name: UpdateLockDetails :one
 UPDATE locks
 SET
@@ -153,7 +315,7 @@ SET
     description = $4
 WHERE
     id = $1
-RETURNING id, name, created_by, created_at, description, access, lock_type, timeout
+RETURNING id, name, created_by, created_at, description, access, lock_type, timeout, released_at, operator
 `
 
 type UpdateLockDetailsParams struct {
@@ -180,6 +342,8 @@ func (q *Queries) UpdateLockDetails(ctx context.Context, arg UpdateLockDetailsPa
 		&i.Access,
 		&i.LockType,
 		&i.Timeout,
+		&i.ReleasedAt,
+		&i.Operator,
 	)
 	return i, err
 }
@@ -13,11 +13,270 @@ import (
 	"github.com/google/uuid"
 )
 
+type AdvancementRule string
+
+const (
+	AdvancementRuleTopOverall     AdvancementRule = "top_overall"
+	AdvancementRuleTopPerContest  AdvancementRule = "top_per_contest"
+	AdvancementRuleScoreThreshold AdvancementRule = "score_threshold"
+	AdvancementRuleManual         AdvancementRule = "manual"
+)
+
+func (e *AdvancementRule) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AdvancementRule(s)
+	case string:
+		*e = AdvancementRule(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AdvancementRule: %T", src)
+	}
+	return nil
+}
+
+type NullAdvancementRule struct {
+	AdvancementRule AdvancementRule `json:"advancement_rule"`
+	Valid           bool            `json:"valid"` // Valid is true if AdvancementRule is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAdvancementRule) Scan(value interface{}) error {
+	if value == nil {
+		ns.AdvancementRule, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AdvancementRule.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAdvancementRule) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AdvancementRule), nil
+}
+
+type BracketMatchStatus string
+
+const (
+	BracketMatchStatusPending   BracketMatchStatus = "pending"
+	BracketMatchStatusScheduled BracketMatchStatus = "scheduled"
+	BracketMatchStatusFinished  BracketMatchStatus = "finished"
+)
+
+func (e *BracketMatchStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = BracketMatchStatus(s)
+	case string:
+		*e = BracketMatchStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for BracketMatchStatus: %T", src)
+	}
+	return nil
+}
+
+type NullBracketMatchStatus struct {
+	BracketMatchStatus BracketMatchStatus `json:"bracket_match_status"`
+	Valid              bool               `json:"valid"` // Valid is true if BracketMatchStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullBracketMatchStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.BracketMatchStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.BracketMatchStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullBracketMatchStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.BracketMatchStatus), nil
+}
+
+type BracketSeeding string
+
+const (
+	BracketSeedingRating BracketSeeding = "rating"
+	BracketSeedingManual BracketSeeding = "manual"
+)
+
+func (e *BracketSeeding) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = BracketSeeding(s)
+	case string:
+		*e = BracketSeeding(s)
+	default:
+		return fmt.Errorf("unsupported scan type for BracketSeeding: %T", src)
+	}
+	return nil
+}
+
+type NullBracketSeeding struct {
+	BracketSeeding BracketSeeding `json:"bracket_seeding"`
+	Valid          bool           `json:"valid"` // Valid is true if BracketSeeding is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullBracketSeeding) Scan(value interface{}) error {
+	if value == nil {
+		ns.BracketSeeding, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.BracketSeeding.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullBracketSeeding) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.BracketSeeding), nil
+}
+
+type LifecycleEvent string
+
+const (
+	LifecycleEventContestStart LifecycleEvent = "contest_start"
+	LifecycleEventContestEnd   LifecycleEvent = "contest_end"
+	LifecycleEventLockTimeout  LifecycleEvent = "lock_timeout"
+)
+
+func (e *LifecycleEvent) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LifecycleEvent(s)
+	case string:
+		*e = LifecycleEvent(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LifecycleEvent: %T", src)
+	}
+	return nil
+}
+
+type NullLifecycleEvent struct {
+	LifecycleEvent LifecycleEvent `json:"lifecycle_event"`
+	Valid          bool           `json:"valid"` // Valid is true if LifecycleEvent is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLifecycleEvent) Scan(value interface{}) error {
+	if value == nil {
+		ns.LifecycleEvent, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LifecycleEvent.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLifecycleEvent) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LifecycleEvent), nil
+}
+
+type LifecycleJobStatus string
+
+const (
+	LifecycleJobStatusPending LifecycleJobStatus = "pending"
+	LifecycleJobStatusRunning LifecycleJobStatus = "running"
+	LifecycleJobStatusDone    LifecycleJobStatus = "done"
+	LifecycleJobStatusFailed  LifecycleJobStatus = "failed"
+)
+
+func (e *LifecycleJobStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LifecycleJobStatus(s)
+	case string:
+		*e = LifecycleJobStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LifecycleJobStatus: %T", src)
+	}
+	return nil
+}
+
+type NullLifecycleJobStatus struct {
+	LifecycleJobStatus LifecycleJobStatus `json:"lifecycle_job_status"`
+	Valid              bool               `json:"valid"` // Valid is true if LifecycleJobStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLifecycleJobStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.LifecycleJobStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LifecycleJobStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLifecycleJobStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LifecycleJobStatus), nil
+}
+
+type LockGrantPermission string
+
+const (
+	LockGrantPermissionView LockGrantPermission = "view"
+	LockGrantPermissionEdit LockGrantPermission = "edit"
+)
+
+func (e *LockGrantPermission) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LockGrantPermission(s)
+	case string:
+		*e = LockGrantPermission(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LockGrantPermission: %T", src)
+	}
+	return nil
+}
+
+type NullLockGrantPermission struct {
+	LockGrantPermission LockGrantPermission `json:"lock_grant_permission"`
+	Valid               bool                `json:"valid"` // Valid is true if LockGrantPermission is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLockGrantPermission) Scan(value interface{}) error {
+	if value == nil {
+		ns.LockGrantPermission, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LockGrantPermission.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLockGrantPermission) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LockGrantPermission), nil
+}
+
 type LockType string
 
 const (
-	LockTypeManual LockType = "manual"
-	LockTypeTimer  LockType = "timer"
+	LockTypeManual    LockType = "manual"
+	LockTypeTimer     LockType = "timer"
+	LockTypeComposite LockType = "composite"
 )
 
 func (e *LockType) Scan(src interface{}) error {
@@ -55,6 +314,70 @@ func (ns NullLockType) Value() (driver.Value, error) {
 	return string(ns.LockType), nil
 }
 
+type RegistrationStatus string
+
+const (
+	RegistrationStatusPending    RegistrationStatus = "pending"
+	RegistrationStatusWaitlisted RegistrationStatus = "waitlisted"
+	RegistrationStatusRejected   RegistrationStatus = "rejected"
+)
+
+func (e *RegistrationStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = RegistrationStatus(s)
+	case string:
+		*e = RegistrationStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for RegistrationStatus: %T", src)
+	}
+	return nil
+}
+
+type NullRegistrationStatus struct {
+	RegistrationStatus RegistrationStatus `json:"registration_status"`
+	Valid              bool               `json:"valid"` // Valid is true if RegistrationStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullRegistrationStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.RegistrationStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.RegistrationStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullRegistrationStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.RegistrationStatus), nil
+}
+
+type AuditLog struct {
+	ID            uuid.UUID        `json:"id"`
+	ActorID       *uuid.UUID       `json:"actor_id"`
+	ActorUserName string           `json:"actor_user_name"`
+	Action        string           `json:"action"`
+	EntityType    string           `json:"entity_type"`
+	EntityID      string           `json:"entity_id"`
+	Before        *json.RawMessage `json:"before"`
+	After         *json.RawMessage `json:"after"`
+	RequestID     *string          `json:"request_id"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+type AuthAttempt struct {
+	Action        string     `json:"action"`
+	Subject       string     `json:"subject"`
+	Attempts      int32      `json:"attempts"`
+	LastAttemptAt time.Time  `json:"last_attempt_at"`
+	BlockedUntil  *time.Time `json:"blocked_until"`
+}
+
 type Bot struct {
 	Name      string          `json:"name"`
 	Platform  string          `json:"platform"`
@@ -63,6 +386,28 @@ type Bot struct {
 	UpdatedAt time.Time       `json:"updated_at"`
 }
 
+type BracketMatch struct {
+	ID           uuid.UUID          `json:"id"`
+	TournamentID uuid.UUID          `json:"tournament_id"`
+	RoundNumber  int32              `json:"round_number"`
+	MatchNumber  int32              `json:"match_number"`
+	Player1ID    *uuid.UUID         `json:"player1_id"`
+	Player2ID    *uuid.UUID         `json:"player2_id"`
+	ContestID    *uuid.UUID         `json:"contest_id"`
+	Player1Score *int32             `json:"player1_score"`
+	Player2Score *int32             `json:"player2_score"`
+	WinnerID     *uuid.UUID         `json:"winner_id"`
+	Status       BracketMatchStatus `json:"status"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+type BracketParticipant struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	Seed         int32     `json:"seed"`
+}
+
 type CfSubmission struct {
 	CfSubID             int64     `json:"cf_sub_id"`
 	SubmissionID        uuid.UUID `json:"submission_id"`
@@ -71,6 +416,20 @@ type CfSubmission struct {
 	PassedTestCount     int32     `json:"passed_test_count"`
 }
 
+type Clarification struct {
+	ID          uuid.UUID  `json:"id"`
+	ContestID   uuid.UUID  `json:"contest_id"`
+	ProblemID   *int32     `json:"problem_id"`
+	AskedBy     uuid.UUID  `json:"asked_by"`
+	Question    string     `json:"question"`
+	Answer      *string    `json:"answer"`
+	AnsweredBy  *uuid.UUID `json:"answered_by"`
+	IsBroadcast bool       `json:"is_broadcast"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	AnsweredAt  *time.Time `json:"answered_at"`
+}
+
 type Contest struct {
 	ID          uuid.UUID  `json:"id"`
 	Title       string     `json:"title"`
@@ -83,6 +442,33 @@ type Contest struct {
 	LockID      *uuid.UUID `json:"lock_id"`
 }
 
+type ContestFreeze struct {
+	ContestID     uuid.UUID  `json:"contest_id"`
+	FreezeMinutes int32      `json:"freeze_minutes"`
+	UnfrozenAt    *time.Time `json:"unfrozen_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type ContestFreezeReveal struct {
+	ContestID  uuid.UUID `json:"contest_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	ProblemID  int32     `json:"problem_id"`
+	RevealedAt time.Time `json:"revealed_at"`
+}
+
+type ContestLifecycleJob struct {
+	ID        uuid.UUID          `json:"id"`
+	ContestID uuid.UUID          `json:"contest_id"`
+	Event     LifecycleEvent     `json:"event"`
+	RunAt     time.Time          `json:"run_at"`
+	Status    LifecycleJobStatus `json:"status"`
+	Attempts  int32              `json:"attempts"`
+	LastError *string            `json:"last_error"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
 type ContestProblem struct {
 	ContestID uuid.UUID `json:"contest_id"`
 	ProblemID int32     `json:"problem_id"`
@@ -94,6 +480,50 @@ type ContestRegisteredUser struct {
 	ContestID uuid.UUID `json:"contest_id"`
 }
 
+type ContestRegistrationRequest struct {
+	ContestID   uuid.UUID          `json:"contest_id"`
+	UserID      uuid.UUID          `json:"user_id"`
+	Status      RegistrationStatus `json:"status"`
+	RequestedAt time.Time          `json:"requested_at"`
+}
+
+type ContestRegistrationSetting struct {
+	ContestID         uuid.UUID `json:"contest_id"`
+	RegistrationStart time.Time `json:"registration_start"`
+	RegistrationEnd   time.Time `json:"registration_end"`
+	Capacity          *int32    `json:"capacity"`
+	RequiresApproval  bool      `json:"requires_approval"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type ContestRating struct {
+	ContestID   uuid.UUID  `json:"contest_id"`
+	FinalizedAt *time.Time `json:"finalized_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type ContestReminder struct {
+	ContestID     uuid.UUID  `json:"contest_id"`
+	OffsetMinutes int32      `json:"offset_minutes"`
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type ContestTeamMember struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	TeamID    uuid.UUID `json:"team_id"`
+	UserID    uuid.UUID `json:"user_id"`
+}
+
+type ContestTeamSetting struct {
+	ContestID   uuid.UUID `json:"contest_id"`
+	MaxTeamSize int32     `json:"max_team_size"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
 type Lock struct {
 	ID          uuid.UUID  `json:"id"`
 	Name        string     `json:"name"`
@@ -103,6 +533,54 @@ type Lock struct {
 	Access      string     `json:"access"`
 	LockType    LockType   `json:"lock_type"`
 	Timeout     *time.Time `json:"timeout"`
+	ReleasedAt  *time.Time `json:"released_at"`
+	Operator    *string    `json:"operator"`
+}
+
+type LockChild struct {
+	ParentID uuid.UUID `json:"parent_id"`
+	ChildID  uuid.UUID `json:"child_id"`
+}
+
+type LockContestDependency struct {
+	LockID    uuid.UUID `json:"lock_id"`
+	ContestID uuid.UUID `json:"contest_id"`
+}
+
+type LockGrant struct {
+	ID         uuid.UUID           `json:"id"`
+	LockID     uuid.UUID           `json:"lock_id"`
+	UserID     *uuid.UUID          `json:"user_id"`
+	TeamID     *uuid.UUID          `json:"team_id"`
+	Permission LockGrantPermission `json:"permission"`
+	GrantedBy  uuid.UUID           `json:"granted_by"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
+
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type OidcLoginState struct {
+	State            string    `json:"state"`
+	CodeVerifier     string    `json:"code_verifier"`
+	Nonce            string    `json:"nonce"`
+	RememberForMonth bool      `json:"remember_for_month"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+type PersonalAccessToken struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Name        string     `json:"name"`
+	HashedToken string     `json:"hashed_token"`
+	Scopes      []string   `json:"scopes"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	RevokedAt   *time.Time `json:"revoked_at"`
 }
 
 type Problem struct {
@@ -117,8 +595,44 @@ type Problem struct {
 	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
+type ProblemSubtask struct {
+	ProblemID int32   `json:"problem_id"`
+	SubtaskID int32   `json:"subtask_id"`
+	Points    int32   `json:"points"`
+	Testcases []int32 `json:"testcases"`
+	DependsOn []int32 `json:"depends_on"`
+}
+
+type RatingChange struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	OldRating int32     `json:"old_rating"`
+	NewRating int32     `json:"new_rating"`
+	Rank      int32     `json:"rank"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Role struct {
-	RoleName string `json:"role_name"`
+	RoleName    string     `json:"role_name"`
+	Description string     `json:"description"`
+	CreatedBy   *uuid.UUID `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type RolePermission struct {
+	RoleName   string `json:"role_name"`
+	Permission string `json:"permission"`
+}
+
+type RoundAdvancement struct {
+	RoundID       uuid.UUID       `json:"round_id"`
+	Rule          AdvancementRule `json:"rule"`
+	TopK          *int32          `json:"top_k"`
+	MinScore      *int32          `json:"min_score"`
+	ManualUserIds []uuid.UUID     `json:"manual_user_ids"`
+	AdvancedAt    *time.Time      `json:"advanced_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
 }
 
 type Solved struct {
@@ -142,14 +656,43 @@ type StandardProblemDatum struct {
 }
 
 type Submission struct {
-	ID          uuid.UUID       `json:"id"`
-	SubmittedBy uuid.UUID       `json:"submitted_by"`
-	ContestID   *uuid.UUID      `json:"contest_id"`
-	ProblemID   int32           `json:"problem_id"`
-	Solution    json.RawMessage `json:"solution"`
-	State       string          `json:"state"`
-	SubmittedAt time.Time       `json:"submitted_at"`
-	UpdatedAt   time.Time       `json:"updated_at"`
+	ID                     uuid.UUID       `json:"id"`
+	SubmittedBy            uuid.UUID       `json:"submitted_by"`
+	ContestID              *uuid.UUID      `json:"contest_id"`
+	ProblemID              int32           `json:"problem_id"`
+	Solution               json.RawMessage `json:"solution"`
+	State                  string          `json:"state"`
+	SubmittedAt            time.Time       `json:"submitted_at"`
+	UpdatedAt              time.Time       `json:"updated_at"`
+	VirtualParticipationID *uuid.UUID      `json:"virtual_participation_id"`
+	Score                  *int32          `json:"score"`
+}
+
+type SubmissionSubtaskResult struct {
+	SubmissionID uuid.UUID `json:"submission_id"`
+	SubtaskID    int32     `json:"subtask_id"`
+	Passed       bool      `json:"passed"`
+}
+
+type Team struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CaptainID uuid.UUID `json:"captain_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type TeamInvitation struct {
+	TeamID    uuid.UUID `json:"team_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	InvitedBy uuid.UUID `json:"invited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TeamMember struct {
+	TeamID   uuid.UUID `json:"team_id"`
+	UserID   uuid.UUID `json:"user_id"`
+	JoinedAt time.Time `json:"joined_at"`
 }
 
 type Token struct {
@@ -171,6 +714,18 @@ type Tournament struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+type TournamentBracket struct {
+	TournamentID         uuid.UUID      `json:"tournament_id"`
+	Seeding              BracketSeeding `json:"seeding"`
+	ProblemPool          []int32        `json:"problem_pool"`
+	ProblemsPerMatch     int32          `json:"problems_per_match"`
+	MatchDurationMinutes int32          `json:"match_duration_minutes"`
+	Size                 int32          `json:"size"`
+	CreatedBy            uuid.UUID      `json:"created_by"`
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at"`
+}
+
 type TournamentContest struct {
 	RoundID   uuid.UUID `json:"round_id"`
 	ContestID uuid.UUID `json:"contest_id"`
@@ -184,6 +739,15 @@ type TournamentRound struct {
 	LockID       *uuid.UUID `json:"lock_id"`
 	CreatedBy    uuid.UUID  `json:"created_by"`
 	UpdatedAt    time.Time  `json:"updated_at"`
+	IsPublished  bool       `json:"is_published"`
+}
+
+type TournamentRoundScoring struct {
+	RoundID   uuid.UUID `json:"round_id"`
+	Weight    int32     `json:"weight"`
+	BestOf    *int32    `json:"best_of"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type User struct {
@@ -197,9 +761,33 @@ type User struct {
 	CreatedAt    time.Time `json:"created_at"`
 }
 
+type UserIdentity struct {
+	Issuer    string    `json:"issuer"`
+	Subject   string    `json:"subject"`
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type UserNotificationSetting struct {
+	UserID        uuid.UUID `json:"user_id"`
+	ContestEmails bool      `json:"contest_emails"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type UserRecoveryCode struct {
+	UserID     uuid.UUID  `json:"user_id"`
+	HashedCode string     `json:"hashed_code"`
+	UsedAt     *time.Time `json:"used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
 type UserRole struct {
-	UserID   uuid.UUID `json:"user_id"`
-	RoleName string    `json:"role_name"`
+	UserID    uuid.UUID  `json:"user_id"`
+	RoleName  string     `json:"role_name"`
+	GrantedBy *uuid.UUID `json:"granted_by"`
+	GrantedAt time.Time  `json:"granted_at"`
 }
 
 type UserScore struct {
@@ -210,3 +798,30 @@ type UserScore struct {
 	UpdatedAt    time.Time `json:"updated_at"`
 	SubmissionID uuid.UUID `json:"submission_id"`
 }
+
+type UserSession struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	UserAgent string     `json:"user_agent"`
+	IpAddress string     `json:"ip_address"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+type UserTotp struct {
+	UserID       uuid.UUID  `json:"user_id"`
+	Secret       string     `json:"secret"`
+	EnabledAt    *time.Time `json:"enabled_at"`
+	LastUsedStep int64      `json:"last_used_step"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type VirtualParticipation struct {
+	ID        uuid.UUID `json:"id"`
+	ContestID uuid.UUID `json:"contest_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -7,12 +7,165 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const addUserRole = `-- name: AddUserRole :one
+INSERT INTO user_roles (
+    user_id,
+    role_name,
+    granted_by
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+RETURNING user_id, role_name, granted_by, granted_at
+`
+
+type AddUserRoleParams struct {
+	UserID    uuid.UUID  `json:"user_id"`
+	RoleName  string     `json:"role_name"`
+	GrantedBy *uuid.UUID `json:"granted_by"`
+}
+
+func (q *Queries) AddUserRole(ctx context.Context, arg AddUserRoleParams) (UserRole, error) {
+	row := q.db.QueryRow(ctx, addUserRole, arg.UserID, arg.RoleName, arg.GrantedBy)
+	var i UserRole
+	err := row.Scan(
+		&i.UserID,
+		&i.RoleName,
+		&i.GrantedBy,
+		&i.GrantedAt,
+	)
+	return i, err
+}
+
+const createRole = `-- name: CreateRole :one
+INSERT INTO roles (
+    role_name,
+    description,
+    created_by
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+RETURNING role_name, description, created_by, created_at
+`
+
+type CreateRoleParams struct {
+	RoleName    string     `json:"role_name"`
+	Description string     `json:"description"`
+	CreatedBy   *uuid.UUID `json:"created_by"`
+}
+
+func (q *Queries) CreateRole(ctx context.Context, arg CreateRoleParams) (Role, error) {
+	row := q.db.QueryRow(ctx, createRole, arg.RoleName, arg.Description, arg.CreatedBy)
+	var i Role
+	err := row.Scan(
+		&i.RoleName,
+		&i.Description,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getRoleMembers = `-- name: GetRoleMembers :many
+SELECT u.id, u.user_name, u.roll_no, ur.granted_by, ur.granted_at FROM user_roles ur
+JOIN users u ON ur.user_id = u.id
+WHERE ur.role_name = $1
+ORDER BY ur.granted_at
+LIMIT $3
+OFFSET $2
+`
+
+type GetRoleMembersParams struct {
+	RoleName string `json:"role_name"`
+	Offset   int32  `json:"offset"`
+	Limit    int32  `json:"limit"`
+}
+
+type GetRoleMembersRow struct {
+	ID        uuid.UUID  `json:"id"`
+	UserName  string     `json:"user_name"`
+	RollNo    string     `json:"roll_no"`
+	GrantedBy *uuid.UUID `json:"granted_by"`
+	GrantedAt time.Time  `json:"granted_at"`
+}
+
+func (q *Queries) GetRoleMembers(ctx context.Context, arg GetRoleMembersParams) ([]GetRoleMembersRow, error) {
+	rows, err := q.db.Query(ctx, getRoleMembers, arg.RoleName, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRoleMembersRow
+	for rows.Next() {
+		var i GetRoleMembersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserName,
+			&i.RollNo,
+			&i.GrantedBy,
+			&i.GrantedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoles = `-- name: GetRoles :many
+SELECT r.role_name, r.description, r.created_by, r.created_at, COUNT(ur.user_id) AS member_count FROM roles r
+LEFT JOIN user_roles ur ON r.role_name = ur.role_name
+GROUP BY r.role_name
+ORDER BY r.role_name
+`
+
+type GetRolesRow struct {
+	RoleName    string     `json:"role_name"`
+	Description string     `json:"description"`
+	CreatedBy   *uuid.UUID `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	MemberCount int64      `json:"member_count"`
+}
+
+func (q *Queries) GetRoles(ctx context.Context) ([]GetRolesRow, error) {
+	rows, err := q.db.Query(ctx, getRoles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRolesRow
+	for rows.Next() {
+		var i GetRolesRow
+		if err := rows.Scan(
+			&i.RoleName,
+			&i.Description,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.MemberCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserRolesByUserName = `-- name: GetUserRolesByUserName :many
-SELECT user_id, role_name FROM user_roles WHERE user_id = $1
+SELECT user_id, role_name, granted_by, granted_at FROM user_roles WHERE user_id = $1
 `
 
 func (q *Queries) GetUserRolesByUserName(ctx context.Context, userID uuid.UUID) ([]UserRole, error) {
@@ -24,7 +177,12 @@ func (q *Queries) GetUserRolesByUserName(ctx context.Context, userID uuid.UUID)
 	var items []UserRole
 	for rows.Next() {
 		var i UserRole
-		if err := rows.Scan(&i.UserID, &i.RoleName); err != nil {
+		if err := rows.Scan(
+			&i.UserID,
+			&i.RoleName,
+			&i.GrantedBy,
+			&i.GrantedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -34,3 +192,26 @@ func (q *Queries) GetUserRolesByUserName(ctx context.Context, userID uuid.UUID)
 	}
 	return items, nil
 }
+
+const removeUserRole = `-- name: RemoveUserRole :one
+DELETE FROM user_roles
+WHERE user_id = $1 AND role_name = $2
+RETURNING user_id, role_name, granted_by, granted_at
+`
+
+type RemoveUserRoleParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	RoleName string    `json:"role_name"`
+}
+
+func (q *Queries) RemoveUserRole(ctx context.Context, arg RemoveUserRoleParams) (UserRole, error) {
+	row := q.db.QueryRow(ctx, removeUserRole, arg.UserID, arg.RoleName)
+	var i UserRole
+	err := row.Scan(
+		&i.UserID,
+		&i.RoleName,
+		&i.GrantedBy,
+		&i.GrantedAt,
+	)
+	return i, err
+}
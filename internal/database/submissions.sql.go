@@ -8,6 +8,7 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -172,8 +173,60 @@ func (q *Queries) GetCfSubmissionById(ctx context.Context, id uuid.UUID) (GetCfS
 	return i, err
 }
 
+const getContestSubmissions = `-- name: GetContestSubmissions :many
+SELECT
+    id,
+    submitted_by,
+    problem_id,
+    state,
+    submitted_at,
+    virtual_participation_id,
+    score
+FROM submissions
+WHERE contest_id=$1
+ORDER BY submitted_at
+`
+
+type GetContestSubmissionsRow struct {
+	ID                     uuid.UUID  `json:"id"`
+	SubmittedBy            uuid.UUID  `json:"submitted_by"`
+	ProblemID              int32      `json:"problem_id"`
+	State                  string     `json:"state"`
+	SubmittedAt            time.Time  `json:"submitted_at"`
+	VirtualParticipationID *uuid.UUID `json:"virtual_participation_id"`
+	Score                  *int32     `json:"score"`
+}
+
+func (q *Queries) GetContestSubmissions(ctx context.Context, contestID *uuid.UUID) ([]GetContestSubmissionsRow, error) {
+	rows, err := q.db.Query(ctx, getContestSubmissions, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetContestSubmissionsRow
+	for rows.Next() {
+		var i GetContestSubmissionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubmittedBy,
+			&i.ProblemID,
+			&i.State,
+			&i.SubmittedAt,
+			&i.VirtualParticipationID,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSubmissionByID = `-- name: GetSubmissionByID :one
-SELECT id, submitted_by, contest_id, problem_id, solution, state, submitted_at, updated_at From submissions WHERE id=$1
+SELECT id, submitted_by, contest_id, problem_id, solution, state, submitted_at, updated_at, virtual_participation_id, score From submissions WHERE id=$1
 `
 
 func (q *Queries) GetSubmissionByID(ctx context.Context, id uuid.UUID) (Submission, error) {
@@ -188,6 +241,8 @@ func (q *Queries) GetSubmissionByID(ctx context.Context, id uuid.UUID) (Submissi
 		&i.State,
 		&i.SubmittedAt,
 		&i.UpdatedAt,
+		&i.VirtualParticipationID,
+		&i.Score,
 	)
 	return i, err
 }
@@ -237,23 +292,26 @@ INSERT INTO submissions (
     contest_id,
     problem_id,
     solution,
-    state
+    state,
+    virtual_participation_id
 ) VALUES (
     $1,
     $2,
     $3,
     $4,
-    $5
+    $5,
+    $6
 )
-RETURNING id, submitted_by, contest_id, problem_id, solution, state, submitted_at, updated_at
+RETURNING id, submitted_by, contest_id, problem_id, solution, state, submitted_at, updated_at, virtual_participation_id, score
 `
 
 type InsertSubmissionParams struct {
-	SubmittedBy uuid.UUID       `json:"submitted_by"`
-	ContestID   *uuid.UUID      `json:"contest_id"`
-	ProblemID   int32           `json:"problem_id"`
-	Solution    json.RawMessage `json:"solution"`
-	State       string          `json:"state"`
+	SubmittedBy            uuid.UUID       `json:"submitted_by"`
+	ContestID              *uuid.UUID      `json:"contest_id"`
+	ProblemID              int32           `json:"problem_id"`
+	Solution               json.RawMessage `json:"solution"`
+	State                  string          `json:"state"`
+	VirtualParticipationID *uuid.UUID      `json:"virtual_participation_id"`
 }
 
 func (q *Queries) InsertSubmission(ctx context.Context, arg InsertSubmissionParams) (Submission, error) {
@@ -263,6 +321,7 @@ func (q *Queries) InsertSubmission(ctx context.Context, arg InsertSubmissionPara
 		arg.ProblemID,
 		arg.Solution,
 		arg.State,
+		arg.VirtualParticipationID,
 	)
 	var i Submission
 	err := row.Scan(
@@ -274,12 +333,31 @@ func (q *Queries) InsertSubmission(ctx context.Context, arg InsertSubmissionPara
 		&i.State,
 		&i.SubmittedAt,
 		&i.UpdatedAt,
+		&i.VirtualParticipationID,
+		&i.Score,
+	)
+	return i, err
+}
+
+const lockBot = `-- name: LockBot :one
+SELECT name, platform, cookies, created_at, updated_at FROM bots WHERE name=$1 FOR UPDATE
+`
+
+func (q *Queries) LockBot(ctx context.Context, name string) (Bot, error) {
+	row := q.db.QueryRow(ctx, lockBot, name)
+	var i Bot
+	err := row.Scan(
+		&i.Name,
+		&i.Platform,
+		&i.Cookies,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
 const pollPendingSubmissions = `-- name: PollPendingSubmissions :many
-SELECT id, submitted_by, contest_id, problem_id, solution, state, submitted_at, updated_at FROM submissions WHERE state = ANY($1::VARCHAR[])
+SELECT id, submitted_by, contest_id, problem_id, solution, state, submitted_at, updated_at, virtual_participation_id, score FROM submissions WHERE state = ANY($1::VARCHAR[])
 `
 
 func (q *Queries) PollPendingSubmissions(ctx context.Context, pendingStates []string) ([]Submission, error) {
@@ -300,6 +378,8 @@ func (q *Queries) PollPendingSubmissions(ctx context.Context, pendingStates []st
 			&i.State,
 			&i.SubmittedAt,
 			&i.UpdatedAt,
+			&i.VirtualParticipationID,
+			&i.Score,
 		); err != nil {
 			return nil, err
 		}
@@ -311,6 +391,20 @@ func (q *Queries) PollPendingSubmissions(ctx context.Context, pendingStates []st
 	return items, nil
 }
 
+const setSubmissionScore = `-- name: SetSubmissionScore :exec
+UPDATE submissions SET score=$2 WHERE id=$1
+`
+
+type SetSubmissionScoreParams struct {
+	ID    uuid.UUID `json:"id"`
+	Score *int32    `json:"score"`
+}
+
+func (q *Queries) SetSubmissionScore(ctx context.Context, arg SetSubmissionScoreParams) error {
+	_, err := q.db.Exec(ctx, setSubmissionScore, arg.ID, arg.Score)
+	return err
+}
+
 const updateBot = `-- name: UpdateBot :one
 UPDATE bots SET cookies=$2 WHERE name=$1 RETURNING name, platform, cookies, created_at, updated_at
 `
@@ -334,7 +428,7 @@ func (q *Queries) UpdateBot(ctx context.Context, arg UpdateBotParams) (Bot, erro
 }
 
 const updateSubmissionByID = `-- name: UpdateSubmissionByID :one
-UPDATE submissions SET state=$2 WHERE id=$1 RETURNING id, submitted_by, contest_id, problem_id, solution, state, submitted_at, updated_at
+UPDATE submissions SET state=$2 WHERE id=$1 RETURNING id, submitted_by, contest_id, problem_id, solution, state, submitted_at, updated_at, virtual_participation_id, score
 `
 
 type UpdateSubmissionByIDParams struct {
@@ -354,6 +448,8 @@ func (q *Queries) UpdateSubmissionByID(ctx context.Context, arg UpdateSubmission
 		&i.State,
 		&i.SubmittedAt,
 		&i.UpdatedAt,
+		&i.VirtualParticipationID,
+		&i.Score,
 	)
 	return i, err
 }
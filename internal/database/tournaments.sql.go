@@ -27,6 +27,27 @@ func (q *Queries) AddTournamentContest(ctx context.Context, arg AddTournamentCon
 	return err
 }
 
+const countTournamentAttachedContests = `-- name: CountTournamentAttachedContests :one
+SELECT (
+    SELECT COUNT(*)
+    FROM tournament_contests tc
+    JOIN tournament_rounds tr ON tc.round_id = tr.id
+    WHERE tr.tournament_id = $1
+) + (
+    SELECT COUNT(*)
+    FROM bracket_matches bm
+    WHERE bm.tournament_id = $1 AND bm.contest_id IS NOT NULL
+)
+`
+
+// contests of the rounds and of the bracket matches of the tournament
+func (q *Queries) CountTournamentAttachedContests(ctx context.Context, tournamentID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countTournamentAttachedContests, tournamentID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
 const createTournament = `-- name: CreateTournament :one
 INSERT INTO tournaments (
     title, is_published, created_by
@@ -58,7 +79,7 @@ const createTournamentRound = `-- name: CreateTournamentRound :one
 INSERT INTO tournament_rounds (
     tournament_id, round_number, title, lock_id, created_by
 ) VALUES ($1, $2, $3, $4, $5)
-RETURNING id, tournament_id, round_number, title, lock_id, created_by, updated_at
+RETURNING id, tournament_id, round_number, title, lock_id, created_by, updated_at, is_published
 `
 
 type CreateTournamentRoundParams struct {
@@ -86,10 +107,20 @@ func (q *Queries) CreateTournamentRound(ctx context.Context, arg CreateTournamen
 		&i.LockID,
 		&i.CreatedBy,
 		&i.UpdatedAt,
+		&i.IsPublished,
 	)
 	return i, err
 }
 
+const deleteTournament = `-- name: DeleteTournament :exec
+DELETE FROM tournaments WHERE id = $1
+`
+
+func (q *Queries) DeleteTournament(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteTournament, id)
+	return err
+}
+
 const deleteTournamentContests = `-- name: DeleteTournamentContests :exec
 DELETE FROM tournament_contests WHERE round_id = $1
 `
@@ -99,6 +130,24 @@ func (q *Queries) DeleteTournamentContests(ctx context.Context, roundID uuid.UUI
 	return err
 }
 
+const deleteTournamentRound = `-- name: DeleteTournamentRound :exec
+DELETE FROM tournament_rounds WHERE id = $1
+`
+
+func (q *Queries) DeleteTournamentRound(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteTournamentRound, id)
+	return err
+}
+
+const deleteTournamentRounds = `-- name: DeleteTournamentRounds :exec
+DELETE FROM tournament_rounds WHERE tournament_id = $1
+`
+
+func (q *Queries) DeleteTournamentRounds(ctx context.Context, tournamentID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteTournamentRounds, tournamentID)
+	return err
+}
+
 const getTournamentById = `-- name: GetTournamentById :one
 SELECT
     t.id,
@@ -236,6 +285,7 @@ SELECT
     tr.title,
     tr.lock_id,
     tr.created_by,
+    tr.is_published,
 
     -- lock fields
     l.access,
@@ -262,6 +312,7 @@ type GetTournamentRoundByNumberRow struct {
 	Title        string     `json:"title"`
 	LockID       *uuid.UUID `json:"lock_id"`
 	CreatedBy    uuid.UUID  `json:"created_by"`
+	IsPublished  bool       `json:"is_published"`
 	Access       *string    `json:"access"`
 	Timeout      *time.Time `json:"timeout"`
 }
@@ -276,6 +327,7 @@ func (q *Queries) GetTournamentRoundByNumber(ctx context.Context, arg GetTournam
 		&i.Title,
 		&i.LockID,
 		&i.CreatedBy,
+		&i.IsPublished,
 		&i.Access,
 		&i.Timeout,
 	)
@@ -359,3 +411,103 @@ func (q *Queries) GetTournamentsByFilters(ctx context.Context, arg GetTournament
 	}
 	return items, nil
 }
+
+const reorderTournamentRounds = `-- name: ReorderTournamentRounds :exec
+UPDATE tournament_rounds tr SET round_number = o.round_number
+FROM unnest($1::uuid[]) WITH ORDINALITY AS o(id, round_number)
+WHERE tr.id = o.id AND tr.tournament_id = $2
+`
+
+type ReorderTournamentRoundsParams struct {
+	RoundIds     []uuid.UUID `json:"round_ids"`
+	TournamentID uuid.UUID   `json:"tournament_id"`
+}
+
+// the round at position i of the ids becomes the round i
+func (q *Queries) ReorderTournamentRounds(ctx context.Context, arg ReorderTournamentRoundsParams) error {
+	_, err := q.db.Exec(ctx, reorderTournamentRounds, arg.RoundIds, arg.TournamentID)
+	return err
+}
+
+const setTournamentPublished = `-- name: SetTournamentPublished :exec
+UPDATE tournaments SET is_published = $2 WHERE id = $1
+`
+
+type SetTournamentPublishedParams struct {
+	ID          uuid.UUID `json:"id"`
+	IsPublished bool      `json:"is_published"`
+}
+
+func (q *Queries) SetTournamentPublished(ctx context.Context, arg SetTournamentPublishedParams) error {
+	_, err := q.db.Exec(ctx, setTournamentPublished, arg.ID, arg.IsPublished)
+	return err
+}
+
+const setTournamentRoundPublished = `-- name: SetTournamentRoundPublished :exec
+UPDATE tournament_rounds SET is_published = $2 WHERE id = $1
+`
+
+type SetTournamentRoundPublishedParams struct {
+	ID          uuid.UUID `json:"id"`
+	IsPublished bool      `json:"is_published"`
+}
+
+func (q *Queries) SetTournamentRoundPublished(ctx context.Context, arg SetTournamentRoundPublishedParams) error {
+	_, err := q.db.Exec(ctx, setTournamentRoundPublished, arg.ID, arg.IsPublished)
+	return err
+}
+
+const shiftTournamentRoundsDown = `-- name: ShiftTournamentRoundsDown :exec
+UPDATE tournament_rounds SET round_number = round_number - 1
+WHERE tournament_id = $1 AND round_number > $2
+`
+
+type ShiftTournamentRoundsDownParams struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	RoundNumber  int32     `json:"round_number"`
+}
+
+// closes the gap left by a deleted round
+func (q *Queries) ShiftTournamentRoundsDown(ctx context.Context, arg ShiftTournamentRoundsDownParams) error {
+	_, err := q.db.Exec(ctx, shiftTournamentRoundsDown, arg.TournamentID, arg.RoundNumber)
+	return err
+}
+
+const updateTournament = `-- name: UpdateTournament :one
+UPDATE tournaments SET title = $2 WHERE id = $1
+RETURNING id, title, is_published, created_by, created_at, updated_at
+`
+
+type UpdateTournamentParams struct {
+	ID    uuid.UUID `json:"id"`
+	Title string    `json:"title"`
+}
+
+func (q *Queries) UpdateTournament(ctx context.Context, arg UpdateTournamentParams) (Tournament, error) {
+	row := q.db.QueryRow(ctx, updateTournament, arg.ID, arg.Title)
+	var i Tournament
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.IsPublished,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateTournamentRound = `-- name: UpdateTournamentRound :exec
+UPDATE tournament_rounds SET title = $2, lock_id = $3 WHERE id = $1
+`
+
+type UpdateTournamentRoundParams struct {
+	ID     uuid.UUID  `json:"id"`
+	Title  string     `json:"title"`
+	LockID *uuid.UUID `json:"lock_id"`
+}
+
+func (q *Queries) UpdateTournamentRound(ctx context.Context, arg UpdateTournamentRoundParams) error {
+	_, err := q.db.Exec(ctx, updateTournamentRound, arg.ID, arg.Title, arg.LockID)
+	return err
+}
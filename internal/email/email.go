@@ -30,7 +30,13 @@ const (
 	KeyEmailBodyPlain           EmailBodyType = "text/plain"
 	PurposeEmailPasswordReset   EmailPurpose  = "reset_password"
 	PurposeEmailSignUp          EmailPurpose  = "sign_up"
+	PurposeAccountLocked        EmailPurpose  = "account locked"
 	PurposeBotNotWorkingAlert   EmailPurpose  = "bot not working"
+	PurposeContestStarted       EmailPurpose  = "contest started"
+	PurposeContestRegistered    EmailPurpose  = "contest registered"
+	PurposeContestReminder      EmailPurpose  = "contest reminder"
+	PurposeContestResults       EmailPurpose  = "contest results"
+	PurposeContestBroadcast     EmailPurpose  = "contest broadcast"
 	defaultEmailChannelCapacity               = 100
 )
 
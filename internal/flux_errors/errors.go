@@ -36,6 +36,8 @@ var (
 	ErrMonitorStart              = errors.New("monitor failed to start")
 	ErrComponentStart            = errors.New("cannot start component")
 	ErrEntityAlreadyExist        = errors.New("entity with given key already exist")
+	ErrTwoFactorRequired         = errors.New("two factor code required")
+	ErrTooManyAttempts           = errors.New("too many attempts")
 )
 
 func HandleDBErrors(
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+)
+
+// AuditEntry is a change to be recorded in the audit log. Before is nil for
+// creations and After is nil for deletions.
+type AuditEntry struct {
+	Action     string
+	EntityType string
+	EntityID   any
+	Before     any
+	After      any
+}
+
+// RecordAudit appends the entry to the audit log with the user and the request
+// found in the context. Pass the query tool of the transaction making the
+// change, so that the change is not committed without its entry.
+func RecordAudit(
+	ctx context.Context,
+	db *database.Queries,
+	entry AuditEntry,
+) error {
+	params := database.CreateAuditLogParams{
+		ActorUserName: AuditActorServer,
+		Action:        entry.Action,
+		EntityType:    entry.EntityType,
+		EntityID:      fmt.Sprint(entry.EntityID),
+	}
+
+	// changes made by the server itself have no claims
+	if claims, ok := ctx.Value(KeyCtxUserCredClaims).(UserCredentialClaims); ok {
+		params.ActorID = &claims.UserId
+		params.ActorUserName = claims.UserName
+	}
+	if requestID, ok := ctx.Value(KeyCtxRequestID).(string); ok {
+		params.RequestID = &requestID
+	}
+
+	// take the snapshots
+	var err error
+	params.Before, err = auditSnapshot(entry.Before)
+	if err != nil {
+		return err
+	}
+	params.After, err = auditSnapshot(entry.After)
+	if err != nil {
+		return err
+	}
+
+	err = db.CreateAuditLog(ctx, params)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot record %s of %s %s, %w",
+			flux_errors.ErrInternal,
+			entry.Action,
+			entry.EntityType,
+			params.EntityID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+func auditSnapshot(snapshot any) (*json.RawMessage, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(snapshot)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot marshal audit snapshot %v, %w",
+			flux_errors.ErrInternal,
+			snapshot,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	// nil pointers are nil snapshots too
+	if string(raw) == "null" {
+		return nil, nil
+	}
+
+	message := json.RawMessage(raw)
+	return &message, nil
+}
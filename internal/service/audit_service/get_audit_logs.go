@@ -0,0 +1,77 @@
+package audit_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// GetAuditLogs searches the changes made to contests, problems, locks,
+// tournaments, bots and roles
+func (a *AuditService) GetAuditLogs(
+	ctx context.Context,
+	request GetAuditLogsRequest,
+) ([]AuditLog, error) {
+	// authorize
+	err := a.UserServiceConfig.Authorize(ctx, user_service.PermAuditView, user_service.Resource{})
+	if err != nil {
+		return nil, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return nil, err
+	}
+	if request.From != nil && request.To != nil && !request.From.Before(*request.To) {
+		return nil, fmt.Errorf(
+			"%w, from must be before to",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	dbLogs, err := a.DB.GetAuditLogs(
+		ctx,
+		database.GetAuditLogsParams{
+			ActorUserName: request.ActorUserName,
+			Action:        request.Action,
+			EntityType:    request.EntityType,
+			EntityID:      request.EntityID,
+			RequestID:     request.RequestID,
+			From:          request.From,
+			To:            request.To,
+			Offset:        (request.PageNumber - 1) * request.PageSize,
+			Limit:         request.PageSize,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			"cannot fetch audit logs",
+		)
+		return nil, err
+	}
+
+	logs := make([]AuditLog, 0, len(dbLogs))
+	for _, dbLog := range dbLogs {
+		logs = append(logs, AuditLog{
+			ID:            dbLog.ID,
+			ActorID:       dbLog.ActorID,
+			ActorUserName: dbLog.ActorUserName,
+			Action:        dbLog.Action,
+			EntityType:    dbLog.EntityType,
+			EntityID:      dbLog.EntityID,
+			Before:        dbLog.Before,
+			After:         dbLog.After,
+			RequestID:     dbLog.RequestID,
+			CreatedAt:     dbLog.CreatedAt,
+		})
+	}
+
+	return logs, nil
+}
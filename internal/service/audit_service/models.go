@@ -0,0 +1,48 @@
+package audit_service
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+var (
+	errMsgs = map[string]map[string]string{}
+)
+
+type AuditService struct {
+	DB                *database.Queries
+	UserServiceConfig *user_service.UserService
+}
+
+type AuditLog struct {
+	ID uuid.UUID `json:"id"`
+	// nil for the changes made by the server itself
+	ActorID       *uuid.UUID `json:"actor_id"`
+	ActorUserName string     `json:"actor_user_name"`
+	Action        string     `json:"action"`
+	EntityType    string     `json:"entity_type"`
+	EntityID      string     `json:"entity_id"`
+	// snapshots of the entity, before is nil for creations and after is nil for deletions
+	Before    *json.RawMessage `json:"before"`
+	After     *json.RawMessage `json:"after"`
+	RequestID *string          `json:"request_id"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// GetAuditLogsRequest filters the audit log, nil filters are ignored. The
+// logs created in [From, To) are returned, latest first.
+type GetAuditLogsRequest struct {
+	ActorUserName *string    `json:"actor_user_name"`
+	Action        *string    `json:"action"`
+	EntityType    *string    `json:"entity_type"`
+	EntityID      *string    `json:"entity_id"`
+	RequestID     *string    `json:"request_id"`
+	From          *time.Time `json:"from"`
+	To            *time.Time `json:"to"`
+	PageNumber    int32      `json:"page_number" validate:"min=1,max=10000"`
+	PageSize      int32      `json:"page_size" validate:"min=1,max=100"`
+}
@@ -0,0 +1,286 @@
+package auth_service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// CreateAccessToken creates a personal access token of the user. The token is
+// returned only here, the db keeps its hash.
+func (a *AuthService) CreateAccessToken(
+	ctx context.Context,
+	request CreateAccessTokenRequest,
+) (AccessToken, error) {
+	// fetch claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return AccessToken{}, err
+	}
+
+	// a leaked token must not be able to outlive itself
+	if claims.AccessTokenID != nil {
+		return AccessToken{}, fmt.Errorf(
+			"%w, access tokens cannot be created with an access token",
+			flux_errors.ErrUnAuthorized,
+		)
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return AccessToken{}, err
+	}
+
+	// users can scope only the permissions they can use
+	permissions, err := a.UserConfig.GetMyPermissions(ctx)
+	if err != nil {
+		return AccessToken{}, err
+	}
+	missing := user_service.MissingPermissions(permissions, request.Scopes)
+	if len(missing) > 0 {
+		return AccessToken{}, fmt.Errorf(
+			"%w, you do not hold the permissions %v",
+			flux_errors.ErrInvalidRequest,
+			missing,
+		)
+	}
+
+	plainToken, err := generateAccessToken()
+	if err != nil {
+		return AccessToken{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return AccessToken{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := a.DB.WithTx(tx)
+
+	dbToken, err := qtx.CreatePersonalAccessToken(
+		ctx,
+		database.CreatePersonalAccessTokenParams{
+			UserID:      claims.UserId,
+			Name:        request.Name,
+			HashedToken: hashAccessToken(plainToken),
+			Scopes:      request.Scopes,
+			ExpiresAt:   time.Now().AddDate(0, 0, int(request.ExpiresInDays)),
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot create access token of user %s", claims.UserName),
+		)
+		return AccessToken{}, err
+	}
+	accessToken := dbAccessTokenToAccessToken(dbToken)
+
+	// record the change, without the token
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionUserCreateAccessToken,
+		EntityType: auditEntityUser,
+		EntityID:   claims.UserId,
+		After:      accessToken,
+	})
+	if err != nil {
+		return AccessToken{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return AccessToken{}, fmt.Errorf(
+			"%w, cannot commit transaction after creating access token of user %s, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			err,
+		)
+	}
+
+	log.Infof("user %s created access token %v", claims.UserName, accessToken.ID)
+
+	accessToken.Token = plainToken
+	return accessToken, nil
+}
+
+// GetMyAccessTokens lists the access tokens of the user that are not revoked
+func (a *AuthService) GetMyAccessTokens(ctx context.Context) ([]AccessToken, error) {
+	// fetch claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dbTokens, err := a.DB.GetUserPersonalAccessTokens(ctx, claims.UserId)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch access tokens of user %s", claims.UserName),
+		)
+		return nil, err
+	}
+
+	accessTokens := make([]AccessToken, 0, len(dbTokens))
+	for _, dbToken := range dbTokens {
+		accessTokens = append(accessTokens, dbAccessTokenToAccessToken(dbToken))
+	}
+
+	return accessTokens, nil
+}
+
+// RevokeAccessToken revokes one of the access tokens of the user
+func (a *AuthService) RevokeAccessToken(ctx context.Context, tokenID uuid.UUID) error {
+	// fetch claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := a.DB.WithTx(tx)
+
+	dbToken, err := qtx.RevokePersonalAccessToken(
+		ctx,
+		database.RevokePersonalAccessTokenParams{
+			ID:     tokenID,
+			UserID: claims.UserId,
+		},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf(
+				"%w, no active access token with id %v",
+				flux_errors.ErrNotFound,
+				tokenID,
+			)
+		}
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot revoke access token %v", tokenID),
+		)
+		return err
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionUserRevokeAccessToken,
+		EntityType: auditEntityUser,
+		EntityID:   claims.UserId,
+		Before:     dbAccessTokenToAccessToken(dbToken),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf(
+			"%w, cannot commit transaction after revoking access token %v, %w",
+			flux_errors.ErrInternal,
+			tokenID,
+			err,
+		)
+	}
+
+	log.Infof("user %s revoked access token %v", claims.UserName, tokenID)
+	return nil
+}
+
+// AuthenticateAccessToken returns the claims of the user owning the token, the
+// same claims a session token of the user would carry along with the scopes
+func (a *AuthService) AuthenticateAccessToken(
+	ctx context.Context,
+	token string,
+) (service.UserCredentialClaims, error) {
+	if !strings.HasPrefix(token, accessTokenPrefix) {
+		return service.UserCredentialClaims{}, fmt.Errorf(
+			"%w, malformed access token",
+			flux_errors.ErrUnAuthorized,
+		)
+	}
+
+	dbToken, err := a.DB.UsePersonalAccessToken(ctx, hashAccessToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.UserCredentialClaims{}, fmt.Errorf(
+				"%w, access token is invalid, revoked or expired",
+				flux_errors.ErrUnAuthorized,
+			)
+		}
+		err = flux_errors.HandleDBErrors(err, errMsgs, "cannot fetch access token")
+		return service.UserCredentialClaims{}, err
+	}
+
+	return service.UserCredentialClaims{
+		UserId:        dbToken.UserID,
+		UserName:      dbToken.UserName,
+		AccessTokenID: &dbToken.ID,
+		Scopes:        dbToken.Scopes,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(dbToken.ExpiresAt),
+			IssuedAt:  jwt.NewNumericDate(dbToken.CreatedAt),
+			Subject:   "personal_access_token",
+			Issuer:    "flux-auth-service",
+		},
+	}, nil
+}
+
+func dbAccessTokenToAccessToken(dbToken database.PersonalAccessToken) AccessToken {
+	return AccessToken{
+		ID:         dbToken.ID,
+		Name:       dbToken.Name,
+		Scopes:     dbToken.Scopes,
+		CreatedAt:  dbToken.CreatedAt,
+		ExpiresAt:  dbToken.ExpiresAt,
+		LastUsedAt: dbToken.LastUsedAt,
+	}
+}
+
+func generateAccessToken() (string, error) {
+	tokenBytes := make([]byte, 32)
+	_, err := rand.Read(tokenBytes)
+	if err != nil {
+		log.Errorf("failed to generate random bytes: %v", err)
+		return "", errors.Join(flux_errors.ErrInternal, err)
+	}
+
+	return accessTokenPrefix + base64.RawURLEncoding.EncodeToString(tokenBytes), nil
+}
+
+// access tokens are random and long, unlike passwords they need no slow hash.
+// a fast one lets the token be looked up on every request.
+func hashAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"time"
 
 	jwt "github.com/golang-jwt/jwt/v4"
@@ -19,6 +20,14 @@ func (a *AuthService) Login(
 	ctx context.Context,
 	request UserLoginRequest,
 ) (userResponse user_service.User, tokenString string, tokenExpiry time.Time, err error) {
+	// count the attempt from the address before it is made, parallel
+	// attempts must not get past the limit
+	ip := ipSubject(request.IPAddress, ipPolicy)
+	ipAttempts, err := a.takeAttempts(ctx, throttleActionLogin, ip)
+	if err != nil {
+		return
+	}
+
 	// get user from db
 	userMetadata, err := a.UserConfig.GetUserByUserNameOrRollNo(
 		ctx,
@@ -26,10 +35,25 @@ func (a *AuthService) Login(
 		request.RollNo,
 	)
 	if err != nil {
+		// guessing users counts against the address
+		if !errors.Is(err, flux_errors.ErrNotFound) {
+			a.refundAttempts(ctx, ipAttempts...)
+		}
+		return
+	}
+
+	// count the attempt on the account
+	account := userSubject(userMetadata.UserID, accountPolicy)
+	accountAttempts, err := a.takeAttempts(ctx, throttleActionLogin, account)
+	if err != nil {
+		a.refundAttempts(ctx, ipAttempts...)
 		return
 	}
+	attempts := slices.Concat(ipAttempts, accountAttempts)
+
 	user, err := a.UserConfig.GetUserProfile(ctx, userMetadata.UserID)
 	if err != nil {
+		a.refundAttempts(ctx, attempts...)
 		return
 	}
 
@@ -40,29 +64,52 @@ func (a *AuthService) Login(
 	)
 	if bcErr != nil {
 		if errors.Is(bcErr, bcrypt.ErrMismatchedHashAndPassword) {
+			a.notifyLockout(ctx, user, attempts...)
 			err = flux_errors.ErrInvalidUserCredentials
 			return
 		}
+		a.refundAttempts(ctx, attempts...)
 		log.Errorf("failed to login user. %v", bcErr)
 		err = errors.Join(flux_errors.ErrInternal, bcErr)
 		return
 	}
 
+	// second step for the users with two factor authentication
+	twoFactor, err := a.loginSecondFactor(ctx, user.ID, request)
+	if err != nil {
+		// a wrong code is as good as a wrong password
+		if errors.Is(err, flux_errors.ErrInvalidRequestCredentials) {
+			a.notifyLockout(ctx, user, attempts...)
+		} else {
+			a.refundAttempts(ctx, attempts...)
+		}
+		return
+	}
+
+	// the account starts over, the address keeps the count of its failures
+	// as it may be guessing other accounts
+	a.clearAttempts(ctx, throttleActionLogin, account)
+	a.refundAttempts(ctx, ipAttempts...)
+
 	// calculate jwt expriy
 	var duration = time.Hour * 24
 	if request.RememberForMonth {
 		duration *= 30
 	}
-	var exp = time.Now().Add(duration)
 
-	// claims store the user data to avoid repeated logins via jwt
-	claims := dbUserToUserCredClaims(exp, user)
-	tokenString, err = GenerateJWT(claims)
+	// every token belongs to a session that can be revoked
+	tokenString, tokenExpiry, err = a.startSession(
+		ctx,
+		user,
+		duration,
+		twoFactor,
+		request.UserAgent,
+		request.IPAddress,
+	)
 	if err != nil {
 		return
 	}
 
-	tokenExpiry = exp
 	userResponse = user
 
 	return
@@ -2,6 +2,9 @@ package auth_service
 
 import (
 	"fmt"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/flux_errors"
@@ -19,9 +22,74 @@ var (
 	}
 )
 
+// actions recorded in the audit log
+const (
+	auditEntityUser                  = "user"
+	auditActionUserRevokeSessions    = "user.revoke_sessions"
+	auditActionUserCreateAccessToken = "user.create_access_token"
+	auditActionUserRevokeAccessToken = "user.revoke_access_token"
+	auditActionUserStartTOTP         = "user.start_totp"
+	auditActionUserEnableTOTP        = "user.enable_totp"
+	auditActionUserDisableTOTP       = "user.disable_totp"
+	auditActionUserNewRecoveryCodes  = "user.new_recovery_codes"
+)
+
+// throttled auth actions, attempts are counted per action
+const (
+	throttleActionLogin         = "login"
+	throttleActionResetPassword = "reset_password"
+	throttleActionMail          = "verification_mail"
+	// longest ForgetAfter of the policies
+	authAttemptsRetention = 24 * time.Hour
+)
+
+var (
+	// failed logins and password resets of an account
+	accountPolicy = ThrottlePolicy{
+		FreeAttempts: 3,
+		BaseDelay:    2 * time.Second,
+		MaxDelay:     5 * time.Minute,
+		LockoutAfter: 10,
+		LockoutFor:   30 * time.Minute,
+		ForgetAfter:  time.Hour,
+	}
+	// failures from an address, many users may share it
+	ipPolicy = ThrottlePolicy{
+		FreeAttempts: 20,
+		BaseDelay:    time.Second,
+		MaxDelay:     15 * time.Minute,
+		ForgetAfter:  time.Hour,
+	}
+	// verification mails sent to a mailbox
+	mailboxPolicy = ThrottlePolicy{
+		FreeAttempts: 3,
+		BaseDelay:    time.Minute,
+		MaxDelay:     time.Hour,
+		ForgetAfter:  24 * time.Hour,
+	}
+	// verification mails requested from an address
+	mailIPPolicy = ThrottlePolicy{
+		FreeAttempts: 10,
+		BaseDelay:    time.Minute,
+		MaxDelay:     time.Hour,
+		ForgetAfter:  time.Hour,
+	}
+)
+
+// time the user has to log in at the identity provider
+const oidcLoginExpiry = 10 * time.Minute
+
+// recovery codes given to the user on enabling two factor authentication
+const recoveryCodeCount = 10
+
+// personal access tokens start with it, so that leaked ones are easy to find
+const accessTokenPrefix = "flux_pat_"
+
 type AuthService struct {
 	DB         *database.Queries
 	UserConfig *user_service.UserService
+	// nil if oidc login is not configured
+	OIDC *OIDCProvider
 }
 
 type UserRegestration struct {
@@ -43,6 +111,23 @@ type UserLoginRequest struct {
 	RollNo           *string `json:"roll_no"`
 	Password         string  `json:"password"`
 	RememberForMonth bool    `json:"remember_for_month"`
+	// second step, one of them is required if two factor authentication is
+	// enabled
+	TOTPCode     string `json:"totp_code"`
+	RecoveryCode string `json:"recovery_code"`
+	// recorded with the session, set by the handler
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
+}
+
+// Session is a login of the user, its id is the jti claim of the token
+type Session struct {
+	ID        uuid.UUID `json:"session_id"`
+	UserAgent string    `json:"user_agent"`
+	IPAddress string    `json:"ip_address"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	IsCurrent bool      `json:"is_current"`
 }
 
 type ResetPasswordRequest struct {
@@ -50,6 +135,8 @@ type ResetPasswordRequest struct {
 	RollNo   *string `json:"roll_no"`
 	Password string  `json:"password"`
 	Token    string  `json:"verification_token"`
+	// failures are counted against it, set by the handler
+	IPAddress string `json:"-"`
 }
 
 func (rpr ResetPasswordRequest) String() string {
@@ -63,3 +150,65 @@ func (rpr ResetPasswordRequest) String() string {
 	}
 	return fmt.Sprintf("user_name=%s, roll_no=%s", uns, rns)
 }
+
+type CreateAccessTokenRequest struct {
+	Name          string   `json:"name" validate:"required,min=1,max=100"`
+	Scopes        []string `json:"scopes" validate:"dive,required"`
+	ExpiresInDays int32    `json:"expires_in_days" validate:"required,min=1,max=365"`
+}
+
+// AccessToken is a personal access token, Token is set only on creation
+type AccessToken struct {
+	ID         uuid.UUID  `json:"token_id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	Token      string     `json:"token,omitempty"`
+}
+
+type OIDCLoginStart struct {
+	AuthURL string
+	State   string
+}
+
+type OIDCCallbackRequest struct {
+	Code  string
+	State string
+	// state kept by the browser that started the login
+	BrowserState string
+	// recorded with the session
+	UserAgent string
+	IPAddress string
+}
+
+// TOTPEnrolment is a pending totp secret, to be confirmed with a code
+type TOTPEnrolment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type ConfirmTOTPRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// TwoFactorRequest proves the second factor for changing it, with either of
+// the codes
+type TwoFactorRequest struct {
+	TOTPCode     string `json:"totp_code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type TwoFactorStatus struct {
+	Enabled           bool       `json:"enabled"`
+	EnabledAt         *time.Time `json:"enabled_at"`
+	RecoveryCodesLeft int64      `json:"recovery_codes_left"`
+	// set if the roles of the user need it
+	Required bool `json:"required"`
+}
+
+// RecoveryCodes are shown once, the db keeps their hashes
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
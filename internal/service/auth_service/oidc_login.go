@@ -0,0 +1,325 @@
+package auth_service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// StartOIDCLogin prepares a login at the identity provider. The state must be
+// kept by the browser until the callback, to tie the login to it.
+func (a *AuthService) StartOIDCLogin(
+	ctx context.Context,
+	rememberForMonth bool,
+) (OIDCLoginStart, error) {
+	if a.OIDC == nil {
+		return OIDCLoginStart{}, fmt.Errorf(
+			"%w, oidc login is not configured",
+			flux_errors.ErrNotFound,
+		)
+	}
+
+	state, err := generateOIDCSecret()
+	if err != nil {
+		return OIDCLoginStart{}, err
+	}
+	nonce, err := generateOIDCSecret()
+	if err != nil {
+		return OIDCLoginStart{}, err
+	}
+	codeVerifier, err := generateOIDCSecret()
+	if err != nil {
+		return OIDCLoginStart{}, err
+	}
+
+	authURL, err := a.OIDC.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		return OIDCLoginStart{}, err
+	}
+
+	// abandoned logins are not consumed
+	err = a.DB.DeleteExpiredOIDCLoginStates(ctx)
+	if err != nil {
+		log.Warnf("cannot delete expired oidc login states, %v", err)
+	}
+
+	err = a.DB.CreateOIDCLoginState(
+		ctx,
+		database.CreateOIDCLoginStateParams{
+			State:            state,
+			CodeVerifier:     codeVerifier,
+			Nonce:            nonce,
+			RememberForMonth: rememberForMonth,
+			ExpiresAt:        time.Now().Add(oidcLoginExpiry),
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(err, errMsgs, "cannot create oidc login state")
+		return OIDCLoginStart{}, err
+	}
+
+	return OIDCLoginStart{
+		AuthURL: authURL,
+		State:   state,
+	}, nil
+}
+
+// FinishOIDCLogin completes the login with the code sent by the identity
+// provider and starts a session, like Login. The user is linked by the
+// verified email on the first login and created if there is none.
+func (a *AuthService) FinishOIDCLogin(
+	ctx context.Context,
+	request OIDCCallbackRequest,
+) (userResponse user_service.User, tokenString string, tokenExpiry time.Time, err error) {
+	if a.OIDC == nil {
+		err = fmt.Errorf("%w, oidc login is not configured", flux_errors.ErrNotFound)
+		return
+	}
+
+	// the login must have been started by the same browser
+	if request.State == "" ||
+		subtle.ConstantTimeCompare([]byte(request.State), []byte(request.BrowserState)) != 1 {
+		err = fmt.Errorf(
+			"%w, login was not started by this browser",
+			flux_errors.ErrInvalidRequest,
+		)
+		return
+	}
+	if request.Code == "" {
+		err = fmt.Errorf("%w, login was cancelled", flux_errors.ErrInvalidRequest)
+		return
+	}
+
+	// a state can be used once
+	loginState, err := a.DB.ConsumeOIDCLoginState(ctx, request.State)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf(
+				"%w, login has expired. please try again",
+				flux_errors.ErrInvalidRequest,
+			)
+			return
+		}
+		err = flux_errors.HandleDBErrors(err, errMsgs, "cannot fetch oidc login state")
+		return
+	}
+	if time.Now().After(loginState.ExpiresAt) {
+		err = fmt.Errorf(
+			"%w, login has expired. please try again",
+			flux_errors.ErrInvalidRequest,
+		)
+		return
+	}
+
+	rawIDToken, err := a.OIDC.Exchange(ctx, request.Code, loginState.CodeVerifier)
+	if err != nil {
+		return
+	}
+	identity, err := a.OIDC.VerifyIDToken(ctx, rawIDToken, loginState.Nonce)
+	if err != nil {
+		return
+	}
+
+	userID, err := a.getOIDCUser(ctx, identity)
+	if err != nil {
+		return
+	}
+	user, err := a.UserConfig.GetUserProfile(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	// the identity provider cannot replace the totp code
+	twoFactorStatus, err := a.twoFactorStatus(ctx, a.DB, userID)
+	if err != nil {
+		return
+	}
+	if twoFactorStatus.Enabled {
+		err = fmt.Errorf(
+			"%w, two factor authentication is enabled, log in with your password",
+			flux_errors.ErrTwoFactorRequired,
+		)
+		return
+	}
+
+	// same session as a password login
+	var duration = time.Hour * 24
+	if loginState.RememberForMonth {
+		duration *= 30
+	}
+	tokenString, tokenExpiry, err = a.startSession(
+		ctx,
+		user,
+		duration,
+		false,
+		request.UserAgent,
+		request.IPAddress,
+	)
+	if err != nil {
+		return
+	}
+
+	userResponse = user
+	return
+}
+
+// getOIDCUser returns the user linked with the identity, linking or creating
+// one if it is the first login of the identity
+func (a *AuthService) getOIDCUser(
+	ctx context.Context,
+	identity OIDCIdentity,
+) (uuid.UUID, error) {
+	dbIdentity, err := a.DB.GetUserIdentity(
+		ctx,
+		database.GetUserIdentityParams{
+			Issuer:  identity.Issuer,
+			Subject: identity.Subject,
+		},
+	)
+	if err == nil {
+		return dbIdentity.UserID, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch identity %s of %s", identity.Subject, identity.Issuer),
+		)
+		return uuid.UUID{}, err
+	}
+
+	// link the existing user with the same email
+	userID, err := a.DB.GetUserIDByEmail(ctx, identity.Email)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot fetch user with email %s", identity.Email),
+			)
+			return uuid.UUID{}, err
+		}
+
+		// first login, create the user
+		dbUser, err := a.createOIDCUser(ctx, identity)
+		if err != nil {
+			return uuid.UUID{}, err
+		}
+		userID = dbUser.ID
+		log.WithFields(log.Fields{
+			"user_name": dbUser.UserName,
+			"issuer":    identity.Issuer,
+		}).Info("created user on oidc login")
+	}
+
+	err = a.DB.CreateUserIdentity(
+		ctx,
+		database.CreateUserIdentityParams{
+			Issuer:  identity.Issuer,
+			Subject: identity.Subject,
+			UserID:  userID,
+			Email:   identity.Email,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot link identity %s of %s", identity.Subject, identity.Issuer),
+		)
+		return uuid.UUID{}, err
+	}
+
+	log.Infof("linked identity %s of %s with user %v", identity.Subject, identity.Issuer, userID)
+	return userID, nil
+}
+
+// createOIDCUser creates a user without a usable password, they can set one
+// with a password reset. Only the emails of the college tell the roll number,
+// others have to sign up first and get their identity linked by the email.
+func (a *AuthService) createOIDCUser(
+	ctx context.Context,
+	identity OIDCIdentity,
+) (database.User, error) {
+	rollNo, ok := OIDCRollNo(identity.Email, a.OIDC.config.AllowedDomain)
+	if !ok {
+		return database.User{}, fmt.Errorf(
+			"%w, roll number cannot be known from %s, please sign up with this email first",
+			flux_errors.ErrInvalidRequest,
+			identity.Email,
+		)
+	}
+
+	firstName := identity.GivenName
+	if firstName == "" {
+		firstName = rollNo
+	}
+	lastName := identity.FamilyName
+
+	randomPassword, err := generateOIDCSecret()
+	if err != nil {
+		return database.User{}, err
+	}
+	passwordHash, err := generatePasswordHash(randomPassword)
+	if err != nil {
+		return database.User{}, err
+	}
+
+	dbUser, err := a.createUserInDB(
+		ctx,
+		UserRegestration{
+			FirstName: firstName,
+			LastName:  lastName,
+			RollNo:    rollNo,
+			UserMail:  identity.Email,
+		},
+		passwordHash,
+	)
+	if errors.Is(err, flux_errors.ErrInvalidRequest) {
+		// the email was not found, so it is the roll number that is taken
+		log.Warnf("roll number %s of %s belongs to another user", rollNo, identity.Email)
+		return database.User{}, fmt.Errorf(
+			"%w, an account with roll number %s already exists, please log in with its email",
+			flux_errors.ErrInvalidRequest,
+			rollNo,
+		)
+	}
+
+	return dbUser, err
+}
+
+// OIDCRollNo returns the roll number of the email. Emails of the college
+// start with the roll number of the student, it is not known for the others.
+func OIDCRollNo(email string, collegeDomain string) (string, bool) {
+	localPart, domain, found := strings.Cut(email, "@")
+	if collegeDomain == "" || !found || localPart == "" || !strings.EqualFold(domain, collegeDomain) {
+		return "", false
+	}
+
+	return strings.ToLower(localPart), true
+}
+
+// generateOIDCSecret returns a random value for the state, the nonce and the
+// pkce verifier
+func generateOIDCSecret() (string, error) {
+	secretBytes := make([]byte, 32)
+	_, err := rand.Read(secretBytes)
+	if err != nil {
+		log.Errorf("failed to generate random bytes: %v", err)
+		return "", errors.Join(flux_errors.ErrInternal, err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(secretBytes), nil
+}
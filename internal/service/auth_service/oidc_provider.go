@@ -0,0 +1,436 @@
+package auth_service
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+)
+
+const oidcRequestTimeout = 10 * time.Second
+
+// OIDCConfig is the client registered with the identity provider
+type OIDCConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// callback of the api registered with the provider
+	RedirectURL string
+	// if set, only the emails of the domain can log in
+	AllowedDomain string
+	// where the browser is sent after logging in
+	PostLoginURL string
+}
+
+// OIDCIdentity is the verified account of the user at the identity provider
+type OIDCIdentity struct {
+	Issuer     string
+	Subject    string
+	Email      string
+	GivenName  string
+	FamilyName string
+}
+
+// OIDCProvider is an openid connect client using the authorization code flow
+// with PKCE. The endpoints and the keys of the provider are discovered on
+// first use.
+type OIDCProvider struct {
+	config     OIDCConfig
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]*rsa.PublicKey
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+type oidcTokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type oidcJWKS struct {
+	Keys []struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+type oidcIDTokenClaims struct {
+	Nonce         string `json:"nonce"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+	jwt.RegisteredClaims
+}
+
+func NewOIDCProvider(config OIDCConfig) *OIDCProvider {
+	return &OIDCProvider{
+		config:     config,
+		httpClient: &http.Client{Timeout: oidcRequestTimeout},
+	}
+}
+
+// PKCEChallenge derives the S256 code challenge sent with the authorization
+// request from the verifier sent with the code exchange
+func PKCEChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// PostLoginURL is where the browser is sent after logging in
+func (p *OIDCProvider) PostLoginURL() string {
+	return p.config.PostLoginURL
+}
+
+// AuthCodeURL returns the url of the provider the user logs in at
+func (p *OIDCProvider) AuthCodeURL(
+	ctx context.Context,
+	state string,
+	nonce string,
+	codeVerifier string,
+) (string, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(discovery.AuthorizationEndpoint)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, invalid authorization endpoint %s of oidc provider, %w",
+			flux_errors.ErrInternal,
+			discovery.AuthorizationEndpoint,
+			err,
+		)
+		log.Error(err)
+		return "", err
+	}
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", "openid email profile")
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", PKCEChallenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+	if p.config.AllowedDomain != "" {
+		// only a hint for the account chooser, the email is checked anyway
+		query.Set("hd", p.config.AllowedDomain)
+	}
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+// Exchange trades the authorization code for the id token of the user
+func (p *OIDCProvider) Exchange(
+	ctx context.Context,
+	code string,
+	codeVerifier string,
+) (string, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+
+	request, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		discovery.TokenEndpoint,
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		err = fmt.Errorf("%w, cannot create oidc token request, %w", flux_errors.ErrInternal, err)
+		log.Error(err)
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	request.SetBasicAuth(
+		url.QueryEscape(p.config.ClientID),
+		url.QueryEscape(p.config.ClientSecret),
+	)
+
+	var tokenResponse oidcTokenResponse
+	statusCode, err := p.doJSON(request, &tokenResponse)
+	if err != nil {
+		return "", err
+	}
+	if statusCode != http.StatusOK || tokenResponse.IDToken == "" {
+		// mostly an expired or reused code
+		return "", fmt.Errorf(
+			"%w, oidc provider rejected the login, %s %s",
+			flux_errors.ErrInvalidRequest,
+			tokenResponse.Error,
+			tokenResponse.ErrorDescription,
+		)
+	}
+
+	return tokenResponse.IDToken, nil
+}
+
+// VerifyIDToken checks the signature, the issuer, the audience, the expiry and
+// the nonce of the id token. The email of the user must be verified.
+func (p *OIDCProvider) VerifyIDToken(
+	ctx context.Context,
+	rawIDToken string,
+	nonce string,
+) (OIDCIdentity, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return OIDCIdentity{}, err
+	}
+
+	claims := oidcIDTokenClaims{}
+	token, err := jwt.ParseWithClaims(
+		rawIDToken,
+		&claims,
+		func(t *jwt.Token) (any, error) {
+			// providers sign the id tokens with their rsa keys
+			if t.Method != jwt.SigningMethodRS256 {
+				return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+			}
+			kid, _ := t.Header["kid"].(string)
+			return p.getKey(ctx, kid)
+		},
+	)
+	if err != nil || !token.Valid {
+		return OIDCIdentity{}, fmt.Errorf(
+			"%w, invalid id token, %v",
+			flux_errors.ErrInvalidRequestCredentials,
+			err,
+		)
+	}
+
+	switch {
+	case claims.Issuer != discovery.Issuer:
+		err = fmt.Errorf("id token is issued by %s", claims.Issuer)
+	case !claims.VerifyAudience(p.config.ClientID, true):
+		err = fmt.Errorf("id token is not meant for this client")
+	case claims.ExpiresAt == nil:
+		err = fmt.Errorf("id token has no expiry")
+	case subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1:
+		err = fmt.Errorf("nonce of the id token does not match")
+	case claims.Subject == "":
+		err = fmt.Errorf("id token has no subject")
+	}
+	if err != nil {
+		return OIDCIdentity{}, fmt.Errorf(
+			"%w, %w",
+			flux_errors.ErrInvalidRequestCredentials,
+			err,
+		)
+	}
+
+	// users are linked by their email, so it must be theirs
+	if claims.Email == "" || !claims.EmailVerified {
+		return OIDCIdentity{}, fmt.Errorf(
+			"%w, email of the account is not verified",
+			flux_errors.ErrInvalidRequestCredentials,
+		)
+	}
+	if p.config.AllowedDomain != "" &&
+		!strings.HasSuffix(strings.ToLower(claims.Email), "@"+strings.ToLower(p.config.AllowedDomain)) {
+		return OIDCIdentity{}, fmt.Errorf(
+			"%w, only %s accounts can log in",
+			flux_errors.ErrInvalidRequestCredentials,
+			p.config.AllowedDomain,
+		)
+	}
+
+	return OIDCIdentity{
+		Issuer:     claims.Issuer,
+		Subject:    claims.Subject,
+		Email:      claims.Email,
+		GivenName:  claims.GivenName,
+		FamilyName: claims.FamilyName,
+	}, nil
+}
+
+func (p *OIDCProvider) getDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	discoveryURL := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		err = fmt.Errorf("%w, cannot create oidc discovery request, %w", flux_errors.ErrInternal, err)
+		log.Error(err)
+		return nil, err
+	}
+
+	var discovery oidcDiscovery
+	statusCode, err := p.doJSON(request, &discovery)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode != http.StatusOK {
+		err = fmt.Errorf(
+			"%w, oidc discovery of %s failed with status %d",
+			flux_errors.ErrInternal,
+			p.config.Issuer,
+			statusCode,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	// a provider must not speak for another issuer
+	if discovery.Issuer != p.config.Issuer {
+		err = fmt.Errorf(
+			"%w, oidc provider %s claims to be %s",
+			flux_errors.ErrInternal,
+			p.config.Issuer,
+			discovery.Issuer,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// getKey returns the signing key with the id, the keys are fetched again for
+// unknown ids as providers rotate them
+func (p *OIDCProvider) getKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	key, ok := p.keys[kid]
+	p.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	keys, err := p.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+
+	key, ok = keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("no signing key with id %s", kid)
+	}
+	return key, nil
+}
+
+func (p *OIDCProvider) fetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, discovery.JwksURI, nil)
+	if err != nil {
+		err = fmt.Errorf("%w, cannot create oidc jwks request, %w", flux_errors.ErrInternal, err)
+		log.Error(err)
+		return nil, err
+	}
+
+	var jwks oidcJWKS
+	statusCode, err := p.doJSON(request, &jwks)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode != http.StatusOK {
+		err = fmt.Errorf(
+			"%w, fetching keys of %s failed with status %d",
+			flux_errors.ErrInternal,
+			p.config.Issuer,
+			statusCode,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			log.Warnf("skipping key %s of oidc provider, invalid modulus", jwk.Kid)
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			log.Warnf("skipping key %s of oidc provider, invalid exponent", jwk.Kid)
+			continue
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	return keys, nil
+}
+
+func (p *OIDCProvider) doJSON(request *http.Request, out any) (int, error) {
+	response, err := p.httpClient.Do(request)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot reach oidc provider at %s, %w",
+			flux_errors.ErrInternal,
+			request.URL.Host,
+			err,
+		)
+		log.Error(err)
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	err = json.NewDecoder(response.Body).Decode(out)
+	if err != nil && response.StatusCode == http.StatusOK {
+		err = fmt.Errorf(
+			"%w, cannot decode response of oidc provider at %s, %w",
+			flux_errors.ErrInternal,
+			request.URL.Host,
+			err,
+		)
+		log.Error(err)
+		return 0, err
+	}
+
+	return response.StatusCode, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/tcp_snm/flux/internal/database"
@@ -16,6 +17,7 @@ func (a *AuthService) ResetPasswordSendMail(
 	ctx context.Context,
 	userName *string,
 	rollNo *string,
+	ipAddress string,
 ) error {
 	// fetch the user from db
 	userMetaData, err := a.UserConfig.GetUserByUserNameOrRollNo(ctx, userName, rollNo)
@@ -32,6 +34,7 @@ func (a *AuthService) ResetPasswordSendMail(
 		ctx,
 		user.Email,
 		email.PurposeEmailPasswordReset,
+		ipAddress,
 	)
 
 	return err
@@ -49,15 +52,35 @@ func (a *AuthService) ResetPassword(
 		},
 	)
 
+	// count the attempt from the address before it is made, parallel
+	// attempts must not get past the limit
+	ip := ipSubject(request.IPAddress, ipPolicy)
+	ipAttempts, err := a.takeAttempts(ctx, throttleActionResetPassword, ip)
+	if err != nil {
+		return err
+	}
+
 	// fetch user from db
 	userMetaData, err := a.UserConfig.GetUserByUserNameOrRollNo(
 		ctx, request.UserName, request.RollNo,
 	)
 	if err != nil {
+		a.refundAttempts(ctx, ipAttempts...)
+		return err
+	}
+
+	// count the attempt on the account
+	account := userSubject(userMetaData.UserID, accountPolicy)
+	accountAttempts, err := a.takeAttempts(ctx, throttleActionResetPassword, account)
+	if err != nil {
+		a.refundAttempts(ctx, ipAttempts...)
 		return err
 	}
+	attempts := slices.Concat(ipAttempts, accountAttempts)
+
 	user, err := a.UserConfig.GetUserProfile(ctx, userMetaData.UserID)
 	if err != nil {
+		a.refundAttempts(ctx, attempts...)
 		return err
 	}
 
@@ -70,10 +93,18 @@ func (a *AuthService) ResetPassword(
 	); err != nil {
 		if errors.Is(err, flux_errors.ErrCorruptedVerification) {
 			resetLogger.Error(err)
+			// guessing tokens is throttled like guessing passwords
+			a.notifyLockout(ctx, user, attempts...)
+		} else {
+			a.refundAttempts(ctx, attempts...)
 		}
 		return err
 	}
 
+	// the token is right, the attempt did not fail
+	a.clearAttempts(ctx, throttleActionResetPassword, account)
+	a.refundAttempts(ctx, ipAttempts...)
+
 	// validate password
 	if err = service.ValidateInput(
 		struct {
@@ -91,8 +122,20 @@ func (a *AuthService) ResetPassword(
 		return err
 	}
 
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := a.DB.WithTx(tx)
+
 	// insert into db
-	if err = a.DB.ResetPassword(ctx, database.ResetPasswordParams{
+	if err = qtx.ResetPassword(ctx, database.ResetPasswordParams{
 		PasswordHash: passwordHash,
 		UserName:     user.UserName,
 	}); err != nil {
@@ -101,6 +144,31 @@ func (a *AuthService) ResetPassword(
 		return err
 	}
 
+	// whoever had the old password may be logged in
+	sessionIDs, err := qtx.RevokeUserSessions(ctx, user.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot revoke sessions of user %s", user.UserName),
+		)
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after resetting password, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		resetLogger.Error(err)
+		return err
+	}
+	resetLogger.Infof("revoked %d sessions after password reset", len(sessionIDs))
+
+	// the owner is back in, a lockout must not keep them out
+	a.clearAttempts(ctx, throttleActionLogin, account)
+
 	// invalidate token
 	a.invalidateVerificationToken(
 		ctx,
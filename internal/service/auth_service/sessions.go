@@ -0,0 +1,286 @@
+package auth_service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// CheckSession tells if the session of the token is still active. Tokens
+// issued before sessions were recorded have no session and are rejected.
+func (a *AuthService) CheckSession(
+	ctx context.Context,
+	claims service.UserCredentialClaims,
+) error {
+	sessionID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return fmt.Errorf(
+			"%w, token of user %s has no session",
+			flux_errors.ErrUnAuthorized,
+			claims.UserName,
+		)
+	}
+
+	dbSession, err := a.DB.GetUserSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf(
+				"%w, session %v does not exist",
+				flux_errors.ErrUnAuthorized,
+				sessionID,
+			)
+		}
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch session %v", sessionID),
+		)
+		return err
+	}
+
+	// the token may outlive its session
+	if dbSession.UserID != claims.UserId ||
+		dbSession.RevokedAt != nil ||
+		time.Now().After(dbSession.ExpiresAt) {
+		return fmt.Errorf(
+			"%w, session %v is revoked or expired",
+			flux_errors.ErrUnAuthorized,
+			sessionID,
+		)
+	}
+
+	return nil
+}
+
+// Logout revokes the session of the token used for the request
+func (a *AuthService) Logout(ctx context.Context) error {
+	// fetch claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	sessionID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return fmt.Errorf(
+			"%w, token has no session",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	_, err = a.DB.RevokeUserSession(
+		ctx,
+		database.RevokeUserSessionParams{
+			ID:     sessionID,
+			UserID: claims.UserId,
+		},
+	)
+	// revoked by another request in the meantime
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot revoke session %v", sessionID),
+		)
+		return err
+	}
+
+	log.Infof("user %s logged out of session %v", claims.UserName, sessionID)
+	return nil
+}
+
+// GetMySessions returns the active sessions of the user, latest first
+func (a *AuthService) GetMySessions(ctx context.Context) ([]Session, error) {
+	// fetch claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dbSessions, err := a.DB.GetActiveUserSessions(ctx, claims.UserId)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch sessions of user %s", claims.UserName),
+		)
+		return nil, err
+	}
+
+	sessions := make([]Session, 0, len(dbSessions))
+	for _, dbSession := range dbSessions {
+		sessions = append(sessions, Session{
+			ID:        dbSession.ID,
+			UserAgent: dbSession.UserAgent,
+			IPAddress: dbSession.IpAddress,
+			CreatedAt: dbSession.CreatedAt,
+			ExpiresAt: dbSession.ExpiresAt,
+			IsCurrent: dbSession.ID.String() == claims.ID,
+		})
+	}
+
+	return sessions, nil
+}
+
+// RevokeMySession revokes one of the sessions of the user
+func (a *AuthService) RevokeMySession(ctx context.Context, sessionID uuid.UUID) error {
+	// fetch claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.DB.RevokeUserSession(
+		ctx,
+		database.RevokeUserSessionParams{
+			ID:     sessionID,
+			UserID: claims.UserId,
+		},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf(
+				"%w, no active session with id %v",
+				flux_errors.ErrNotFound,
+				sessionID,
+			)
+		}
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot revoke session %v", sessionID),
+		)
+		return err
+	}
+
+	log.Infof("user %s revoked their session %v", claims.UserName, sessionID)
+	return nil
+}
+
+// RevokeUserSessions revokes every active session of the user, logging them
+// out of all devices. Returns the ids of the revoked sessions.
+func (a *AuthService) RevokeUserSessions(
+	ctx context.Context,
+	userName string,
+) ([]uuid.UUID, error) {
+	// fetch claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// authorize
+	err = a.UserConfig.Authorize(ctx, user_service.PermSessionManage, user_service.Resource{})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := a.UserConfig.FetchUserByUserName(ctx, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := a.DB.WithTx(tx)
+
+	sessionIDs, err := qtx.RevokeUserSessions(ctx, user.UserID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot revoke sessions of user %s", user.UserName),
+		)
+		return nil, err
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionUserRevokeSessions,
+		EntityType: auditEntityUser,
+		EntityID:   user.UserID,
+		After:      sessionIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf(
+			"%w, cannot commit transaction after revoking sessions of user %s, %w",
+			flux_errors.ErrInternal,
+			user.UserName,
+			err,
+		)
+	}
+
+	log.Infof(
+		"user %s revoked %d sessions of user %s",
+		claims.UserName,
+		len(sessionIDs),
+		user.UserName,
+	)
+	return sessionIDs, nil
+}
+
+// startSession records a new session of the user and issues its token.
+// twoFactor tells if the user logged in with the second factor.
+func (a *AuthService) startSession(
+	ctx context.Context,
+	user user_service.User,
+	duration time.Duration,
+	twoFactor bool,
+	userAgent string,
+	ipAddress string,
+) (tokenString string, tokenExpiry time.Time, err error) {
+	sessionID := uuid.New()
+	tokenExpiry = time.Now().Add(duration)
+
+	// claims store the user data to avoid repeated logins via jwt
+	claims := dbUserToUserCredClaims(tokenExpiry, user)
+	claims.ID = sessionID.String()
+	claims.TwoFactor = twoFactor
+	tokenString, err = GenerateJWT(claims)
+	if err != nil {
+		return
+	}
+
+	_, err = a.DB.CreateUserSession(
+		ctx,
+		database.CreateUserSessionParams{
+			ID:        sessionID,
+			UserID:    user.ID,
+			UserAgent: userAgent,
+			IpAddress: ipAddress,
+			ExpiresAt: tokenExpiry,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot create session of user %s", user.UserName),
+		)
+		tokenString = ""
+		return
+	}
+
+	return
+}
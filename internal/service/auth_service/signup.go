@@ -154,6 +154,7 @@ func genRandUserName(firstName string, lastName string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	name := strings.ToLower("flux#" + lastName + firstName[:3] + strconv.Itoa(suffix))
+	// names from identity providers may be shorter than the signup allows
+	name := strings.ToLower("flux#" + lastName + firstName[:min(3, len(firstName))] + strconv.Itoa(suffix))
 	return name, nil
 }
@@ -0,0 +1,284 @@
+package auth_service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/email"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// ThrottlePolicy tells how long a subject is blocked after its recent
+// attempts of an action
+type ThrottlePolicy struct {
+	// attempts allowed before the backoff starts
+	FreeAttempts int
+	// block after the first attempt over the free ones, doubled with every
+	// next attempt up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// the subject is locked out for LockoutFor after LockoutAfter attempts,
+	// zero if it never is
+	LockoutAfter int
+	LockoutFor   time.Duration
+	// attempts are forgotten after this long without one
+	ForgetAfter time.Duration
+}
+
+// BlockFor returns how long the subject is blocked after the attempts and if
+// it is a lockout
+func (p ThrottlePolicy) BlockFor(attempts int) (time.Duration, bool) {
+	if p.LockoutAfter > 0 && attempts >= p.LockoutAfter {
+		return p.LockoutFor, true
+	}
+
+	over := attempts - p.FreeAttempts
+	if over <= 0 {
+		return 0, false
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < over && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay), false
+}
+
+// throttleSubject is who the attempts are counted against, an empty key is
+// not counted
+type throttleSubject struct {
+	key    string
+	policy ThrottlePolicy
+}
+
+func userSubject(userID uuid.UUID, policy ThrottlePolicy) throttleSubject {
+	return throttleSubject{key: "user:" + userID.String(), policy: policy}
+}
+
+func ipSubject(ipAddress string, policy ThrottlePolicy) throttleSubject {
+	if ipAddress == "" {
+		return throttleSubject{}
+	}
+	return throttleSubject{key: "ip:" + ipAddress, policy: policy}
+}
+
+func emailSubject(userEmail string, policy ThrottlePolicy) throttleSubject {
+	return throttleSubject{key: "email:" + strings.ToLower(userEmail), policy: policy}
+}
+
+// throttleAttempt is an attempt counted against a subject before it is made
+type throttleAttempt struct {
+	action   string
+	subject  throttleSubject
+	attempts int
+}
+
+// lockoutStarted tells if the attempt locked the subject out
+func (t throttleAttempt) lockoutStarted() bool {
+	return t.subject.policy.LockoutAfter > 0 && t.attempts == t.subject.policy.LockoutAfter
+}
+
+// takeAttempts counts an attempt of the action against the subjects before it
+// is made and blocks them as their policies say, failing if any of them is
+// blocked already. The count and the block are written in one transaction
+// holding the rows, so parallel attempts cannot get past the policies.
+func (a *AuthService) takeAttempts(
+	ctx context.Context,
+	action string,
+	subjects ...throttleSubject,
+) ([]throttleAttempt, error) {
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// if anything goes wrong roll back, attempts of a blocked subject are
+	// not counted either
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := a.DB.WithTx(tx)
+
+	taken := make([]throttleAttempt, 0, len(subjects))
+	var blocked []string
+	var blockedUntil time.Time
+	for _, subject := range subjects {
+		if subject.key == "" {
+			continue
+		}
+
+		// the row stays locked until the commit, the next attempt waits for
+		// the count and the block of this one
+		attempt, err := qtx.RecordAuthAttempt(
+			ctx,
+			database.RecordAuthAttemptParams{
+				Action:       action,
+				Subject:      subject.key,
+				ForgetBefore: time.Now().Add(-subject.policy.ForgetAfter),
+			},
+		)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot record %s attempt of %s", action, subject.key),
+			)
+			return nil, err
+		}
+
+		// wait for the longest block
+		if attempt.BlockedUntil != nil && attempt.BlockedUntil.After(time.Now()) {
+			blocked = append(blocked, subject.key)
+			if attempt.BlockedUntil.After(blockedUntil) {
+				blockedUntil = *attempt.BlockedUntil
+			}
+			continue
+		}
+
+		taken = append(taken, throttleAttempt{
+			action:   action,
+			subject:  subject,
+			attempts: int(attempt.Attempts),
+		})
+
+		blockFor, _ := subject.policy.BlockFor(int(attempt.Attempts))
+		if blockFor == 0 {
+			continue
+		}
+		until := time.Now().Add(blockFor)
+		err = qtx.BlockAuthAttempts(
+			ctx,
+			database.BlockAuthAttemptsParams{
+				Action:       action,
+				Subject:      subject.key,
+				BlockedUntil: &until,
+			},
+		)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot block %s attempts of %s", action, subject.key),
+			)
+			return nil, err
+		}
+	}
+
+	if len(blocked) > 0 {
+		log.Warnf("blocked %s attempt of %v until %v", action, blocked, blockedUntil)
+		return nil, fmt.Errorf(
+			"%w, please try again in %v",
+			flux_errors.ErrTooManyAttempts,
+			time.Until(blockedUntil).Truncate(time.Second)+time.Second,
+		)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after recording %s attempt, %w",
+			flux_errors.ErrInternal,
+			action,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	// a fresh count, a good time to drop the forgotten ones
+	for _, attempt := range taken {
+		if attempt.attempts != 1 {
+			continue
+		}
+		err = a.DB.DeleteStaleAuthAttempts(ctx, time.Now().Add(-authAttemptsRetention))
+		if err != nil {
+			log.Warnf("cannot delete stale auth attempts, %v", err)
+		}
+		break
+	}
+
+	return taken, nil
+}
+
+// refundAttempts gives back the attempts that did not fail, blocks they have
+// started are kept
+func (a *AuthService) refundAttempts(ctx context.Context, attempts ...throttleAttempt) {
+	for _, attempt := range attempts {
+		err := a.DB.RefundAuthAttempt(
+			ctx,
+			database.RefundAuthAttemptParams{
+				Action:  attempt.action,
+				Subject: attempt.subject.key,
+			},
+		)
+		if err != nil {
+			log.Warnf(
+				"cannot refund %s attempt of %s, %v",
+				attempt.action,
+				attempt.subject.key,
+				err,
+			)
+		}
+	}
+}
+
+// notifyLockout lets the user know if the failed attempts locked their
+// account
+func (a *AuthService) notifyLockout(
+	ctx context.Context,
+	user user_service.User,
+	attempts ...throttleAttempt,
+) {
+	for _, attempt := range attempts {
+		if !attempt.lockoutStarted() {
+			continue
+		}
+
+		log.Warnf("locked out user %s after failed %s attempts", user.UserName, attempt.action)
+		err := email.NewMail(
+			ctx,
+			"Flux account locked",
+			fmt.Sprintf(
+				"your flux account %s was locked for %v after %d failed %s attempts. "+
+					"if this was not you, please reset your password and enable two factor authentication.",
+				user.UserName,
+				attempt.subject.policy.LockoutFor,
+				attempt.subject.policy.LockoutAfter,
+				strings.ReplaceAll(attempt.action, "_", " "),
+			),
+			email.KeyEmailBodyPlain,
+			email.PurposeAccountLocked,
+			user.Email,
+		)
+		if err != nil {
+			// the account stays locked anyway
+			log.Errorf("cannot send lockout notice to user %s, %v", user.UserName, err)
+		}
+		return
+	}
+}
+
+// clearAttempts forgets the attempts of the subject after a success
+func (a *AuthService) clearAttempts(
+	ctx context.Context,
+	action string,
+	subject throttleSubject,
+) {
+	err := a.DB.ClearAuthAttempts(
+		ctx,
+		database.ClearAuthAttemptsParams{
+			Action:  action,
+			Subject: subject.key,
+		},
+	)
+	if err != nil {
+		log.Warnf("cannot clear %s attempts of %s, %v", action, subject.key, err)
+	}
+}
@@ -0,0 +1,658 @@
+package auth_service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// GetTwoFactorStatus tells if the user has enabled two factor authentication
+// and if their roles need it
+func (a *AuthService) GetTwoFactorStatus(ctx context.Context) (TwoFactorStatus, error) {
+	// fetch claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return TwoFactorStatus{}, err
+	}
+
+	status, err := a.twoFactorStatus(ctx, a.DB, claims.UserId)
+	if err != nil {
+		return TwoFactorStatus{}, err
+	}
+
+	status.Required, err = a.UserConfig.IsTwoFactorRequired(ctx, claims.UserId)
+	if err != nil {
+		return TwoFactorStatus{}, err
+	}
+
+	return status, nil
+}
+
+// StartTOTPEnrolment creates a new totp secret for the user. The secret is
+// pending until it is confirmed with a code of the authenticator app.
+func (a *AuthService) StartTOTPEnrolment(ctx context.Context) (TOTPEnrolment, error) {
+	claims, err := getSessionClaims(ctx)
+	if err != nil {
+		return TOTPEnrolment{}, err
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return TOTPEnrolment{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return TOTPEnrolment{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := a.DB.WithTx(tx)
+
+	_, err = qtx.CreatePendingUserTOTP(
+		ctx,
+		database.CreatePendingUserTOTPParams{
+			UserID: claims.UserId,
+			Secret: secret,
+		},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TOTPEnrolment{}, fmt.Errorf(
+				"%w, two factor authentication is already enabled, disable it first",
+				flux_errors.ErrInvalidRequest,
+			)
+		}
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot create totp secret of user %s", claims.UserName),
+		)
+		return TOTPEnrolment{}, err
+	}
+
+	// record the change, without the secret
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionUserStartTOTP,
+		EntityType: auditEntityUser,
+		EntityID:   claims.UserId,
+	})
+	if err != nil {
+		return TOTPEnrolment{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return TOTPEnrolment{}, fmt.Errorf(
+			"%w, cannot commit transaction after creating totp secret of user %s, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			err,
+		)
+	}
+
+	log.Infof("user %s started totp enrolment", claims.UserName)
+	return TOTPEnrolment{
+		Secret:          secret,
+		ProvisioningURI: TOTPProvisioningURI(secret, claims.UserName),
+	}, nil
+}
+
+// ConfirmTOTPEnrolment enables two factor authentication once the code of the
+// pending secret is entered, and returns the recovery codes. The current
+// session stays without the second factor, the user has to log in again.
+func (a *AuthService) ConfirmTOTPEnrolment(
+	ctx context.Context,
+	request ConfirmTOTPRequest,
+) (RecoveryCodes, error) {
+	claims, err := getSessionClaims(ctx)
+	if err != nil {
+		return RecoveryCodes{}, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return RecoveryCodes{}, err
+	}
+
+	dbTOTP, err := a.DB.GetUserTOTP(ctx, claims.UserId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return RecoveryCodes{}, fmt.Errorf(
+				"%w, start the totp enrolment first",
+				flux_errors.ErrInvalidRequest,
+			)
+		}
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch totp secret of user %s", claims.UserName),
+		)
+		return RecoveryCodes{}, err
+	}
+	if dbTOTP.EnabledAt != nil {
+		return RecoveryCodes{}, fmt.Errorf(
+			"%w, two factor authentication is already enabled",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// the app must have the secret
+	step := MatchTOTPStep(dbTOTP.Secret, request.Code, time.Now(), 0)
+	if step == 0 {
+		return RecoveryCodes{}, fmt.Errorf(
+			"%w, invalid totp code",
+			flux_errors.ErrInvalidRequestCredentials,
+		)
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return RecoveryCodes{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := a.DB.WithTx(tx)
+
+	_, err = qtx.EnableUserTOTP(
+		ctx,
+		database.EnableUserTOTPParams{
+			UserID:       claims.UserId,
+			LastUsedStep: step,
+		},
+	)
+	if err != nil {
+		// confirmed by another request in the meantime
+		if errors.Is(err, sql.ErrNoRows) {
+			return RecoveryCodes{}, fmt.Errorf(
+				"%w, two factor authentication is already enabled",
+				flux_errors.ErrInvalidRequest,
+			)
+		}
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot enable totp of user %s", claims.UserName),
+		)
+		return RecoveryCodes{}, err
+	}
+
+	recoveryCodes, err := a.replaceRecoveryCodes(ctx, qtx, claims.UserId)
+	if err != nil {
+		return RecoveryCodes{}, err
+	}
+
+	// record the change
+	status, err := a.twoFactorStatus(ctx, qtx, claims.UserId)
+	if err != nil {
+		return RecoveryCodes{}, err
+	}
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionUserEnableTOTP,
+		EntityType: auditEntityUser,
+		EntityID:   claims.UserId,
+		After:      status,
+	})
+	if err != nil {
+		return RecoveryCodes{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return RecoveryCodes{}, fmt.Errorf(
+			"%w, cannot commit transaction after enabling totp of user %s, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			err,
+		)
+	}
+
+	log.Infof("user %s enabled two factor authentication", claims.UserName)
+	return RecoveryCodes{RecoveryCodes: recoveryCodes}, nil
+}
+
+// DisableTOTP turns off two factor authentication, the user must prove the
+// second factor one last time
+func (a *AuthService) DisableTOTP(ctx context.Context, request TwoFactorRequest) error {
+	claims, err := getSessionClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := a.DB.WithTx(tx)
+
+	before, err := a.checkEnabledSecondFactor(ctx, qtx, claims, request)
+	if err != nil {
+		return err
+	}
+
+	_, err = qtx.DeleteUserTOTP(ctx, claims.UserId)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot delete totp secret of user %s", claims.UserName),
+		)
+		return err
+	}
+	err = qtx.DeleteUserRecoveryCodes(ctx, claims.UserId)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot delete recovery codes of user %s", claims.UserName),
+		)
+		return err
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionUserDisableTOTP,
+		EntityType: auditEntityUser,
+		EntityID:   claims.UserId,
+		Before:     before,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf(
+			"%w, cannot commit transaction after disabling totp of user %s, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			err,
+		)
+	}
+
+	log.Infof("user %s disabled two factor authentication", claims.UserName)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user, the old
+// ones stop working
+func (a *AuthService) RegenerateRecoveryCodes(
+	ctx context.Context,
+	request TwoFactorRequest,
+) (RecoveryCodes, error) {
+	claims, err := getSessionClaims(ctx)
+	if err != nil {
+		return RecoveryCodes{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return RecoveryCodes{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := a.DB.WithTx(tx)
+
+	before, err := a.checkEnabledSecondFactor(ctx, qtx, claims, request)
+	if err != nil {
+		return RecoveryCodes{}, err
+	}
+
+	recoveryCodes, err := a.replaceRecoveryCodes(ctx, qtx, claims.UserId)
+	if err != nil {
+		return RecoveryCodes{}, err
+	}
+
+	// record the change
+	after, err := a.twoFactorStatus(ctx, qtx, claims.UserId)
+	if err != nil {
+		return RecoveryCodes{}, err
+	}
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionUserNewRecoveryCodes,
+		EntityType: auditEntityUser,
+		EntityID:   claims.UserId,
+		Before:     before,
+		After:      after,
+	})
+	if err != nil {
+		return RecoveryCodes{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return RecoveryCodes{}, fmt.Errorf(
+			"%w, cannot commit transaction after replacing recovery codes of user %s, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			err,
+		)
+	}
+
+	log.Infof("user %s replaced their recovery codes", claims.UserName)
+	return RecoveryCodes{RecoveryCodes: recoveryCodes}, nil
+}
+
+// loginSecondFactor is the second step of the login. It tells if the second
+// factor was checked, users without two factor authentication skip it.
+func (a *AuthService) loginSecondFactor(
+	ctx context.Context,
+	userID uuid.UUID,
+	request UserLoginRequest,
+) (bool, error) {
+	dbTOTP, err := a.DB.GetUserTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch totp secret of user %v", userID),
+		)
+		return false, err
+	}
+	if dbTOTP.EnabledAt == nil {
+		return false, nil
+	}
+
+	err = a.checkSecondFactor(ctx, a.DB, dbTOTP, request.TOTPCode, request.RecoveryCode)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// checkEnabledSecondFactor checks the second factor of a user who must have
+// enabled two factor authentication, wrong codes are throttled like failed
+// logins. Returns the status before the change.
+func (a *AuthService) checkEnabledSecondFactor(
+	ctx context.Context,
+	db *database.Queries,
+	claims service.UserCredentialClaims,
+	request TwoFactorRequest,
+) (TwoFactorStatus, error) {
+	dbTOTP, err := db.GetUserTOTP(ctx, claims.UserId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch totp secret of user %s", claims.UserName),
+		)
+		return TwoFactorStatus{}, err
+	}
+	if err != nil || dbTOTP.EnabledAt == nil {
+		return TwoFactorStatus{}, fmt.Errorf(
+			"%w, two factor authentication is not enabled",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	status, err := a.twoFactorStatus(ctx, db, claims.UserId)
+	if err != nil {
+		return TwoFactorStatus{}, err
+	}
+
+	// a wrong code counts like a wrong password, a stolen session must not
+	// be able to guess the codes
+	account := userSubject(claims.UserId, accountPolicy)
+	attempts, err := a.takeAttempts(ctx, throttleActionLogin, account)
+	if err != nil {
+		return TwoFactorStatus{}, err
+	}
+
+	err = a.checkSecondFactor(ctx, db, dbTOTP, request.TOTPCode, request.RecoveryCode)
+	if err != nil {
+		if !errors.Is(err, flux_errors.ErrInvalidRequestCredentials) {
+			a.refundAttempts(ctx, attempts...)
+			return TwoFactorStatus{}, err
+		}
+		if slices.ContainsFunc(attempts, throttleAttempt.lockoutStarted) {
+			user, userErr := a.UserConfig.GetUserProfile(ctx, claims.UserId)
+			if userErr != nil {
+				log.Errorf("cannot notify lockout of user %s, %v", claims.UserName, userErr)
+			} else {
+				a.notifyLockout(ctx, user, attempts...)
+			}
+		}
+		return TwoFactorStatus{}, err
+	}
+
+	// the account starts over as with a login
+	a.clearAttempts(ctx, throttleActionLogin, account)
+
+	return status, nil
+}
+
+// checkSecondFactor checks the totp code or else the recovery code, using it
+// up so that it cannot be replayed
+func (a *AuthService) checkSecondFactor(
+	ctx context.Context,
+	db *database.Queries,
+	dbTOTP database.UserTotp,
+	totpCode string,
+	recoveryCode string,
+) error {
+	switch {
+	case totpCode != "":
+		step := MatchTOTPStep(dbTOTP.Secret, totpCode, time.Now(), dbTOTP.LastUsedStep)
+		if step == 0 {
+			return fmt.Errorf("%w, invalid totp code", flux_errors.ErrInvalidRequestCredentials)
+		}
+
+		_, err := db.UseUserTOTPStep(
+			ctx,
+			database.UseUserTOTPStepParams{
+				UserID:       dbTOTP.UserID,
+				LastUsedStep: step,
+			},
+		)
+		if err != nil {
+			// used by another request in the meantime
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w, invalid totp code", flux_errors.ErrInvalidRequestCredentials)
+			}
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot use totp code of user %v", dbTOTP.UserID),
+			)
+			return err
+		}
+	case recoveryCode != "":
+		_, err := db.UseUserRecoveryCode(
+			ctx,
+			database.UseUserRecoveryCodeParams{
+				UserID:     dbTOTP.UserID,
+				HashedCode: hashRecoveryCode(recoveryCode),
+			},
+		)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf(
+					"%w, invalid or used recovery code",
+					flux_errors.ErrInvalidRequestCredentials,
+				)
+			}
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot use recovery code of user %v", dbTOTP.UserID),
+			)
+			return err
+		}
+		log.Warnf("user %v used a recovery code", dbTOTP.UserID)
+	default:
+		return fmt.Errorf(
+			"%w, enter the code of your authenticator app or a recovery code",
+			flux_errors.ErrTwoFactorRequired,
+		)
+	}
+
+	return nil
+}
+
+func (a *AuthService) twoFactorStatus(
+	ctx context.Context,
+	db *database.Queries,
+	userID uuid.UUID,
+) (TwoFactorStatus, error) {
+	dbTOTP, err := db.GetUserTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TwoFactorStatus{}, nil
+		}
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch totp secret of user %v", userID),
+		)
+		return TwoFactorStatus{}, err
+	}
+	if dbTOTP.EnabledAt == nil {
+		return TwoFactorStatus{}, nil
+	}
+
+	left, err := db.CountUnusedUserRecoveryCodes(ctx, userID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot count recovery codes of user %v", userID),
+		)
+		return TwoFactorStatus{}, err
+	}
+
+	return TwoFactorStatus{
+		Enabled:           true,
+		EnabledAt:         dbTOTP.EnabledAt,
+		RecoveryCodesLeft: left,
+	}, nil
+}
+
+// replaceRecoveryCodes stores new recovery codes of the user and returns them
+func (a *AuthService) replaceRecoveryCodes(
+	ctx context.Context,
+	db *database.Queries,
+	userID uuid.UUID,
+) ([]string, error) {
+	recoveryCodes := make([]string, 0, recoveryCodeCount)
+	hashedCodes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		recoveryCode, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		recoveryCodes = append(recoveryCodes, recoveryCode)
+		hashedCodes = append(hashedCodes, hashRecoveryCode(recoveryCode))
+	}
+
+	err := db.DeleteUserRecoveryCodes(ctx, userID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot delete recovery codes of user %v", userID),
+		)
+		return nil, err
+	}
+
+	err = db.AddUserRecoveryCodes(
+		ctx,
+		database.AddUserRecoveryCodesParams{
+			UserID:      userID,
+			HashedCodes: hashedCodes,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot add recovery codes of user %v", userID),
+		)
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// getSessionClaims returns the claims of a request made with a session, the
+// second factor cannot be changed with an access token
+func getSessionClaims(ctx context.Context) (service.UserCredentialClaims, error) {
+	// fetch claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return service.UserCredentialClaims{}, err
+	}
+
+	if claims.AccessTokenID != nil {
+		return service.UserCredentialClaims{}, fmt.Errorf(
+			"%w, two factor authentication cannot be changed with an access token",
+			flux_errors.ErrUnAuthorized,
+		)
+	}
+
+	return claims, nil
+}
+
+func generateTOTPSecret() (string, error) {
+	// 160 bits, as recommended by RFC 4226
+	secretBytes := make([]byte, 20)
+	_, err := rand.Read(secretBytes)
+	if err != nil {
+		log.Errorf("failed to generate random bytes: %v", err)
+		return "", errors.Join(flux_errors.ErrInternal, err)
+	}
+
+	return totpEncoding.EncodeToString(secretBytes), nil
+}
+
+// generateRecoveryCode returns a code like abcd-efgh-ijkl-mnop
+func generateRecoveryCode() (string, error) {
+	codeBytes := make([]byte, 10)
+	_, err := rand.Read(codeBytes)
+	if err != nil {
+		log.Errorf("failed to generate random bytes: %v", err)
+		return "", errors.Join(flux_errors.ErrInternal, err)
+	}
+
+	code := strings.ToLower(totpEncoding.EncodeToString(codeBytes))
+	return code[0:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:16], nil
+}
+
+// recovery codes are random like access tokens, a fast hash is enough. The
+// dashes and the case are ignored.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
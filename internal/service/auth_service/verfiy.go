@@ -24,6 +24,7 @@ func (a *AuthService) SendVerificationEmail(
 	ctx context.Context,
 	userEmail string,
 	verifyPurpose email.EmailPurpose,
+	ipAddress string,
 ) error {
 	// validate the email
 	if err := service.ValidateInput(
@@ -37,6 +38,16 @@ func (a *AuthService) SendVerificationEmail(
 		return err
 	}
 
+	// stop flooding the mailbox
+	subjects := []throttleSubject{
+		emailSubject(userEmail, mailboxPolicy),
+		ipSubject(ipAddress, mailIPPolicy),
+	}
+	_, err := a.takeAttempts(ctx, throttleActionMail, subjects...)
+	if err != nil {
+		return err
+	}
+
 	// create a new token
 	plainToken, err := generateToken()
 	if err != nil {
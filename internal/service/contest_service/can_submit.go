@@ -16,6 +16,7 @@ func (c *ContestService) CanSubmit(
 	ctx context.Context,
 	problemID int32,
 	contestID uuid.UUID,
+	virtualParticipationID *uuid.UUID,
 ) error {
 	// get claims from contest
 	claims, err := service.GetClaimsFromContext(ctx)
@@ -29,6 +30,17 @@ func (c *ContestService) CanSubmit(
 		return err
 	}
 
+	// virtual participants submit within their own window
+	if virtualParticipationID != nil {
+		return c.canSubmitVirtually(
+			ctx,
+			claims,
+			problemID,
+			contestID,
+			*virtualParticipationID,
+		)
+	}
+
 	// check if the contest has been started
 	if contest.StartTime == nil {
 		err = fmt.Errorf(
@@ -64,6 +76,32 @@ func (c *ContestService) CanSubmit(
 	}
 
 	// check if the problem is in the contest
+	err = c.validateContestProblem(ctx, claims, problemID, contestID)
+	if err != nil {
+		return err
+	}
+
+	// check if the user can take part in the contest
+	err = c.authorizeContestParticipant(ctx, contest)
+	if err != nil {
+		log.Warnf(
+			"unregistered user %s tried to submit solution to problem with id %v to contest with id %v",
+			claims.UserName,
+			problemID,
+			contestID,
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (c *ContestService) validateContestProblem(
+	ctx context.Context,
+	claims service.UserCredentialClaims,
+	problemID int32,
+	contestID uuid.UUID,
+) error {
 	has := false
 	problems, err := c.GetContestProblems(ctx, contestID)
 	if err != nil {
@@ -88,32 +126,28 @@ func (c *ContestService) CanSubmit(
 		)
 	}
 
-	// check if the contest is public and published
-	if contest.LockId != nil && contest.IsPublished {
-		return nil
+	return nil
+}
+
+func (c *ContestService) authorizeRegisteredUser(
+	ctx context.Context,
+	contestID uuid.UUID,
+) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
 	}
 
-	// check if the user is registered to the contest
-	registered := false
 	registeredUsers, err := c.GetContestRegisteredUsers(ctx, contestID)
 	if err != nil {
 		return err
 	}
 	for _, user := range registeredUsers {
 		if claims.UserId == user.UserID {
-			registered = true
-			break
+			return nil
 		}
 	}
-	if !registered {
-		log.Warnf(
-			"unregistered user %s tried to submit solution to problem with id %v to contest with id %v",
-			claims.UserName,
-			problemID,
-			contestID,
-		)
-		return flux_errors.ErrUnAuthorized
-	}
 
-	return nil
-}
\ No newline at end of file
+	return flux_errors.ErrUnAuthorized
+}
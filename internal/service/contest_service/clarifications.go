@@ -0,0 +1,287 @@
+package contest_service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+func (c *ContestService) AskClarification(
+	ctx context.Context,
+	request AskClarificationRequest,
+) (Clarification, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return Clarification{}, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return Clarification{}, err
+	}
+
+	// get the contest
+	contest, err := c.GetContestByID(ctx, request.ContestID)
+	if err != nil {
+		return Clarification{}, err
+	}
+
+	// questions can be asked only while the contest is running
+	if contest.StartTime == nil {
+		err = fmt.Errorf(
+			"%w, contest %v has start time as nil, cannot ask clarification",
+			flux_errors.ErrInternal,
+			contest.ID,
+		)
+		log.Error(err)
+		return Clarification{}, err
+	}
+	now := time.Now()
+	if now.Before(*contest.StartTime) || now.After(contest.EndTime) {
+		return Clarification{}, fmt.Errorf(
+			"%w, clarifications can be asked only while the contest is running",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// only participants can ask questions
+	err = c.authorizeContestParticipant(ctx, contest)
+	if err != nil {
+		log.Warnf(
+			"user %s tried to ask clarification in contest %v without participating",
+			claims.UserName,
+			contest.ID,
+		)
+		return Clarification{}, err
+	}
+
+	// insert into db, the problem is validated by its foreign key
+	dbClarification, err := c.DB.CreateClarification(
+		ctx,
+		database.CreateClarificationParams{
+			ContestID: request.ContestID,
+			ProblemID: request.ProblemID,
+			AskedBy:   claims.UserId,
+			Question:  request.Question,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf(
+				"cannot create clarification of user %s in contest %v",
+				claims.UserName,
+				request.ContestID,
+			),
+		)
+		return Clarification{}, err
+	}
+
+	return dbClarificationToServiceClarification(dbClarification), nil
+}
+
+func (c *ContestService) AnswerClarification(
+	ctx context.Context,
+	request AnswerClarificationRequest,
+) (Clarification, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return Clarification{}, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return Clarification{}, err
+	}
+
+	// get the clarification
+	dbClarification, err := c.DB.GetClarificationByID(ctx, request.ClarificationID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch clarification with id %v", request.ClarificationID),
+		)
+		return Clarification{}, err
+	}
+
+	// get the contest
+	contest, err := c.GetContestByID(ctx, dbClarification.ContestID)
+	if err != nil {
+		return Clarification{}, err
+	}
+
+	// authorize
+	err = c.authorizeContestJury(
+		ctx,
+		contest,
+		fmt.Sprintf(
+			"user %s tried to answer clarification %v of contest %v",
+			claims.UserName,
+			request.ClarificationID,
+			contest.ID,
+		),
+	)
+	if err != nil {
+		return Clarification{}, err
+	}
+
+	// answer
+	dbClarification, err = c.DB.AnswerClarification(
+		ctx,
+		database.AnswerClarificationParams{
+			ID:          request.ClarificationID,
+			Answer:      &request.Answer,
+			AnsweredBy:  &claims.UserId,
+			IsBroadcast: request.Broadcast,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot answer clarification with id %v", request.ClarificationID),
+		)
+		return Clarification{}, err
+	}
+
+	return dbClarificationToServiceClarification(dbClarification), nil
+}
+
+// GetClarifications returns the clarifications visible to the user. Jury can see
+// every clarification while participants see their own questions and the broadcasts.
+// If since is not nil, only the clarifications updated after it are returned so that
+// clients can poll for updates.
+func (c *ContestService) GetClarifications(
+	ctx context.Context,
+	contestID uuid.UUID,
+	since *time.Time,
+) ([]Clarification, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// get the contest
+	contest, err := c.GetContestByID(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+
+	// jury can see all the clarifications
+	isJury, err := c.isContestJury(ctx, contest)
+	if err != nil {
+		return nil, err
+	}
+	var askedBy *uuid.UUID
+	if !isJury {
+		// others must be participating in the contest
+		err = c.authorizeContestParticipant(ctx, contest)
+		if err != nil {
+			return nil, err
+		}
+		askedBy = &claims.UserId
+	}
+
+	dbClarifications, err := c.DB.GetContestClarifications(
+		ctx,
+		database.GetContestClarificationsParams{
+			ContestID:    contestID,
+			AskedBy:      askedBy,
+			UpdatedSince: since,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch clarifications of contest %v", contestID),
+		)
+		return nil, err
+	}
+
+	res := make([]Clarification, 0, len(dbClarifications))
+	for _, dbClarification := range dbClarifications {
+		res = append(res, dbClarificationToServiceClarification(dbClarification))
+	}
+
+	return res, nil
+}
+
+func (c *ContestService) GetUnansweredClarifications(
+	ctx context.Context,
+	contestID uuid.UUID,
+) ([]Clarification, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// get the contest
+	contest, err := c.GetContestByID(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+
+	// authorize
+	err = c.authorizeContestJury(
+		ctx,
+		contest,
+		fmt.Sprintf(
+			"user %s tried to fetch unanswered clarifications of contest %v",
+			claims.UserName,
+			contestID,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dbClarifications, err := c.DB.GetUnansweredClarifications(ctx, contestID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch unanswered clarifications of contest %v", contestID),
+		)
+		return nil, err
+	}
+
+	res := make([]Clarification, 0, len(dbClarifications))
+	for _, dbClarification := range dbClarifications {
+		res = append(res, dbClarificationToServiceClarification(dbClarification))
+	}
+
+	return res, nil
+}
+
+func dbClarificationToServiceClarification(
+	dbClarification database.Clarification,
+) Clarification {
+	return Clarification{
+		ID:          dbClarification.ID,
+		ContestID:   dbClarification.ContestID,
+		ProblemID:   dbClarification.ProblemID,
+		AskedBy:     dbClarification.AskedBy,
+		Question:    dbClarification.Question,
+		Answer:      dbClarification.Answer,
+		AnsweredBy:  dbClarification.AnsweredBy,
+		IsBroadcast: dbClarification.IsBroadcast,
+		CreatedAt:   dbClarification.CreatedAt.UTC(),
+		UpdatedAt:   dbClarification.UpdatedAt.UTC(),
+		AnsweredAt:  dbClarification.AnsweredAt,
+	}
+}
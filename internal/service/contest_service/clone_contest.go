@@ -0,0 +1,393 @@
+package contest_service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/lock_service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// CloneContest creates a new unpublished contest from an existing one. Problems with
+// their scores, freeze, team and registration settings are copied, registered users
+// are copied only if asked. Times are shifted to the new start time.
+func (c *ContestService) CloneContest(
+	ctx context.Context,
+	request CloneContestRequest,
+) (Contest, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return Contest{}, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return Contest{}, err
+	}
+
+	// get the source contest
+	source, err := c.GetContestByID(ctx, request.SourceContestID)
+	if err != nil {
+		return Contest{}, err
+	}
+
+	// authorize
+	err = c.authorizeContestJury(
+		ctx,
+		source,
+		fmt.Sprintf(
+			"user %s tried to clone contest %v",
+			claims.UserName,
+			request.SourceContestID,
+		),
+	)
+	if err != nil {
+		return Contest{}, err
+	}
+
+	// source start time must not be nil
+	if source.StartTime == nil {
+		err = fmt.Errorf(
+			"%w, contest %v has start time as nil, cannot clone",
+			flux_errors.ErrInternal,
+			source.ID,
+		)
+		log.Error(err)
+		return Contest{}, err
+	}
+
+	// prepare the details of the clone
+	details := Contest{
+		Title:       source.Title,
+		StartTime:   request.StartTime,
+		IsPublished: false,
+	}
+	if request.Title != nil {
+		details.Title = *request.Title
+	}
+
+	// get the start time
+	var startTime time.Time
+	var lock lock_service.FluxLock
+	if source.LockId == nil {
+		if request.StartTime == nil {
+			return Contest{}, fmt.Errorf(
+				"%w, start time must be specified for cloning private contests",
+				flux_errors.ErrInvalidRequest,
+			)
+		}
+		startTime = *request.StartTime
+	} else {
+		// public clones start with their lock
+		err = c.UserServiceConfig.Authorize(
+			ctx,
+			user_service.PermContestCreate,
+			user_service.Resource{},
+		)
+		if err != nil {
+			return Contest{}, err
+		}
+		details.LockId = source.LockId
+		if request.LockID != nil {
+			details.LockId = request.LockID
+		}
+		lock, err = c.LockServiceConfig.GetLockById(ctx, *details.LockId)
+		if err != nil {
+			return Contest{}, err
+		}
+		startTime, err = publicContestStartTime(details, lock)
+		if err != nil {
+			return Contest{}, err
+		}
+	}
+
+	// keep the duration unless the end time is given
+	details.EndTime = startTime.Add(source.EndTime.Sub(*source.StartTime))
+	if request.EndTime != nil {
+		details.EndTime = *request.EndTime
+	}
+
+	// validate the clone
+	if details.LockId == nil {
+		err = c.validatePrivateContest(details)
+	} else {
+		err = c.validatePublicContest(ctx, details, nil, lock)
+	}
+	if err != nil {
+		return Contest{}, err
+	}
+
+	// get the problems with the swaps applied
+	problems, err := c.getClonedProblems(ctx, source, request.ProblemSwaps)
+	if err != nil {
+		return Contest{}, err
+	}
+	err = c.validateContestProblems(ctx, details.LockId, problems)
+	if err != nil {
+		return Contest{}, err
+	}
+
+	// get the users
+	var userNames []string
+	if request.IncludeUsers {
+		users, err := c.GetContestRegisteredUsers(ctx, source.ID)
+		if err != nil {
+			return Contest{}, err
+		}
+		userNames = make([]string, 0, len(users))
+		for _, user := range users {
+			userNames = append(userNames, user.UserName)
+		}
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return Contest{}, err
+	}
+
+	// if anything goes wrong roll back transaction
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	// create contest
+	dbContest, err := qtx.CreateContest(
+		ctx,
+		database.CreateContestParams{
+			Title:       details.Title,
+			CreatedBy:   claims.UserId,
+			StartTime:   details.StartTime,
+			EndTime:     details.EndTime,
+			IsPublished: false,
+			LockID:      details.LockId,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot create clone of contest %v, %w",
+			flux_errors.ErrInternal,
+			source.ID,
+			err,
+		)
+		log.WithField("contest details", details).Error(err)
+		return Contest{}, err
+	}
+
+	// add problems
+	err = c.addProblemsToContest(ctx, qtx, dbContest.ID, problems)
+	if err != nil {
+		return Contest{}, err
+	}
+
+	// add users
+	_, err = c.addUsersToContest(ctx, qtx, dbContest.ID, userNames)
+	if err != nil {
+		return Contest{}, err
+	}
+
+	// copy the settings
+	err = c.cloneContestSettings(
+		ctx,
+		qtx,
+		source,
+		dbContest.ID,
+		startTime,
+		details.EndTime,
+	)
+	if err != nil {
+		return Contest{}, err
+	}
+
+	utcStartTime := startTime.UTC()
+	contest := Contest{
+		ID:          dbContest.ID,
+		Title:       dbContest.Title,
+		LockId:      dbContest.LockID,
+		StartTime:   &utcStartTime,
+		EndTime:     dbContest.EndTime.UTC(),
+		IsPublished: dbContest.IsPublished,
+		CreatedBy:   dbContest.CreatedBy,
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionContestClone,
+		EntityType: auditEntityContest,
+		EntityID:   contest.ID,
+		After: CreateContestRequest{
+			ContestDetails:  contest,
+			RegisteredUsers: userNames,
+			ContestProblems: problems,
+		},
+	})
+	if err != nil {
+		return Contest{}, err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after cloning contest %v, %w",
+			flux_errors.ErrInternal,
+			source.ID,
+			err,
+		)
+		return Contest{}, err
+	}
+
+	log.Infof("user %s cloned contest %v into %v", claims.UserName, source.ID, contest.ID)
+	return contest, nil
+}
+
+func (c *ContestService) getClonedProblems(
+	ctx context.Context,
+	source Contest,
+	swaps []ProblemSwap,
+) ([]ContestProblem, error) {
+	dbProblems, err := c.DB.GetContestProblemsByContestID(ctx, source.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot get problems of contest with id %v", source.ID),
+		)
+		return nil, err
+	}
+
+	// index the problems
+	problems := make([]ContestProblem, 0, len(dbProblems))
+	indices := make(map[int32]int, len(dbProblems))
+	for i, dbProblem := range dbProblems {
+		problems = append(problems, ContestProblem{
+			ProblemId: dbProblem.ProblemID,
+			Score:     dbProblem.Score,
+		})
+		indices[dbProblem.ProblemID] = i
+	}
+
+	// swapped problems keep the score of the old ones
+	for _, swap := range swaps {
+		index, ok := indices[swap.OldProblemID]
+		if !ok {
+			return nil, fmt.Errorf(
+				"%w, problem with id %v does not exist in the contest being cloned",
+				flux_errors.ErrInvalidRequest,
+				swap.OldProblemID,
+			)
+		}
+		delete(indices, swap.OldProblemID)
+		problems[index].ProblemId = swap.NewProblemID
+	}
+
+	return problems, nil
+}
+
+// cloneContestSettings copies freeze, team and registration settings of the source
+// contest. Registration window is shifted along with the start of the contest.
+func (c *ContestService) cloneContestSettings(
+	ctx context.Context,
+	qtx *database.Queries,
+	source Contest,
+	cloneID uuid.UUID,
+	startTime time.Time,
+	endTime time.Time,
+) error {
+	// freeze
+	dbFreeze, err := qtx.GetContestFreeze(ctx, source.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch freeze of contest %v", source.ID),
+		)
+	}
+	if err == nil {
+		// a freeze longer than the clone is dropped
+		if time.Duration(dbFreeze.FreezeMinutes)*time.Minute < endTime.Sub(startTime) {
+			_, err = qtx.SetContestFreeze(
+				ctx,
+				database.SetContestFreezeParams{
+					ContestID:     cloneID,
+					FreezeMinutes: dbFreeze.FreezeMinutes,
+				},
+			)
+			if err != nil {
+				return flux_errors.HandleDBErrors(
+					err,
+					errMsgs,
+					fmt.Sprintf("cannot set freeze of contest %v", cloneID),
+				)
+			}
+		}
+	}
+
+	// team settings
+	dbTeamSettings, err := qtx.GetContestTeamSettings(ctx, source.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch team settings of contest %v", source.ID),
+		)
+	}
+	if err == nil {
+		_, err = qtx.SetContestTeamSettings(
+			ctx,
+			database.SetContestTeamSettingsParams{
+				ContestID:   cloneID,
+				MaxTeamSize: dbTeamSettings.MaxTeamSize,
+			},
+		)
+		if err != nil {
+			return flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot set team settings of contest %v", cloneID),
+			)
+		}
+	}
+
+	// registration settings
+	dbRegistration, err := qtx.GetContestRegistrationSettings(ctx, source.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch registration settings of contest %v", source.ID),
+		)
+	}
+	if err == nil {
+		shift := startTime.Sub(*source.StartTime)
+		_, err = qtx.SetContestRegistrationSettings(
+			ctx,
+			database.SetContestRegistrationSettingsParams{
+				ContestID:         cloneID,
+				RegistrationStart: dbRegistration.RegistrationStart.Add(shift),
+				RegistrationEnd:   dbRegistration.RegistrationEnd.Add(shift),
+				Capacity:          dbRegistration.Capacity,
+				RequiresApproval:  dbRegistration.RequiresApproval,
+			},
+		)
+		if err != nil {
+			return flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot set registration settings of contest %v", cloneID),
+			)
+		}
+	}
+
+	return nil
+}
@@ -75,6 +75,7 @@ func (c *ContestService) validatePrivateContest(
 }
 
 func (c *ContestService) validatePublicContest(
+	ctx context.Context,
 	contest Contest,
 	registeredUserNames []string,
 	lock lock_service.FluxLock,
@@ -85,38 +86,36 @@ func (c *ContestService) validatePublicContest(
 		return err
 	}
 
-	// start time is inferred only from its lock
-	if contest.StartTime != nil {
-		return fmt.Errorf(
-			"%w, start time of a public contest must be null, it is inferred from its lock only",
-			flux_errors.ErrInvalidRequest,
-		)
-	}
-
-	// validate type
-	if lock.Type != database.LockTypeTimer {
-		return fmt.Errorf(
-			"%w, only timer locks can be used for public contest",
-			flux_errors.ErrInvalidRequest,
-		)
+	// start time is inferred from timer locks only
+	startTime, err := publicContestStartTime(contest, lock)
+	if err != nil {
+		return err
 	}
 
 	// validate its expiry
-	expired, err := c.LockServiceConfig.IsLockExpired(lock, 60*24)
+	condition, err := c.LockServiceConfig.GetLockCondition(ctx, lock.ID)
 	if err != nil {
 		return err
 	}
-	if expired {
+	if condition.IsOpenAt(time.Now().Add(24 * time.Hour)) {
 		return fmt.Errorf(
 			"%w, lock must have atleast one day of expiry",
 			flux_errors.ErrInvalidRequest,
 		)
 	}
 
+	// problems must stay hidden till the contest starts
+	if condition.IsOpenAt(startTime) {
+		return fmt.Errorf(
+			"%w, lock must not open before the contest starts",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
 	// validate end time
-	if lock.Timeout.Add(time.Minute * 5).After(contest.EndTime) {
+	if startTime.Add(time.Minute * 5).After(contest.EndTime) {
 		return fmt.Errorf(
-			"%w, contest endtime must be atleast 5 minutes after the expiry of the lock",
+			"%w, contest endtime must be atleast 5 minutes after its start",
 			flux_errors.ErrInvalidRequest,
 		)
 	}
@@ -132,6 +131,40 @@ func (c *ContestService) validatePublicContest(
 	return nil
 }
 
+// publicContestStartTime returns when the public contest starts. Contests
+// behind a timer lock start with its timeout and must not have a start time of
+// their own, others need one as their locks do not open at a known time.
+func publicContestStartTime(contest Contest, lock lock_service.FluxLock) (time.Time, error) {
+	if lock.Type != database.LockTypeTimer {
+		if contest.StartTime == nil {
+			return time.Time{}, fmt.Errorf(
+				"%w, start time of a contest with a %s lock must be specified",
+				flux_errors.ErrInvalidRequest,
+				lock.Type,
+			)
+		}
+		return *contest.StartTime, nil
+	}
+
+	if contest.StartTime != nil {
+		return time.Time{}, fmt.Errorf(
+			"%w, start time of a contest with a timer lock must be null, it is inferred from its lock only",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// very rare, but for safety purpose
+	if lock.Timeout == nil {
+		return time.Time{}, fmt.Errorf(
+			"%w, timer lock %v has timeout as nil",
+			flux_errors.ErrInternal,
+			lock.ID,
+		)
+	}
+
+	return *lock.Timeout, nil
+}
+
 func (c *ContestService) validateContestProblems(
 	ctx context.Context,
 	contestLockID *uuid.UUID,
@@ -278,14 +311,16 @@ func (c *ContestService) addProblemsToContest(
 	return nil
 }
 
+// addUsersToContest registers the users and returns the ids of the ones who
+// were not registered already
 func (c *ContestService) addUsersToContest(
 	ctx context.Context,
 	qtx *database.Queries,
 	contestID uuid.UUID,
 	userNames []string,
-) (err error) {
+) ([]uuid.UUID, error) {
 	if qtx == nil {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"%w, transaction query tool is nil, cannot add users to contest with id %v",
 			flux_errors.ErrInternal,
 			contestID,
@@ -294,7 +329,7 @@ func (c *ContestService) addUsersToContest(
 
 	// check for empty
 	if len(userNames) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	// fetch users by filters
@@ -306,6 +341,9 @@ func (c *ContestService) addUsersToContest(
 			PageSize:   int32(len(userNames)),
 		},
 	)
+	if err != nil {
+		return nil, err
+	}
 
 	usersSet := make(map[string]user_service.UserMetaData)
 
@@ -318,7 +356,7 @@ func (c *ContestService) addUsersToContest(
 	for _, userName := range userNames {
 		_, ok := usersSet[userName]
 		if !ok {
-			return fmt.Errorf(
+			return nil, fmt.Errorf(
 				"%w, user %s does not exist",
 				flux_errors.ErrInvalidRequest,
 				userName,
@@ -326,9 +364,10 @@ func (c *ContestService) addUsersToContest(
 		}
 	}
 
-	// insert users into db
+	// insert users into db, the ones already registered are skipped
+	userIDs := make([]uuid.UUID, 0, len(usersSet))
 	for _, user := range usersSet {
-		_, err := qtx.RegisterUserToContest(
+		added, err := qtx.RegisterUserToContest(
 			ctx, database.RegisterUserToContestParams{
 				ContestID: contestID,
 				UserID:    user.UserID,
@@ -340,10 +379,35 @@ func (c *ContestService) addUsersToContest(
 				errMsgs,
 				fmt.Sprintf("cannot add users to contest with id %v", contestID),
 			)
+			return nil, err
+		}
+
+		// the user no longer needs a pending or waitlisted request
+		err = qtx.DeleteRegistrationRequest(
+			ctx,
+			database.DeleteRegistrationRequestParams{
+				ContestID: contestID,
+				UserID:    user.UserID,
+			},
+		)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf(
+					"cannot delete registration request of user %v in contest %v",
+					user.UserID,
+					contestID,
+				),
+			)
+			return nil, err
+		}
+		if added > 0 {
+			userIDs = append(userIDs, user.UserID)
 		}
 	}
 
-	return nil
+	return userIDs, nil
 }
 
 func dbContestToServiceContest(
@@ -351,7 +415,11 @@ func dbContestToServiceContest(
 ) (Contest, error) {
 	// time in database is stored in timezone, convert it to utc
 	var utcStartTime *time.Time
-	if dbContest.LockID != nil {
+	if dbContest.StartTime != nil {
+		// private contests, and public ones behind manual or composite locks
+		ust := dbContest.StartTime.UTC()
+		utcStartTime = &ust
+	} else if dbContest.LockID != nil {
 		if dbContest.LockTimeout == nil {
 			err := fmt.Errorf(
 				"%w, lock with id %v has timeout as nil and is associated with contest %v",
@@ -365,9 +433,6 @@ func dbContestToServiceContest(
 
 		ust := dbContest.LockTimeout.UTC()
 		utcStartTime = &ust
-	} else if dbContest.StartTime != nil {
-		ust := dbContest.StartTime.UTC()
-		utcStartTime = &ust
 	} else {
 		err := fmt.Errorf(
 			"%w, contest %v has both lock_id and start_time as nil",
@@ -401,28 +466,39 @@ func (c *ContestService) authorizeContestUpdate(
 		return err
 	}
 
-	// any manager can update a public contest
+	// any manager or an edit grantee of its lock can update a public contest
 	if contest.LockId != nil {
-		err = c.UserServiceConfig.AuthorizeUserRole(
+		allowed, err := c.UserServiceConfig.IsAllowed(
 			ctx,
-			user_service.RoleManager,
-			fmt.Sprintf(
+			user_service.PermContestManage,
+			user_service.Resource{},
+		)
+		if err != nil || allowed {
+			return err
+		}
+		granted, err := c.LockServiceConfig.HasLockGrant(
+			ctx,
+			*contest.LockId,
+			database.LockGrantPermissionEdit,
+		)
+		if err != nil {
+			return err
+		}
+		if !granted {
+			log.Warnf(
 				"user %s tried to update unauthorized public contest with id %v",
 				claims.UserName,
 				contest.ID,
-			),
-		)
-		return err
+			)
+			return flux_errors.ErrUnAuthorized
+		}
+		return nil
 	} else {
 		// creator access for updating private contest
-		err = c.UserServiceConfig.AuthorizeCreatorAccess(
+		err = c.UserServiceConfig.Authorize(
 			ctx,
-			contest.CreatedBy,
-			fmt.Sprintf(
-				"user %s tried to update unauthorized private contest with id %v",
-				claims.UserName,
-				contest.ID,
-			),
+			user_service.PermContestEditAny,
+			contestResource(contest),
 		)
 		if err != nil {
 			return err
@@ -448,3 +524,104 @@ func (c *ContestService) authorizeContestUpdate(
 
 	return err
 }
+
+// isContestJury reports whether the user can act as the jury of the contest.
+// Creators judge every contest, managers additionally judge public contests.
+func (c *ContestService) isContestJury(
+	ctx context.Context,
+	contest Contest,
+) (bool, error) {
+	allowed, err := c.UserServiceConfig.IsAllowed(
+		ctx,
+		user_service.PermContestEditAny,
+		contestResource(contest),
+	)
+	if err != nil || allowed {
+		return allowed, err
+	}
+
+	// private contests are judged only by their creators
+	if contest.LockId == nil {
+		return false, nil
+	}
+
+	return c.UserServiceConfig.IsAllowed(
+		ctx,
+		user_service.PermContestManage,
+		user_service.Resource{},
+	)
+}
+
+func contestResource(contest Contest) user_service.Resource {
+	return user_service.Resource{
+		Kind:    "contest",
+		ID:      contest.ID,
+		OwnerID: &contest.CreatedBy,
+	}
+}
+
+func (c *ContestService) authorizeContestJury(
+	ctx context.Context,
+	contest Contest,
+	warnMessage string,
+) error {
+	isJury, err := c.isContestJury(ctx, contest)
+	if err != nil {
+		return err
+	}
+	if !isJury {
+		if warnMessage != "" {
+			log.Warn(warnMessage)
+		}
+		return flux_errors.ErrUnAuthorized
+	}
+
+	return nil
+}
+
+// authorizeStandingsViewer tells if the user sees the standings of the contest
+// as its jury. Anyone can see the standings of a published public contest,
+// private ones are shown only to the jury and their participants.
+func (c *ContestService) authorizeStandingsViewer(
+	ctx context.Context,
+	contest Contest,
+) (bool, error) {
+	isJury, err := c.isContestJury(ctx, contest)
+	if err != nil || isJury {
+		return isJury, err
+	}
+
+	if !(contest.LockId != nil && contest.IsPublished) {
+		err = c.authorizeContestParticipant(ctx, contest)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// authorizeContestParticipant checks if the user can take part in the contest.
+// Anyone can take part in a published public contest, others need a registration.
+// Team contests additionally need the user to be a part of a registered team.
+func (c *ContestService) authorizeContestParticipant(
+	ctx context.Context,
+	contest Contest,
+) error {
+	if !(contest.LockId != nil && contest.IsPublished) {
+		err := c.authorizeRegisteredUser(ctx, contest.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	isTeamContest, err := c.isTeamContest(ctx, contest.ID)
+	if err != nil {
+		return err
+	}
+	if isTeamContest {
+		return c.authorizeTeamMember(ctx, contest.ID)
+	}
+
+	return nil
+}
@@ -9,14 +9,15 @@ import (
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 func (c *ContestService) CreateContest(
 	ctx context.Context,
 	request CreateContestRequest,
 ) (Contest, error) {
-	// start time is specified for private contest
-	// but must be extracted from lock for public contest
+	// start time is specified for private contest and public contest with
+	// manual or composite locks, but must be extracted from timer locks
 	var startTime *time.Time
 
 	// contest details validations
@@ -30,6 +31,15 @@ func (c *ContestService) CreateContest(
 		startTime = request.ContestDetails.StartTime
 	} else {
 		// its a public contest
+		err := c.UserServiceConfig.Authorize(
+			ctx,
+			user_service.PermContestCreate,
+			user_service.Resource{},
+		)
+		if err != nil {
+			return Contest{}, err
+		}
+
 		// get lock here to get start time from its expiry
 		lock, err := c.LockServiceConfig.GetLockById(
 			ctx,
@@ -40,13 +50,17 @@ func (c *ContestService) CreateContest(
 		}
 
 		// validate the public contest
-		err = c.validatePublicContest(request.ContestDetails, request.RegisteredUsers, lock)
+		err = c.validatePublicContest(ctx, request.ContestDetails, request.RegisteredUsers, lock)
 		if err != nil {
 			return Contest{}, err
 		}
 
-		// set the startTime as the expiry of the lock
-		startTime = lock.Timeout
+		// set the startTime as the expiry of a timer lock, or the given one
+		lockStartTime, err := publicContestStartTime(request.ContestDetails, lock)
+		if err != nil {
+			return Contest{}, err
+		}
+		startTime = &lockStartTime
 	}
 
 	// get claims
@@ -83,8 +97,8 @@ func (c *ContestService) CreateContest(
 		database.CreateContestParams{
 			Title:     request.ContestDetails.Title,
 			CreatedBy: claims.UserId,
-			// for public contest start_time must be nil as its inferred
-			// from its lock only to avoid inconsistency that arise with duplication
+			// for public contest with timer lock start_time must be nil as its
+			// inferred from its lock only to avoid inconsistency that arise with duplication
 			StartTime:   request.ContestDetails.StartTime,
 			EndTime:     request.ContestDetails.EndTime,
 			IsPublished: request.ContestDetails.IsPublished,
@@ -115,7 +129,33 @@ func (c *ContestService) CreateContest(
 	}
 
 	// add users
-	err = c.addUsersToContest(ctx, qtx, dbContest.ID, request.RegisteredUsers)
+	_, err = c.addUsersToContest(ctx, qtx, dbContest.ID, request.RegisteredUsers)
+	if err != nil {
+		return Contest{}, err
+	}
+
+	utcStartTime := startTime.UTC()
+	contest := Contest{
+		ID:          dbContest.ID,
+		Title:       dbContest.Title,
+		LockId:      dbContest.LockID,
+		StartTime:   &utcStartTime,
+		EndTime:     dbContest.EndTime.UTC(),
+		IsPublished: dbContest.IsPublished,
+		CreatedBy:   dbContest.CreatedBy,
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionContestCreate,
+		EntityType: auditEntityContest,
+		EntityID:   contest.ID,
+		After: CreateContestRequest{
+			ContestDetails:  contest,
+			RegisteredUsers: request.RegisteredUsers,
+			ContestProblems: request.ContestProblems,
+		},
+	})
 	if err != nil {
 		return Contest{}, err
 	}
@@ -130,15 +170,5 @@ func (c *ContestService) CreateContest(
 		return Contest{}, err
 	}
 
-	// prepare response and return
-	utcStartTime := startTime.UTC()
-	return Contest{
-		ID:          dbContest.ID,
-		Title:       dbContest.Title,
-		LockId:      dbContest.LockID,
-		StartTime:   &utcStartTime,
-		EndTime:     dbContest.EndTime.UTC(),
-		IsPublished: dbContest.IsPublished,
-		CreatedBy:   dbContest.CreatedBy,
-	}, nil
+	return contest, nil
 }
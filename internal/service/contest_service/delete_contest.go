@@ -78,6 +78,17 @@ func (c *ContestService) DeleteContest(
 		return err
 	}
 
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionContestDelete,
+		EntityType: auditEntityContest,
+		EntityID:   id,
+		Before:     prevContest,
+	})
+	if err != nil {
+		return err
+	}
+
 	// commit the tx
 	if err = tx.Commit(ctx); err != nil {
 		err = fmt.Errorf(
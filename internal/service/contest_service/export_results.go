@@ -0,0 +1,216 @@
+package contest_service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// ExportContestResults exports the final standings of an ended contest. Only the
+// jury can export, so the results are never hidden by a freeze.
+func (c *ContestService) ExportContestResults(
+	ctx context.Context,
+	request ExportResultsRequest,
+) (ExportedResults, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return ExportedResults{}, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return ExportedResults{}, err
+	}
+
+	// get the contest
+	contest, err := c.GetContestByID(ctx, request.ContestID)
+	if err != nil {
+		return ExportedResults{}, err
+	}
+
+	// authorize
+	err = c.authorizeContestJury(
+		ctx,
+		contest,
+		fmt.Sprintf(
+			"user %s tried to export results of contest %v",
+			claims.UserName,
+			contest.ID,
+		),
+	)
+	if err != nil {
+		return ExportedResults{}, err
+	}
+
+	// results are final only after the contest ends
+	if !time.Now().After(contest.EndTime) {
+		return ExportedResults{}, fmt.Errorf(
+			"%w, results can be exported only after the contest has ended",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	standings, err := c.buildStandings(ctx, contest, request.IncludeUnofficial, nil)
+	if err != nil {
+		return ExportedResults{}, err
+	}
+
+	fileName := fmt.Sprintf("contest_%v_results", contest.ID)
+	switch request.Format {
+	case ExportFormatCSV:
+		data, err := standingsToCSV(standings)
+		if err != nil {
+			return ExportedResults{}, err
+		}
+		return ExportedResults{
+			FileName:    fileName + ".csv",
+			ContentType: "text/csv",
+			Data:        data,
+		}, nil
+	case ExportFormatICPC:
+		return ExportedResults{
+			FileName:    "results.tsv",
+			ContentType: "text/tab-separated-values",
+			Data:        standingsToICPCResults(standings),
+		}, nil
+	default:
+		data, err := json.Marshal(ContestResults{
+			ContestID:  contest.ID,
+			Title:      contest.Title,
+			StartTime:  *contest.StartTime,
+			EndTime:    contest.EndTime,
+			ProblemIDs: standings.ProblemIDs,
+			Rows:       standings.Rows,
+		})
+		if err != nil {
+			err = fmt.Errorf(
+				"%w, cannot marshal results of contest %v, %w",
+				flux_errors.ErrInternal,
+				contest.ID,
+				err,
+			)
+			log.Error(err)
+			return ExportedResults{}, err
+		}
+		return ExportedResults{
+			FileName:    fileName + ".json",
+			ContentType: "application/json",
+			Data:        data,
+		}, nil
+	}
+}
+
+// standingsToCSV writes one line per row with score, attempts and acceptance
+// time in minutes for every problem
+func standingsToCSV(standings ContestStandings) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	header := []string{"rank", "user_name", "roll_no", "team_name", "unofficial"}
+	for _, problemID := range standings.ProblemIDs {
+		header = append(
+			header,
+			fmt.Sprintf("%v_score", problemID),
+			fmt.Sprintf("%v_attempts", problemID),
+			fmt.Sprintf("%v_time", problemID),
+		)
+	}
+	header = append(header, "total_score", "penalty")
+
+	records := [][]string{header}
+	for _, row := range standings.Rows {
+		record := []string{
+			strconv.Itoa(int(row.Rank)),
+			row.UserName,
+			row.RollNo,
+			row.TeamName,
+			strconv.FormatBool(row.IsVirtual),
+		}
+		for _, result := range row.Problems {
+			acceptedAt := ""
+			if result.AcceptedAt != nil {
+				acceptedAt = strconv.FormatInt(*result.AcceptedAt/60, 10)
+			}
+			record = append(
+				record,
+				strconv.Itoa(int(result.Score)),
+				strconv.Itoa(int(result.Attempts)),
+				acceptedAt,
+			)
+		}
+		record = append(
+			record,
+			strconv.Itoa(int(row.Score)),
+			strconv.FormatInt(row.Penalty, 10),
+		)
+		records = append(records, record)
+	}
+
+	err := writer.WriteAll(records)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot write results of contest %v as csv, %w",
+			flux_errors.ErrInternal,
+			standings.ContestID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// standingsToICPCResults writes the results.tsv of the ICPC Contest API. Teams are
+// identified by their roll numbers, or by their names in team contests.
+func standingsToICPCResults(standings ContestStandings) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("results\t1\n")
+
+	for _, row := range standings.Rows {
+		id := row.RollNo
+		if row.TeamID != nil {
+			id = row.TeamName
+		}
+
+		solved := 0
+		var lastAccepted int64
+		for _, result := range row.Problems {
+			if !result.Accepted || result.AcceptedAt == nil {
+				continue
+			}
+			solved++
+			if *result.AcceptedAt/60 > lastAccepted {
+				lastAccepted = *result.AcceptedAt / 60
+			}
+		}
+
+		award := "Ranked"
+		if solved == 0 {
+			award = "Honorable"
+		}
+
+		fmt.Fprintf(
+			&buf,
+			"%s\t%d\t%s\t%d\t%d\t%d\n",
+			id,
+			row.Rank,
+			award,
+			solved,
+			row.Penalty,
+			lastAccepted,
+		)
+	}
+
+	return buf.Bytes()
+}
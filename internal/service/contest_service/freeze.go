@@ -0,0 +1,532 @@
+package contest_service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+func (c *ContestService) SetContestFreeze(
+	ctx context.Context,
+	freeze ContestFreeze,
+) (ContestFreeze, error) {
+	// validate
+	err := service.ValidateInput(freeze)
+	if err != nil {
+		return ContestFreeze{}, err
+	}
+
+	// get the contest
+	contest, err := c.GetContestByID(ctx, freeze.ContestID)
+	if err != nil {
+		return ContestFreeze{}, err
+	}
+
+	// authorize
+	err = c.authorizeContestUpdate(ctx, contest)
+	if err != nil {
+		return ContestFreeze{}, err
+	}
+
+	// freeze must begin after the contest starts
+	duration := contest.EndTime.Sub(*contest.StartTime)
+	if time.Duration(freeze.FreezeMinutes)*time.Minute >= duration {
+		return ContestFreeze{}, fmt.Errorf(
+			"%w, freeze must be shorter than the duration of the contest",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// freeze before the change
+	prevFreeze, err := c.findContestFreeze(ctx, freeze.ContestID)
+	if err != nil {
+		return ContestFreeze{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return ContestFreeze{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	dbFreeze, err := qtx.SetContestFreeze(
+		ctx,
+		database.SetContestFreezeParams{
+			ContestID:     freeze.ContestID,
+			FreezeMinutes: freeze.FreezeMinutes,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot set freeze of contest %v", freeze.ContestID),
+		)
+		return ContestFreeze{}, err
+	}
+	newFreeze := dbFreezeToServiceFreeze(dbFreeze)
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionContestSetFreeze,
+		EntityType: auditEntityContest,
+		EntityID:   freeze.ContestID,
+		Before:     prevFreeze,
+		After:      newFreeze,
+	})
+	if err != nil {
+		return ContestFreeze{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return ContestFreeze{}, fmt.Errorf(
+			"%w, cannot commit transaction after setting freeze of contest %v, %w",
+			flux_errors.ErrInternal,
+			freeze.ContestID,
+			err,
+		)
+	}
+
+	return newFreeze, nil
+}
+
+func (c *ContestService) GetContestFreeze(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (ContestFreeze, error) {
+	// get the contest
+	contest, err := c.GetContestByID(ctx, contestID)
+	if err != nil {
+		return ContestFreeze{}, err
+	}
+
+	// the freeze is seen by whoever sees the standings
+	_, err = c.authorizeStandingsViewer(ctx, contest)
+	if err != nil {
+		return ContestFreeze{}, err
+	}
+
+	dbFreeze, err := c.DB.GetContestFreeze(ctx, contestID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch freeze of contest %v", contestID),
+		)
+		return ContestFreeze{}, err
+	}
+
+	return dbFreezeToServiceFreeze(dbFreeze), nil
+}
+
+func (c *ContestService) DeleteContestFreeze(
+	ctx context.Context,
+	contestID uuid.UUID,
+) error {
+	// get the contest
+	contest, err := c.GetContestByID(ctx, contestID)
+	if err != nil {
+		return err
+	}
+
+	// authorize
+	err = c.authorizeContestUpdate(ctx, contest)
+	if err != nil {
+		return err
+	}
+
+	// freeze before the change
+	prevFreeze, err := c.findContestFreeze(ctx, contestID)
+	if err != nil {
+		return err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	err = qtx.DeleteContestFreeze(ctx, contestID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot delete freeze of contest %v", contestID),
+		)
+		return err
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionContestDeleteFreeze,
+		EntityType: auditEntityContest,
+		EntityID:   contestID,
+		Before:     prevFreeze,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf(
+			"%w, cannot commit transaction after deleting freeze of contest %v, %w",
+			flux_errors.ErrInternal,
+			contestID,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// UnfreezeContest reveals every hidden result at once and returns the final standings
+func (c *ContestService) UnfreezeContest(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (ContestStandings, error) {
+	// get the contest and authorize the manager
+	contest, err := c.authorizeUnfreeze(ctx, contestID)
+	if err != nil {
+		return ContestStandings{}, err
+	}
+
+	// freeze before the change
+	prevFreeze, err := c.findContestFreeze(ctx, contestID)
+	if err != nil {
+		return ContestStandings{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return ContestStandings{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	dbFreeze, err := qtx.UnfreezeContest(ctx, contestID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot unfreeze contest %v", contestID),
+		)
+		return ContestStandings{}, err
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionContestUnfreeze,
+		EntityType: auditEntityContest,
+		EntityID:   contestID,
+		Before:     prevFreeze,
+		After:      dbFreezeToServiceFreeze(dbFreeze),
+	})
+	if err != nil {
+		return ContestStandings{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return ContestStandings{}, fmt.Errorf(
+			"%w, cannot commit transaction after unfreezing contest %v, %w",
+			flux_errors.ErrInternal,
+			contestID,
+			err,
+		)
+	}
+
+	return c.buildStandings(ctx, contest, false, nil)
+}
+
+// RevealNextResult is a single step of the resolver. It picks the lowest ranked
+// participant having hidden results and reveals their first hidden problem. Once
+// nothing is left to reveal, the contest gets unfrozen.
+func (c *ContestService) RevealNextResult(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (RevealResponse, error) {
+	// get the contest and authorize the manager
+	contest, err := c.authorizeUnfreeze(ctx, contestID)
+	if err != nil {
+		return RevealResponse{}, err
+	}
+
+	freeze, err := c.getStandingsFreeze(ctx, contest)
+	if err != nil {
+		return RevealResponse{}, err
+	}
+	if freeze == nil {
+		return RevealResponse{}, fmt.Errorf(
+			"%w, standings of the contest are not frozen",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// get the current frozen standings
+	standings, err := c.buildStandings(ctx, contest, false, freeze)
+	if err != nil {
+		return RevealResponse{}, err
+	}
+
+	// find the lowest ranked row with a hidden result
+	var row *StandingsRow
+	var problemID int32
+	for i := len(standings.Rows) - 1; i >= 0 && row == nil; i-- {
+		for _, result := range standings.Rows[i].Problems {
+			if result.Pending > 0 {
+				row = &standings.Rows[i]
+				problemID = result.ProblemID
+				break
+			}
+		}
+	}
+
+	// nothing is left, unfreeze the contest
+	if row == nil {
+		standings, err = c.UnfreezeContest(ctx, contestID)
+		if err != nil {
+			return RevealResponse{}, err
+		}
+		return RevealResponse{Done: true, Standings: standings}, nil
+	}
+
+	// results of a team are revealed for all of its members
+	userIDs := []uuid.UUID{row.UserID}
+	if row.TeamID != nil {
+		userIDs = row.MemberIDs
+	}
+	teamID, userID := row.TeamID, row.UserID
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return RevealResponse{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	for _, id := range userIDs {
+		err = qtx.RevealFrozenResult(
+			ctx,
+			database.RevealFrozenResultParams{
+				ContestID: contestID,
+				UserID:    id,
+				ProblemID: problemID,
+			},
+		)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf(
+					"cannot reveal result of user %v for problem %v in contest %v",
+					id,
+					problemID,
+					contestID,
+				),
+			)
+			return RevealResponse{}, err
+		}
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionContestRevealResult,
+		EntityType: auditEntityContest,
+		EntityID:   contestID,
+		After: revealedResult{
+			ProblemID: problemID,
+			TeamID:    teamID,
+			UserIDs:   userIDs,
+		},
+	})
+	if err != nil {
+		return RevealResponse{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return RevealResponse{}, fmt.Errorf(
+			"%w, cannot commit transaction after revealing result of problem %v in contest %v, %w",
+			flux_errors.ErrInternal,
+			problemID,
+			contestID,
+			err,
+		)
+	}
+	for _, id := range userIDs {
+		freeze.revealed[frozenCell{id, problemID}] = true
+	}
+
+	// standings after the reveal
+	standings, err = c.buildStandings(ctx, contest, false, freeze)
+	if err != nil {
+		return RevealResponse{}, err
+	}
+
+	accepted := false
+	for _, row := range standings.Rows {
+		if row.UserID != userID || (teamID != nil && (row.TeamID == nil || *row.TeamID != *teamID)) {
+			continue
+		}
+		for _, result := range row.Problems {
+			if result.ProblemID == problemID {
+				accepted = result.Accepted
+			}
+		}
+	}
+
+	res := RevealResponse{
+		ProblemID: &problemID,
+		Accepted:  accepted,
+		Standings: standings,
+	}
+	if teamID != nil {
+		res.TeamID = teamID
+	} else {
+		res.UserID = &userID
+	}
+
+	return res, nil
+}
+
+func (c *ContestService) authorizeUnfreeze(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (Contest, error) {
+	// only managers can unfreeze
+	err := c.UserServiceConfig.Authorize(ctx, user_service.PermContestManage, user_service.Resource{})
+	if err != nil {
+		return Contest{}, err
+	}
+
+	// get the contest
+	contest, err := c.GetContestByID(ctx, contestID)
+	if err != nil {
+		return Contest{}, err
+	}
+
+	// results can be revealed only after the contest ends
+	if !time.Now().After(contest.EndTime) {
+		return Contest{}, fmt.Errorf(
+			"%w, standings can be unfrozen only after the contest has ended",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	return contest, nil
+}
+
+// getStandingsFreeze returns the freeze to be applied on the standings of the
+// contest, nil if the contest has no freeze or is already unfrozen
+func (c *ContestService) getStandingsFreeze(
+	ctx context.Context,
+	contest Contest,
+) (*standingsFreeze, error) {
+	dbFreeze, err := c.DB.GetContestFreeze(ctx, contest.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch freeze of contest %v", contest.ID),
+		)
+		return nil, err
+	}
+	if dbFreeze.UnfrozenAt != nil {
+		return nil, nil
+	}
+
+	// contest start time must not be nil
+	if contest.StartTime == nil {
+		err = fmt.Errorf(
+			"%w, contest %v has start time as nil, cannot apply freeze",
+			flux_errors.ErrInternal,
+			contest.ID,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	// get the results already revealed by the resolver
+	dbReveals, err := c.DB.GetFrozenResultReveals(ctx, contest.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch revealed results of contest %v", contest.ID),
+		)
+		return nil, err
+	}
+	revealed := make(map[frozenCell]bool, len(dbReveals))
+	for _, dbReveal := range dbReveals {
+		revealed[frozenCell{dbReveal.UserID, dbReveal.ProblemID}] = true
+	}
+
+	freezeTime := contest.EndTime.Add(-time.Duration(dbFreeze.FreezeMinutes) * time.Minute)
+	return &standingsFreeze{
+		offset:   freezeTime.Sub(*contest.StartTime),
+		revealed: revealed,
+	}, nil
+}
+
+// findContestFreeze returns the freeze of the contest, nil if it has none
+func (c *ContestService) findContestFreeze(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (*ContestFreeze, error) {
+	dbFreeze, err := c.DB.GetContestFreeze(ctx, contestID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch freeze of contest %v", contestID),
+		)
+		return nil, err
+	}
+
+	freeze := dbFreezeToServiceFreeze(dbFreeze)
+	return &freeze, nil
+}
+
+func dbFreezeToServiceFreeze(dbFreeze database.ContestFreeze) ContestFreeze {
+	return ContestFreeze{
+		ContestID:     dbFreeze.ContestID,
+		FreezeMinutes: dbFreeze.FreezeMinutes,
+		UnfrozenAt:    dbFreeze.UnfrozenAt,
+	}
+}
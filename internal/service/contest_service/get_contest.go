@@ -27,7 +27,7 @@ func (c *ContestService) GetContestByID(
 			errMsgs,
 			fmt.Sprintf("failed to fetch contest with id %v", id),
 		)
-		return Contest{}, nil
+		return Contest{}, err
 	}
 
 	return dbContestToServiceContest(dbContest)
@@ -9,6 +9,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service/problem_service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 func (c *ContestService) GetContestProblems(
@@ -84,7 +85,8 @@ func (c *ContestService) authorizeProblemView(
 ) error {
 	/*
 		1. If the contest is started anyone can see the problems of a contest, otherwise
-		2. If its a public contest only authorized personnel can see the problems
+		2. If its a public contest only authorized personnel or the users granted
+		   access to its lock can see the problems
 		3. If its a private contest, they need the creator access to view the problems
 
 		Since, the creator of a private contest already knows the problems, its ok
@@ -122,8 +124,10 @@ func (c *ContestService) authorizeProblemView(
 			return err
 		}
 
-		if err := c.UserServiceConfig.AuthorizeUserRole(
+		if err := c.LockServiceConfig.AuthorizeLock(
 			ctx,
+			*contest.LockId,
+			contest.LockTimeout,
 			*contest.LockAccess,
 			"",
 		); err != nil {
@@ -131,10 +135,10 @@ func (c *ContestService) authorizeProblemView(
 		}
 	} else {
 		// private contest, need creator access
-		if err := c.UserServiceConfig.AuthorizeCreatorAccess(
+		if err := c.UserServiceConfig.Authorize(
 			ctx,
-			contest.CreatedBy,
-			"",
+			user_service.PermContestEditAny,
+			contestResource(contest),
 		); err != nil {
 			return err
 		}
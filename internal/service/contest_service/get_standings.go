@@ -0,0 +1,388 @@
+package contest_service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+func (c *ContestService) GetContestStandings(
+	ctx context.Context,
+	contestID uuid.UUID,
+	includeVirtual bool,
+) (ContestStandings, error) {
+	// get the contest
+	contest, err := c.GetContestByID(ctx, contestID)
+	if err != nil {
+		return ContestStandings{}, err
+	}
+
+	// contest start time must not be nil
+	if contest.StartTime == nil {
+		err = fmt.Errorf(
+			"%w, contest %v has start time as nil, cannot prepare standings",
+			flux_errors.ErrInternal,
+			contestID,
+		)
+		log.Error(err)
+		return ContestStandings{}, err
+	}
+
+	// jury sees the live standings, others see the frozen one
+	isJury, err := c.authorizeStandingsViewer(ctx, contest)
+	if err != nil {
+		return ContestStandings{}, err
+	}
+
+	// standings are visible only after the contest has started
+	if time.Now().Before(*contest.StartTime) {
+		return ContestStandings{}, fmt.Errorf(
+			"%w, standings are available only after the contest has started",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	var freeze *standingsFreeze
+	if !isJury {
+		freeze, err = c.getStandingsFreeze(ctx, contest)
+		if err != nil {
+			return ContestStandings{}, err
+		}
+	}
+
+	return c.buildStandings(ctx, contest, includeVirtual, freeze)
+}
+
+// GetFinalStandings returns the live standings of the official participants
+// ignoring any freeze. It does not authorize the caller, so it must only be used
+// by other services, like the rating service, once the contest has ended.
+func (c *ContestService) GetFinalStandings(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (ContestStandings, error) {
+	// get the contest
+	contest, err := c.GetContestByID(ctx, contestID)
+	if err != nil {
+		return ContestStandings{}, err
+	}
+
+	return c.buildStandings(ctx, contest, false, nil)
+}
+
+func (c *ContestService) buildStandings(
+	ctx context.Context,
+	contest Contest,
+	includeVirtual bool,
+	freeze *standingsFreeze,
+) (ContestStandings, error) {
+	// get the problems of the contest
+	dbProblems, err := c.DB.GetContestProblemsByContestID(ctx, contest.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot get problems of contest with id %v", contest.ID),
+		)
+		return ContestStandings{}, err
+	}
+
+	// get all the submissions made to the contest
+	submissions, err := c.DB.GetContestSubmissions(ctx, &contest.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot get submissions of contest with id %v", contest.ID),
+		)
+		return ContestStandings{}, err
+	}
+
+	// get the virtual participations if requested
+	participations := make(map[uuid.UUID]database.VirtualParticipation)
+	if includeVirtual {
+		dbParticipations, err := c.DB.GetContestVirtualParticipations(ctx, contest.ID)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot get virtual participations of contest with id %v", contest.ID),
+			)
+			return ContestStandings{}, err
+		}
+		for _, participation := range dbParticipations {
+			participations[participation.ID] = participation
+		}
+	}
+
+	// submissions of team members count for their teams
+	userTeams, err := c.getUserTeams(ctx, contest.ID)
+	if err != nil {
+		return ContestStandings{}, err
+	}
+
+	problemIDs := make([]int32, 0, len(dbProblems))
+	for _, dbProblem := range dbProblems {
+		problemIDs = append(problemIDs, dbProblem.ProblemID)
+	}
+	sort.Slice(problemIDs, func(i, j int) bool { return problemIDs[i] < problemIDs[j] })
+
+	// problems with subtasks are scored partially
+	dbSubtaskPoints, err := c.DB.GetProblemsSubtaskPoints(ctx, problemIDs)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot get subtask points of problems of contest with id %v", contest.ID),
+		)
+		return ContestStandings{}, err
+	}
+	subtaskPoints := make(map[int32]int32, len(dbSubtaskPoints))
+	for _, points := range dbSubtaskPoints {
+		subtaskPoints[points.ProblemID] = points.TotalPoints
+	}
+
+	// compute the rows
+	rows := ComputeStandings(
+		contest, dbProblems, submissions, participations, userTeams, subtaskPoints, freeze,
+	)
+	RankStandings(rows)
+
+	// fill in the user details
+	err = c.fillStandingsUsers(ctx, rows)
+	if err != nil {
+		return ContestStandings{}, err
+	}
+
+	return ContestStandings{
+		ContestID:  contest.ID,
+		ProblemIDs: problemIDs,
+		IsFrozen:   freeze != nil,
+		Rows:       rows,
+	}, nil
+}
+
+// ComputeStandings aggregates submissions into one row per participant. Virtual
+// participants get their own row and their times are measured from the start of
+// their personal run, so they can be compared with the real participants. If
+// userTeams is not nil, submissions of the members are aggregated into the row of
+// their team. Problems present in subtaskPoints are scored partially, taking the
+// best score across the submissions scaled to the score of the problem. If freeze
+// is not nil, verdicts of the submissions made after the freeze are counted as
+// pending.
+func ComputeStandings(
+	contest Contest,
+	dbProblems []database.ContestProblem,
+	submissions []database.GetContestSubmissionsRow,
+	participations map[uuid.UUID]database.VirtualParticipation,
+	userTeams map[uuid.UUID]*standingsTeam,
+	subtaskPoints map[int32]int32,
+	freeze *standingsFreeze,
+) []StandingsRow {
+	// problem scores and their column in a row
+	problemScores := make(map[int32]int32, len(dbProblems))
+	problemIDs := make([]int32, 0, len(dbProblems))
+	for _, dbProblem := range dbProblems {
+		problemScores[dbProblem.ProblemID] = dbProblem.Score
+		problemIDs = append(problemIDs, dbProblem.ProblemID)
+	}
+	sort.Slice(problemIDs, func(i, j int) bool { return problemIDs[i] < problemIDs[j] })
+	problemColumns := make(map[int32]int, len(problemIDs))
+	for i, id := range problemIDs {
+		problemColumns[id] = i
+	}
+
+	type participant struct {
+		userID uuid.UUID
+		// virtual participation id, uuid.Nil for real participants
+		participationID uuid.UUID
+		// team id, uuid.Nil for individual participants
+		teamID uuid.UUID
+	}
+
+	rowIndices := make(map[participant]int)
+	rows := make([]StandingsRow, 0)
+
+	// submissions are ordered by the time of submission
+	for _, submission := range submissions {
+		column, ok := problemColumns[submission.ProblemID]
+		if !ok {
+			continue
+		}
+
+		// find out when this participant has started
+		key := participant{userID: submission.SubmittedBy}
+		startTime := *contest.StartTime
+		endTime := contest.EndTime
+		var team *standingsTeam
+		if submission.VirtualParticipationID == nil && userTeams != nil {
+			team = userTeams[submission.SubmittedBy]
+			if team == nil {
+				// not a part of any registered team
+				continue
+			}
+			key = participant{teamID: team.id}
+		} else if submission.VirtualParticipationID != nil {
+			participation, ok := participations[*submission.VirtualParticipationID]
+			if !ok {
+				// virtual participants are not requested
+				continue
+			}
+			key.participationID = participation.ID
+			startTime = participation.StartTime
+			endTime = participation.EndTime
+		}
+		if submission.SubmittedAt.Before(startTime) || submission.SubmittedAt.After(endTime) {
+			continue
+		}
+
+		// get or create the row
+		index, ok := rowIndices[key]
+		if !ok {
+			problems := make([]ProblemResult, 0, len(problemIDs))
+			for _, id := range problemIDs {
+				problems = append(problems, ProblemResult{ProblemID: id})
+			}
+			row := StandingsRow{
+				UserID:    key.userID,
+				IsVirtual: key.participationID != uuid.Nil,
+				Problems:  problems,
+			}
+			if team != nil {
+				row.TeamID = &team.id
+				row.TeamName = team.name
+				row.MemberIDs = team.members
+			}
+			rows = append(rows, row)
+			index = len(rows) - 1
+			rowIndices[key] = index
+		}
+		row := &rows[index]
+		result := &row.Problems[column]
+
+		// nothing changes after the first acceptance
+		if result.Accepted {
+			continue
+		}
+
+		// hide the verdict if it is made after the freeze
+		elapsed := submission.SubmittedAt.Sub(startTime)
+		if freeze != nil && freeze.hides(submission.SubmittedBy, submission.ProblemID, elapsed) {
+			result.Pending++
+			continue
+		}
+
+		if submission.State == SubStateAccepted {
+			acceptedAt := int64(elapsed / time.Second)
+			result.Accepted = true
+			result.AcceptedAt = &acceptedAt
+			// partial score taken earlier is replaced by the full one
+			row.Score += problemScores[submission.ProblemID] - result.Score
+			result.Score = problemScores[submission.ProblemID]
+			row.Penalty += acceptedAt/60 + int64(result.Attempts)*penaltyPerRejection
+			continue
+		}
+
+		// keep the best partial score
+		totalPoints := subtaskPoints[submission.ProblemID]
+		if submission.Score != nil && totalPoints > 0 {
+			score := int32(
+				int64(problemScores[submission.ProblemID]) * int64(*submission.Score) / int64(totalPoints),
+			)
+			if score > result.Score {
+				row.Score += score - result.Score
+				result.Score = score
+			}
+		}
+		if penalizedSubStates[submission.State] {
+			result.Attempts++
+		}
+	}
+
+	return rows
+}
+
+// RankStandings sorts the rows by score and penalty. Rows having the
+// same score and penalty share the same rank.
+func RankStandings(rows []StandingsRow) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Score != rows[j].Score {
+			return rows[i].Score > rows[j].Score
+		}
+		if rows[i].Penalty != rows[j].Penalty {
+			return rows[i].Penalty < rows[j].Penalty
+		}
+		// real participants are listed before virtual ones
+		return !rows[i].IsVirtual && rows[j].IsVirtual
+	})
+
+	for i := range rows {
+		if i > 0 && rows[i].Score == rows[i-1].Score && rows[i].Penalty == rows[i-1].Penalty {
+			rows[i].Rank = rows[i-1].Rank
+		} else {
+			rows[i].Rank = int32(i + 1)
+		}
+	}
+}
+
+func (c *ContestService) fillStandingsUsers(
+	ctx context.Context,
+	rows []StandingsRow,
+) error {
+	// collect the user ids
+	userIDSet := make(map[uuid.UUID]bool)
+	userIDs := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		// team rows have no user
+		if row.TeamID != nil {
+			continue
+		}
+		if !userIDSet[row.UserID] {
+			userIDSet[row.UserID] = true
+			userIDs = append(userIDs, row.UserID)
+		}
+	}
+
+	// check for empty
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	users, err := c.UserServiceConfig.GetUsersByFilters(
+		ctx,
+		user_service.GetUsersRequest{
+			UserIDs:    userIDs,
+			PageNumber: 1,
+			PageSize:   int32(len(userIDs)),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	usersMap := make(map[uuid.UUID]user_service.UserMetaData, len(users))
+	for _, user := range users {
+		usersMap[user.UserID] = user
+	}
+
+	for i := range rows {
+		if rows[i].TeamID != nil {
+			continue
+		}
+		user, ok := usersMap[rows[i].UserID]
+		if !ok {
+			log.Warnf("user %v present in standings but missing in fetched users", rows[i].UserID)
+			continue
+		}
+		rows[i].UserName = user.UserName
+		rows[i].RollNo = user.RollNo
+	}
+
+	return nil
+}
@@ -5,13 +5,74 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service/lock_service"
 	"github.com/tcp_snm/flux/internal/service/problem_service"
+	"github.com/tcp_snm/flux/internal/service/team_service"
 	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 var (
-	errMsgs = map[string]map[string]string{}
+	msgUniqueConstraint = map[string]string{
+		"uq_virtual_participation_user": "you have already participated virtually in this contest",
+		"contest_registered_users_pkey": "user is already registered in the contest",
+		"contest_team_members_pkey":     "a member of the team is already a part of another team in this contest",
+	}
+
+	msgForeignKey = map[string]string{
+		"fk_clarifications_contest_problem": "problem does not exist in the contest",
+		"fk_lock_dependencies_contest":      "contest is a condition of a lock",
+	}
+
+	errMsgs = map[string]map[string]string{
+		flux_errors.CodeUniqueConstraint:     msgUniqueConstraint,
+		flux_errors.CodeForeignKeyConstraint: msgForeignKey,
+	}
+)
+
+const (
+	// verdict of an accepted submission
+	SubStateAccepted = "OK"
+	// penalty minutes added for every rejected attempt before acceptance
+	penaltyPerRejection = 20
+	// status of a request that has turned into a registration
+	RegistrationStatusRegistered = "registered"
+)
+
+// actions recorded in the audit log
+const (
+	auditEntityContest                   = "contest"
+	auditActionContestCreate             = "contest.create"
+	auditActionContestClone              = "contest.clone"
+	auditActionContestUpdate             = "contest.update"
+	auditActionContestDelete             = "contest.delete"
+	auditActionContestSetProblems        = "contest.set_problems"
+	auditActionContestSetFreeze          = "contest.set_freeze"
+	auditActionContestDeleteFreeze       = "contest.delete_freeze"
+	auditActionContestUnfreeze           = "contest.unfreeze"
+	auditActionContestRevealResult       = "contest.reveal_result"
+	auditActionContestSetTeamSettings    = "contest.set_team_settings"
+	auditActionContestDeleteTeamSettings = "contest.delete_team_settings"
+	auditActionContestSetRegistration    = "contest.set_registration_settings"
+	auditActionContestReviewRegistration = "contest.review_registration"
+	auditActionContestSetUsers           = "contest.set_users"
+	auditActionContestAddUsers           = "contest.add_users"
+	auditActionContestRemoveUsers        = "contest.remove_users"
+)
+
+var (
+	// verdicts that count as a wrong attempt in standings. verdicts
+	// like compilation errors or judge failures are not penalized
+	penalizedSubStates = map[string]bool{
+		"WRONG_ANSWER":            true,
+		"RUNTIME_ERROR":           true,
+		"TIME_LIMIT_EXCEEDED":     true,
+		"MEMORY_LIMIT_EXCEEDED":   true,
+		"IDLENESS_LIMIT_EXCEEDED": true,
+		"PARTIAL":                 true,
+		"CHALLENGED":              true,
+		"SECURITY_VIOLATED":       true,
+	}
 )
 
 type ContestService struct {
@@ -19,6 +80,7 @@ type ContestService struct {
 	UserServiceConfig    *user_service.UserService
 	LockServiceConfig    *lock_service.LockService
 	ProblemServiceConfig *problem_service.ProblemService
+	TeamServiceConfig    *team_service.TeamService
 }
 
 type ContestProblem struct {
@@ -60,3 +122,233 @@ type GetContestRequest struct {
 	PageNumber  int32       `json:"page_number" validate:"min=1,max=10000"`
 	PageSize    int32       `json:"page_size" validate:"min=0,max=10000"`
 }
+
+type VirtualParticipation struct {
+	ID        uuid.UUID `json:"virtual_participation_id"`
+	ContestID uuid.UUID `json:"contest_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+type ProblemResult struct {
+	ProblemID int32 `json:"problem_id"`
+	Score     int32 `json:"score"`
+	// rejected attempts made before the first acceptance
+	Attempts int32 `json:"attempts"`
+	Accepted bool  `json:"accepted"`
+	// attempts whose verdicts are hidden by the freeze
+	Pending int32 `json:"pending"`
+	// seconds elapsed from the start of the participation till acceptance
+	AcceptedAt *int64 `json:"accepted_at_seconds,omitempty"`
+}
+
+// StandingsRow is the row of a single user, or of a team in team contests
+type StandingsRow struct {
+	Rank      int32           `json:"rank"`
+	UserID    uuid.UUID       `json:"user_id"`
+	UserName  string          `json:"user_name"`
+	RollNo    string          `json:"roll_no"`
+	TeamID    *uuid.UUID      `json:"team_id,omitempty"`
+	TeamName  string          `json:"team_name,omitempty"`
+	MemberIDs []uuid.UUID     `json:"member_ids,omitempty"`
+	IsVirtual bool            `json:"is_virtual"`
+	Score     int32           `json:"score"`
+	Penalty   int64           `json:"penalty"`
+	Problems  []ProblemResult `json:"problems"`
+}
+
+type ContestStandings struct {
+	ContestID  uuid.UUID      `json:"contest_id"`
+	ProblemIDs []int32        `json:"problem_ids"`
+	IsFrozen   bool           `json:"is_frozen"`
+	Rows       []StandingsRow `json:"rows"`
+}
+
+type Clarification struct {
+	ID          uuid.UUID  `json:"clarification_id"`
+	ContestID   uuid.UUID  `json:"contest_id"`
+	ProblemID   *int32     `json:"problem_id"`
+	AskedBy     uuid.UUID  `json:"asked_by"`
+	Question    string     `json:"question"`
+	Answer      *string    `json:"answer"`
+	AnsweredBy  *uuid.UUID `json:"answered_by"`
+	IsBroadcast bool       `json:"is_broadcast"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	AnsweredAt  *time.Time `json:"answered_at"`
+}
+
+type AskClarificationRequest struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	ProblemID *int32    `json:"problem_id"`
+	Question  string    `json:"question" validate:"required,max=2000"`
+}
+
+type AnswerClarificationRequest struct {
+	ClarificationID uuid.UUID `json:"clarification_id"`
+	Answer          string    `json:"answer" validate:"required,max=5000"`
+	// broadcast the answer to every participant of the contest
+	Broadcast bool `json:"broadcast"`
+}
+
+type ContestFreeze struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	// standings freeze these many minutes before the end of the contest
+	FreezeMinutes int32      `json:"freeze_minutes" validate:"min=1"`
+	UnfrozenAt    *time.Time `json:"unfrozen_at"`
+}
+
+// RevealResponse is the outcome of a single resolver step
+type RevealResponse struct {
+	// user or team and problem whose result got revealed, nil if nothing is left to reveal
+	UserID    *uuid.UUID       `json:"user_id"`
+	TeamID    *uuid.UUID       `json:"team_id,omitempty"`
+	ProblemID *int32           `json:"problem_id"`
+	Accepted  bool             `json:"accepted"`
+	Done      bool             `json:"done"`
+	Standings ContestStandings `json:"standings"`
+}
+
+// standingsFreeze decides which verdicts are hidden in the standings
+type standingsFreeze struct {
+	// time elapsed from the start of the participation after which verdicts are hidden
+	offset time.Duration
+	// (user, problem) pairs already revealed by the resolver
+	revealed map[frozenCell]bool
+}
+
+type frozenCell struct {
+	userID    uuid.UUID
+	problemID int32
+}
+
+func (f *standingsFreeze) hides(userID uuid.UUID, problemID int32, elapsed time.Duration) bool {
+	if elapsed < f.offset {
+		return false
+	}
+	return !f.revealed[frozenCell{userID, problemID}]
+}
+
+// revealedResult is a result revealed by a single step of the resolver
+type revealedResult struct {
+	ProblemID int32       `json:"problem_id"`
+	TeamID    *uuid.UUID  `json:"team_id,omitempty"`
+	UserIDs   []uuid.UUID `json:"user_ids"`
+}
+
+type RegistrationSettings struct {
+	ContestID         uuid.UUID `json:"contest_id"`
+	RegistrationStart time.Time `json:"registration_start"`
+	RegistrationEnd   time.Time `json:"registration_end"`
+	// maximum registered users, nil means unlimited
+	Capacity *int32 `json:"capacity" validate:"omitempty,min=1"`
+	// every request must be approved by the creator
+	RequiresApproval bool `json:"requires_approval"`
+}
+
+type RegistrationRequest struct {
+	ContestID   uuid.UUID `json:"contest_id"`
+	UserID      uuid.UUID `json:"user_id"`
+	UserName    string    `json:"user_name"`
+	Status      string    `json:"status"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+// RegistrationResponse tells where the user stands after a registration action
+type RegistrationResponse struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	// one of registered, pending, waitlisted or rejected
+	Status string `json:"status"`
+}
+
+type ReviewRegistrationRequest struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Approve   bool      `json:"approve"`
+}
+
+type ContestTeamSettings struct {
+	ContestID   uuid.UUID `json:"contest_id"`
+	MaxTeamSize int32     `json:"max_team_size" validate:"min=1"`
+}
+
+type ContestTeam struct {
+	TeamID    uuid.UUID   `json:"team_id"`
+	Name      string      `json:"name"`
+	MemberIDs []uuid.UUID `json:"member_ids"`
+}
+
+// standingsTeam is a team as it was registered into the contest
+type standingsTeam struct {
+	id      uuid.UUID
+	name    string
+	members []uuid.UUID
+}
+
+type ProblemSwap struct {
+	OldProblemID int32 `json:"old_problem_id"`
+	NewProblemID int32 `json:"new_problem_id"`
+}
+
+type CloneContestRequest struct {
+	SourceContestID uuid.UUID `json:"source_contest_id"`
+	// defaults to the title of the source contest
+	Title *string `json:"title" validate:"omitempty,min=5,max=100"`
+	// lock of a public clone, defaults to the lock of the source contest
+	LockID *uuid.UUID `json:"lock_id"`
+	// start time of the clone, public clones behind a timer lock start with it
+	StartTime *time.Time `json:"start_time"`
+	// defaults to keeping the duration of the source contest
+	EndTime      *time.Time    `json:"end_time"`
+	IncludeUsers bool          `json:"include_users"`
+	ProblemSwaps []ProblemSwap `json:"problem_swaps"`
+}
+
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatJSON = "json"
+	// results.tsv of the ICPC Contest API
+	ExportFormatICPC = "icpc"
+)
+
+type ExportResultsRequest struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	Format    string    `json:"format" validate:"oneof=csv json icpc"`
+	// include the virtual participants
+	IncludeUnofficial bool `json:"include_unofficial"`
+}
+
+// ContestResults is the document exported in the json format
+type ContestResults struct {
+	ContestID  uuid.UUID      `json:"contest_id"`
+	Title      string         `json:"title"`
+	StartTime  time.Time      `json:"start_time"`
+	EndTime    time.Time      `json:"end_time"`
+	ProblemIDs []int32        `json:"problem_ids"`
+	Rows       []StandingsRow `json:"rows"`
+}
+
+type ExportedResults struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+type SetRemindersRequest struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	// minutes before the start, up to 30 days
+	OffsetMinutes []int32 `json:"offset_minutes" validate:"max=5,dive,min=1,max=43200"`
+}
+
+type ContestReminder struct {
+	OffsetMinutes int32      `json:"offset_minutes"`
+	SentAt        *time.Time `json:"sent_at"`
+}
+
+type BroadcastRequest struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	Subject   string    `json:"subject" validate:"min=5,max=100"`
+	Body      string    `json:"body" validate:"min=1,max=5000"`
+}
@@ -0,0 +1,355 @@
+package contest_service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/email"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// SetContestReminders replaces the reminders of the contest. Reminders are mailed
+// to the participants the given minutes before the start of the contest.
+func (c *ContestService) SetContestReminders(
+	ctx context.Context,
+	request SetRemindersRequest,
+) ([]ContestReminder, error) {
+	// get the contest
+	contest, err := c.GetContestByID(ctx, request.ContestID)
+	if err != nil {
+		return nil, err
+	}
+
+	// authorize
+	err = c.authorizeContestUpdate(ctx, contest)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[int32]bool, len(request.OffsetMinutes))
+	for _, offset := range request.OffsetMinutes {
+		if seen[offset] {
+			return nil, fmt.Errorf(
+				"%w, reminder at %d minutes is given more than once",
+				flux_errors.ErrInvalidRequest,
+				offset,
+			)
+		}
+		seen[offset] = true
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	// replace the old reminders
+	err = qtx.DeleteContestReminders(ctx, contest.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot delete reminders of contest %v", contest.ID),
+		)
+		return nil, err
+	}
+	for _, offset := range request.OffsetMinutes {
+		err = qtx.CreateContestReminder(
+			ctx,
+			database.CreateContestReminderParams{
+				ContestID:     contest.ID,
+				OffsetMinutes: offset,
+			},
+		)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot create reminder of contest %v", contest.ID),
+			)
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf(
+			"%w, cannot commit transaction after setting reminders of contest %v, %w",
+			flux_errors.ErrInternal,
+			contest.ID,
+			err,
+		)
+	}
+
+	return c.GetContestReminders(ctx, contest.ID)
+}
+
+func (c *ContestService) GetContestReminders(
+	ctx context.Context,
+	contestID uuid.UUID,
+) ([]ContestReminder, error) {
+	// get the contest
+	contest, err := c.GetContestByID(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+
+	// authorize
+	err = c.authorizeContestUpdate(ctx, contest)
+	if err != nil {
+		return nil, err
+	}
+
+	dbReminders, err := c.DB.GetContestReminders(ctx, contest.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch reminders of contest %v", contest.ID),
+		)
+		return nil, err
+	}
+
+	reminders := make([]ContestReminder, 0, len(dbReminders))
+	for _, dbReminder := range dbReminders {
+		reminders = append(reminders, ContestReminder{
+			OffsetMinutes: dbReminder.OffsetMinutes,
+			SentAt:        dbReminder.SentAt,
+		})
+	}
+
+	return reminders, nil
+}
+
+// BroadcastToParticipants mails the message to the registered users of the
+// contest who have not opted out and returns the number of mails queued
+func (c *ContestService) BroadcastToParticipants(
+	ctx context.Context,
+	request BroadcastRequest,
+) (int, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// get the contest
+	contest, err := c.GetContestByID(ctx, request.ContestID)
+	if err != nil {
+		return 0, err
+	}
+
+	// authorize
+	err = c.authorizeContestUpdate(ctx, contest)
+	if err != nil {
+		return 0, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return 0, err
+	}
+
+	recipients, err := c.DB.GetRegisteredUserRecipients(ctx, contest.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch registered users of contest %v", contest.ID),
+		)
+		return 0, err
+	}
+
+	subject := fmt.Sprintf("[%s] %s", contest.Title, request.Subject)
+	sent := 0
+	for _, recipient := range recipients {
+		err = email.NewMail(
+			ctx,
+			subject,
+			request.Body,
+			email.KeyEmailBodyPlain,
+			email.PurposeContestBroadcast,
+			recipient.Email,
+		)
+		if err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	log.Infof(
+		"user %s broadcasted a message to %d participants of contest %v",
+		claims.UserName,
+		sent,
+		contest.ID,
+	)
+
+	return sent, nil
+}
+
+// SendDueReminders mails the reminders whose time has come. Each reminder is
+// claimed before mailing, so it is sent at most once.
+func (c *ContestService) SendDueReminders(ctx context.Context) error {
+	dbReminders, err := c.DB.ClaimDueContestReminders(ctx)
+	if err != nil {
+		return flux_errors.HandleDBErrors(err, errMsgs, "cannot claim due contest reminders")
+	}
+
+	for _, dbReminder := range dbReminders {
+		contest, err := c.GetContestByID(ctx, dbReminder.ContestID)
+		if err != nil {
+			log.Errorf("cannot get contest %v for its reminder, %v", dbReminder.ContestID, err)
+			continue
+		}
+
+		subject := fmt.Sprintf("Reminder: %s starts soon", contest.Title)
+		body := fmt.Sprintf(
+			"Hello,\n\nThe contest %s starts at %s and ends at %s.\n\nAll the best!",
+			contest.Title,
+			contest.StartTime.Format(time.RFC1123),
+			contest.EndTime.Format(time.RFC1123),
+		)
+		c.mailParticipants(ctx, contest.ID, email.PurposeContestReminder, func(uuid.UUID) (string, string, bool) {
+			return subject, body, true
+		})
+	}
+
+	return nil
+}
+
+// SendResultsSummary mails every participant their final rank and score in
+// the contest
+func (c *ContestService) SendResultsSummary(ctx context.Context, contestID uuid.UUID) error {
+	contest, err := c.GetContestByID(ctx, contestID)
+	if err != nil {
+		return err
+	}
+
+	standings, err := c.GetFinalStandings(ctx, contest.ID)
+	if err != nil {
+		return err
+	}
+
+	// members of a team share the row of the team
+	rows := make(map[uuid.UUID]StandingsRow)
+	official := 0
+	for _, row := range standings.Rows {
+		if row.IsVirtual {
+			continue
+		}
+		official++
+		if row.TeamID != nil {
+			for _, memberID := range row.MemberIDs {
+				rows[memberID] = row
+			}
+			continue
+		}
+		rows[row.UserID] = row
+	}
+
+	subject := fmt.Sprintf("Results of %s", contest.Title)
+	c.mailParticipants(ctx, contest.ID, email.PurposeContestResults, func(userID uuid.UUID) (string, string, bool) {
+		row, ok := rows[userID]
+		if !ok {
+			return "", "", false
+		}
+		body := fmt.Sprintf(
+			"Hello,\n\nThe contest %s has ended. You finished at rank %d of %d with a score of %d and a penalty of %d.\n\nThank you for participating!",
+			contest.Title,
+			row.Rank,
+			official,
+			row.Score,
+			row.Penalty,
+		)
+		return subject, body, true
+	})
+
+	return nil
+}
+
+// NotifyRegistered confirms the registration to the users. It is called after
+// the registration is committed, also by the services registering users within
+// their own transaction. Mails are sent on a best effort basis.
+func (c *ContestService) NotifyRegistered(
+	ctx context.Context,
+	contestID uuid.UUID,
+	userIDs []uuid.UUID,
+) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	contest, err := c.GetContestByID(ctx, contestID)
+	if err != nil {
+		log.Errorf("cannot get contest %v to confirm registrations, %v", contestID, err)
+		return
+	}
+
+	recipients, err := c.DB.GetUserRecipients(ctx, userIDs)
+	if err != nil {
+		log.Errorf("cannot fetch mails to confirm registrations in contest %v, %v", contestID, err)
+		return
+	}
+
+	subject := fmt.Sprintf("Registered for %s", contest.Title)
+	body := fmt.Sprintf(
+		"Hello,\n\nYou are registered for the contest %s. The contest starts at %s and ends at %s.\n\nAll the best!",
+		contest.Title,
+		contest.StartTime.Format(time.RFC1123),
+		contest.EndTime.Format(time.RFC1123),
+	)
+	for _, recipient := range recipients {
+		err = email.NewMail(
+			ctx, subject, body, email.KeyEmailBodyPlain, email.PurposeContestRegistered, recipient.Email,
+		)
+		if err != nil {
+			log.Errorf("cannot confirm registrations in contest %v, %v", contestID, err)
+			return
+		}
+	}
+}
+
+// mailParticipants mails every participant of the contest who has not opted
+// out. compose returns the mail of a participant, or false to skip them.
+func (c *ContestService) mailParticipants(
+	ctx context.Context,
+	contestID uuid.UUID,
+	purpose email.EmailPurpose,
+	compose func(userID uuid.UUID) (string, string, bool),
+) {
+	recipients, err := c.DB.GetContestParticipantRecipients(ctx, contestID)
+	if err != nil {
+		log.Errorf("cannot fetch participants of contest %v, %v", contestID, err)
+		return
+	}
+
+	// one mail per participant, so the addresses are not shared
+	for _, recipient := range recipients {
+		subject, body, ok := compose(recipient.ID)
+		if !ok {
+			continue
+		}
+		err = email.NewMail(ctx, subject, body, email.KeyEmailBodyPlain, purpose, recipient.Email)
+		if err != nil {
+			log.Errorf("cannot mail participants of contest %v, %v", contestID, err)
+			return
+		}
+	}
+}
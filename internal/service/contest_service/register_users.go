@@ -3,9 +3,11 @@ package contest_service
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service"
 )
@@ -15,44 +17,87 @@ func (c *ContestService) RegisterUsersToContest(
 	contestID uuid.UUID,
 	userNames []string,
 ) error {
+	// create a new transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	// replace the users
+	newUserIDs, err := c.ReplaceContestUsers(ctx, qtx, contestID, userNames)
+	if err != nil {
+		return err
+	}
+
+	// commit the transaction if started
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after registering users, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		return err
+	}
+
+	// only the users who were not registered before
+	c.NotifyRegistered(ctx, contestID, newUserIDs)
+
+	return nil
+}
+
+// ReplaceContestUsers replaces the registered users of the contest within the
+// transaction of the caller, so that other services can register users along
+// with their own changes. Returns the ids of the users who were not registered
+// before, to be notified after the commit.
+func (c *ContestService) ReplaceContestUsers(
+	ctx context.Context,
+	qtx *database.Queries,
+	contestID uuid.UUID,
+	userNames []string,
+) ([]uuid.UUID, error) {
 	// get claims
 	claims, err := service.GetClaimsFromContext(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// get contest
 	contest, err := c.GetContestByID(ctx, contestID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// authorize
 	err = c.authorizeContestUpdate(ctx, contest)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// published contest cannot have any registered users
 	if contest.IsPublished {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"%w, published contests cannot have any registered users",
 			flux_errors.ErrInvalidRequest,
 		)
 	}
 
-	// create a new transaction
-	tx, err := service.GetNewTransaction(ctx)
+	// users before the change
+	prevUserIDs, err := qtx.GetContestUsers(ctx, contestID)
 	if err != nil {
-		return err
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch users of contest %v", contestID),
+		)
+		return nil, err
 	}
 
-	// if anything goes wrong roll back
-	defer tx.Rollback(ctx)
-
-	// get a new query tool with this transaction
-	qtx := c.DB.WithTx(tx)
-
 	// unregister previous users
 	err = qtx.UnRegisterContestUsers(ctx, contestID)
 	if err != nil {
@@ -61,23 +106,25 @@ func (c *ContestService) RegisterUsersToContest(
 			errMsgs,
 			fmt.Sprintf("cannot unregister users of contest %v", contestID),
 		)
-		return err
+		return nil, err
 	}
 
 	// add users to contest
-	err = c.addUsersToContest(ctx, qtx, contestID, userNames)
+	userIDs, err := c.addUsersToContest(ctx, qtx, contestID, userNames)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// commit the transaction if started
-	if err = tx.Commit(ctx); err != nil {
-		err = fmt.Errorf(
-			"%w, cannot commit transaction after registering users, %w",
-			flux_errors.ErrInternal,
-			err,
-		)
-		return err
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionContestSetUsers,
+		EntityType: auditEntityContest,
+		EntityID:   contestID,
+		Before:     prevUserIDs,
+		After:      userIDs,
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// log if it is a public contest
@@ -89,5 +136,13 @@ func (c *ContestService) RegisterUsersToContest(
 		)
 	}
 
-	return nil
+	// users who were not registered before
+	newUserIDs := make([]uuid.UUID, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if !slices.Contains(prevUserIDs, userID) {
+			newUserIDs = append(newUserIDs, userID)
+		}
+	}
+
+	return newUserIDs, nil
 }
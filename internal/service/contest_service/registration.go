@@ -0,0 +1,1199 @@
+package contest_service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+func (c *ContestService) SetRegistrationSettings(
+	ctx context.Context,
+	settings RegistrationSettings,
+) (RegistrationSettings, error) {
+	// validate
+	err := service.ValidateInput(settings)
+	if err != nil {
+		return RegistrationSettings{}, err
+	}
+
+	// get the contest
+	contest, err := c.GetContestByID(ctx, settings.ContestID)
+	if err != nil {
+		return RegistrationSettings{}, err
+	}
+
+	// authorize
+	err = c.authorizeContestUpdate(ctx, contest)
+	if err != nil {
+		return RegistrationSettings{}, err
+	}
+
+	// published contests are open to everyone
+	if contest.IsPublished {
+		return RegistrationSettings{}, fmt.Errorf(
+			"%w, published contests cannot have registrations",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// window must close before the contest starts
+	if !settings.RegistrationStart.Before(settings.RegistrationEnd) {
+		return RegistrationSettings{}, fmt.Errorf(
+			"%w, registration must start before it ends",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+	if settings.RegistrationEnd.After(*contest.StartTime) {
+		return RegistrationSettings{}, fmt.Errorf(
+			"%w, registration must end before the contest starts",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return RegistrationSettings{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	// settings before the change, nil if there were none
+	var prevSettings *RegistrationSettings
+	dbPrevSettings, err := qtx.GetContestRegistrationSettings(ctx, settings.ContestID)
+	if err == nil {
+		prev := dbRegistrationSettingsToServiceSettings(dbPrevSettings)
+		prevSettings = &prev
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch registration settings of contest %v", settings.ContestID),
+		)
+		return RegistrationSettings{}, err
+	}
+
+	dbSettings, err := qtx.SetContestRegistrationSettings(
+		ctx,
+		database.SetContestRegistrationSettingsParams{
+			ContestID:         settings.ContestID,
+			RegistrationStart: settings.RegistrationStart,
+			RegistrationEnd:   settings.RegistrationEnd,
+			Capacity:          settings.Capacity,
+			RequiresApproval:  settings.RequiresApproval,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot set registration settings of contest %v", settings.ContestID),
+		)
+		return RegistrationSettings{}, err
+	}
+	newSettings := dbRegistrationSettingsToServiceSettings(dbSettings)
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionContestSetRegistration,
+		EntityType: auditEntityContest,
+		EntityID:   settings.ContestID,
+		Before:     prevSettings,
+		After:      newSettings,
+	})
+	if err != nil {
+		return RegistrationSettings{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return RegistrationSettings{}, fmt.Errorf(
+			"%w, cannot commit transaction after setting registration settings of contest %v, %w",
+			flux_errors.ErrInternal,
+			settings.ContestID,
+			err,
+		)
+	}
+
+	return newSettings, nil
+}
+
+func (c *ContestService) GetRegistrationSettings(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (RegistrationSettings, error) {
+	dbSettings, err := c.DB.GetContestRegistrationSettings(ctx, contestID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch registration settings of contest %v", contestID),
+		)
+		return RegistrationSettings{}, err
+	}
+
+	return dbRegistrationSettingsToServiceSettings(dbSettings), nil
+}
+
+// RegisterSelf registers the user into the contest. The request is kept pending if
+// the contest requires approval and is waitlisted if the contest is full.
+func (c *ContestService) RegisterSelf(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (RegistrationResponse, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return RegistrationResponse{}, err
+	}
+
+	// get the contest and check if registration is open
+	err = c.validateRegistrationWindow(ctx, contestID)
+	if err != nil {
+		return RegistrationResponse{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return RegistrationResponse{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	// lock the settings so that concurrent registrations respect the capacity
+	dbSettings, err := qtx.LockContestRegistrationSettings(ctx, contestID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot lock registration settings of contest %v", contestID),
+		)
+		return RegistrationResponse{}, err
+	}
+
+	// user must not be registered already
+	registered, err := qtx.IsUserRegisteredInContest(
+		ctx,
+		database.IsUserRegisteredInContestParams{
+			ContestID: contestID,
+			UserID:    claims.UserId,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf(
+				"cannot check if user %s is registered in contest %v",
+				claims.UserName,
+				contestID,
+			),
+		)
+		return RegistrationResponse{}, err
+	}
+	if registered {
+		return RegistrationResponse{}, fmt.Errorf(
+			"%w, you are already registered in this contest",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// user must not have an open request
+	dbRequest, err := qtx.GetRegistrationRequest(
+		ctx,
+		database.GetRegistrationRequestParams{
+			ContestID: contestID,
+			UserID:    claims.UserId,
+		},
+	)
+	if err == nil {
+		return RegistrationResponse{}, fmt.Errorf(
+			"%w, your registration request is already %s",
+			flux_errors.ErrInvalidRequest,
+			dbRequest.Status,
+		)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf(
+				"cannot fetch registration request of user %s in contest %v",
+				claims.UserName,
+				contestID,
+			),
+		)
+		return RegistrationResponse{}, err
+	}
+
+	// creator decides the requests of contests requiring approval
+	var status string
+	if dbSettings.RequiresApproval {
+		_, err = qtx.CreateRegistrationRequest(
+			ctx,
+			database.CreateRegistrationRequestParams{
+				ContestID: contestID,
+				UserID:    claims.UserId,
+				Status:    database.RegistrationStatusPending,
+			},
+		)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf(
+					"cannot create registration request of user %s in contest %v",
+					claims.UserName,
+					contestID,
+				),
+			)
+			return RegistrationResponse{}, err
+		}
+		status = string(database.RegistrationStatusPending)
+	} else {
+		status, err = c.registerOrWaitlist(ctx, qtx, dbSettings, claims.UserId)
+		if err != nil {
+			return RegistrationResponse{}, err
+		}
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after registering user %s in contest %v, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			contestID,
+			err,
+		)
+		log.Error(err)
+		return RegistrationResponse{}, err
+	}
+
+	if status == RegistrationStatusRegistered {
+		c.NotifyRegistered(ctx, contestID, []uuid.UUID{claims.UserId})
+	}
+
+	return RegistrationResponse{
+		ContestID: contestID,
+		UserID:    claims.UserId,
+		Status:    status,
+	}, nil
+}
+
+// UnregisterSelf withdraws the registration or the open request of the user. A freed
+// seat is handed over to the earliest waitlisted user.
+func (c *ContestService) UnregisterSelf(
+	ctx context.Context,
+	contestID uuid.UUID,
+) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// get the contest and check if registration is open
+	err = c.validateRegistrationWindow(ctx, contestID)
+	if err != nil {
+		return err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	// lock the settings so that the waitlist is promoted only once
+	dbSettings, err := qtx.LockContestRegistrationSettings(ctx, contestID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot lock registration settings of contest %v", contestID),
+		)
+		return err
+	}
+
+	registered, err := qtx.IsUserRegisteredInContest(
+		ctx,
+		database.IsUserRegisteredInContestParams{
+			ContestID: contestID,
+			UserID:    claims.UserId,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf(
+				"cannot check if user %s is registered in contest %v",
+				claims.UserName,
+				contestID,
+			),
+		)
+		return err
+	}
+
+	var promoted []uuid.UUID
+
+	if registered {
+		// remove the registration and fill the seat
+		err = qtx.UnregisterUserFromContest(
+			ctx,
+			database.UnregisterUserFromContestParams{
+				ContestID: contestID,
+				UserID:    claims.UserId,
+			},
+		)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf(
+					"cannot unregister user %s from contest %v",
+					claims.UserName,
+					contestID,
+				),
+			)
+			return err
+		}
+
+		promoted, err = c.promoteWaitlist(ctx, qtx, dbSettings)
+		if err != nil {
+			return err
+		}
+	} else {
+		// withdraw the open request, rejected ones stay as they are
+		dbRequest, err := qtx.GetRegistrationRequest(
+			ctx,
+			database.GetRegistrationRequestParams{
+				ContestID: contestID,
+				UserID:    claims.UserId,
+			},
+		)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf(
+					"%w, you are not registered in this contest",
+					flux_errors.ErrInvalidRequest,
+				)
+			}
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf(
+					"cannot fetch registration request of user %s in contest %v",
+					claims.UserName,
+					contestID,
+				),
+			)
+			return err
+		}
+		if dbRequest.Status == database.RegistrationStatusRejected {
+			return fmt.Errorf(
+				"%w, your registration request is already rejected",
+				flux_errors.ErrInvalidRequest,
+			)
+		}
+
+		err = qtx.DeleteRegistrationRequest(
+			ctx,
+			database.DeleteRegistrationRequestParams{
+				ContestID: contestID,
+				UserID:    claims.UserId,
+			},
+		)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf(
+					"cannot delete registration request of user %s in contest %v",
+					claims.UserName,
+					contestID,
+				),
+			)
+			return err
+		}
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after unregistering user %s from contest %v, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			contestID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	c.NotifyRegistered(ctx, contestID, promoted)
+
+	return nil
+}
+
+// GetRegistrationRequests returns the requests of the contest, optionally filtered by
+// their status. Only the jury of the contest can see them.
+func (c *ContestService) GetRegistrationRequests(
+	ctx context.Context,
+	contestID uuid.UUID,
+	status *string,
+) ([]RegistrationRequest, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// get the contest
+	contest, err := c.GetContestByID(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+
+	// authorize
+	err = c.authorizeContestJury(
+		ctx,
+		contest,
+		fmt.Sprintf(
+			"user %s tried to fetch registration requests of contest %v",
+			claims.UserName,
+			contestID,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate the status filter
+	var statusFilter database.NullRegistrationStatus
+	if status != nil {
+		switch database.RegistrationStatus(*status) {
+		case database.RegistrationStatusPending,
+			database.RegistrationStatusWaitlisted,
+			database.RegistrationStatusRejected:
+			statusFilter = database.NullRegistrationStatus{
+				RegistrationStatus: database.RegistrationStatus(*status),
+				Valid:              true,
+			}
+		default:
+			return nil, fmt.Errorf(
+				"%w, invalid registration status %s",
+				flux_errors.ErrInvalidRequest,
+				*status,
+			)
+		}
+	}
+
+	dbRequests, err := c.DB.GetContestRegistrationRequests(
+		ctx,
+		database.GetContestRegistrationRequestsParams{
+			ContestID: contestID,
+			Status:    statusFilter,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch registration requests of contest %v", contestID),
+		)
+		return nil, err
+	}
+
+	// check for empty
+	if len(dbRequests) == 0 {
+		return make([]RegistrationRequest, 0), nil
+	}
+
+	// fetch the users who made the requests
+	userIDs := make([]uuid.UUID, 0, len(dbRequests))
+	for _, dbRequest := range dbRequests {
+		userIDs = append(userIDs, dbRequest.UserID)
+	}
+	users, err := c.UserServiceConfig.GetUsersByFilters(
+		ctx,
+		user_service.GetUsersRequest{
+			UserIDs:    userIDs,
+			PageNumber: 1,
+			PageSize:   int32(len(userIDs)),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	userNames := make(map[uuid.UUID]string, len(users))
+	for _, user := range users {
+		userNames[user.UserID] = user.UserName
+	}
+
+	res := make([]RegistrationRequest, 0, len(dbRequests))
+	for _, dbRequest := range dbRequests {
+		res = append(res, RegistrationRequest{
+			ContestID:   dbRequest.ContestID,
+			UserID:      dbRequest.UserID,
+			UserName:    userNames[dbRequest.UserID],
+			Status:      string(dbRequest.Status),
+			RequestedAt: dbRequest.RequestedAt.UTC(),
+		})
+	}
+
+	return res, nil
+}
+
+// ReviewRegistrationRequest approves or rejects a pending request. An approved
+// request gets waitlisted if the contest is already full.
+func (c *ContestService) ReviewRegistrationRequest(
+	ctx context.Context,
+	request ReviewRegistrationRequest,
+) (RegistrationResponse, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return RegistrationResponse{}, err
+	}
+
+	// get the contest
+	contest, err := c.GetContestByID(ctx, request.ContestID)
+	if err != nil {
+		return RegistrationResponse{}, err
+	}
+
+	// authorize
+	err = c.authorizeContestUpdate(ctx, contest)
+	if err != nil {
+		return RegistrationResponse{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return RegistrationResponse{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	// lock the settings so that approvals respect the capacity
+	dbSettings, err := qtx.LockContestRegistrationSettings(ctx, request.ContestID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot lock registration settings of contest %v", request.ContestID),
+		)
+		return RegistrationResponse{}, err
+	}
+
+	// get the request
+	dbRequest, err := qtx.GetRegistrationRequest(
+		ctx,
+		database.GetRegistrationRequestParams{
+			ContestID: request.ContestID,
+			UserID:    request.UserID,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf(
+				"cannot fetch registration request of user %v in contest %v",
+				request.UserID,
+				request.ContestID,
+			),
+		)
+		return RegistrationResponse{}, err
+	}
+	if dbRequest.Status != database.RegistrationStatusPending {
+		return RegistrationResponse{}, fmt.Errorf(
+			"%w, only pending requests can be reviewed, request is %s",
+			flux_errors.ErrInvalidRequest,
+			dbRequest.Status,
+		)
+	}
+	prevRequest := RegistrationRequest{
+		ContestID:   dbRequest.ContestID,
+		UserID:      dbRequest.UserID,
+		Status:      string(dbRequest.Status),
+		RequestedAt: dbRequest.RequestedAt.UTC(),
+	}
+
+	var status string
+	if request.Approve {
+		status, err = c.registerOrWaitlist(ctx, qtx, dbSettings, request.UserID)
+		if err != nil {
+			return RegistrationResponse{}, err
+		}
+	} else {
+		dbRequest, err = qtx.UpdateRegistrationRequestStatus(
+			ctx,
+			database.UpdateRegistrationRequestStatusParams{
+				ContestID: request.ContestID,
+				UserID:    request.UserID,
+				Status:    database.RegistrationStatusRejected,
+			},
+		)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf(
+					"cannot reject registration request of user %v in contest %v",
+					request.UserID,
+					request.ContestID,
+				),
+			)
+			return RegistrationResponse{}, err
+		}
+		status = string(dbRequest.Status)
+	}
+	response := RegistrationResponse{
+		ContestID: request.ContestID,
+		UserID:    request.UserID,
+		Status:    status,
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionContestReviewRegistration,
+		EntityType: auditEntityContest,
+		EntityID:   request.ContestID,
+		Before:     prevRequest,
+		After:      response,
+	})
+	if err != nil {
+		return RegistrationResponse{}, err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after reviewing registration request of user %v in contest %v, %w",
+			flux_errors.ErrInternal,
+			request.UserID,
+			request.ContestID,
+			err,
+		)
+		log.Error(err)
+		return RegistrationResponse{}, err
+	}
+
+	log.Infof(
+		"user %s reviewed registration request of user %v in contest %v, status: %s",
+		claims.UserName,
+		request.UserID,
+		request.ContestID,
+		status,
+	)
+
+	if status == RegistrationStatusRegistered {
+		c.NotifyRegistered(ctx, request.ContestID, []uuid.UUID{request.UserID})
+	}
+
+	return response, nil
+}
+
+// AddUsersToContest registers the given users without touching the existing
+// registrations. Users added by the creator are not limited by the capacity.
+func (c *ContestService) AddUsersToContest(
+	ctx context.Context,
+	contestID uuid.UUID,
+	userNames []string,
+) error {
+	// get the contest and authorize
+	_, err := c.getContestForRegistrationUpdate(ctx, contestID)
+	if err != nil {
+		return err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	// add users to contest
+	userIDs, err := c.addUsersToContest(ctx, qtx, contestID, userNames)
+	if err != nil {
+		return err
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionContestAddUsers,
+		EntityType: auditEntityContest,
+		EntityID:   contestID,
+		After:      userIDs,
+	})
+	if err != nil {
+		return err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after adding users to contest %v, %w",
+			flux_errors.ErrInternal,
+			contestID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	c.NotifyRegistered(ctx, contestID, userIDs)
+
+	return nil
+}
+
+// RemoveUsersFromContest unregisters the given users. Freed seats are handed
+// over to the waitlisted users.
+func (c *ContestService) RemoveUsersFromContest(
+	ctx context.Context,
+	contestID uuid.UUID,
+	userNames []string,
+) error {
+	// get the contest and authorize
+	_, err := c.getContestForRegistrationUpdate(ctx, contestID)
+	if err != nil {
+		return err
+	}
+
+	// check for empty
+	if len(userNames) == 0 {
+		return nil
+	}
+
+	// the same user may be given more than once
+	userNames = slices.Compact(slices.Sorted(slices.Values(userNames)))
+
+	// fetch users by their names
+	users, err := c.UserServiceConfig.GetUsersByFilters(
+		ctx,
+		user_service.GetUsersRequest{
+			UserNames:  userNames,
+			PageNumber: 1,
+			PageSize:   int32(len(userNames)),
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if len(users) != len(userNames) {
+		return fmt.Errorf(
+			"%w, some of the given users do not exist",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	// lock the settings if the contest has any
+	dbSettings, err := qtx.LockContestRegistrationSettings(ctx, contestID)
+	hasSettings := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot lock registration settings of contest %v", contestID),
+		)
+		return err
+	}
+
+	removedIDs := make([]uuid.UUID, 0, len(users))
+	for _, user := range users {
+		err = qtx.UnregisterUserFromContest(
+			ctx,
+			database.UnregisterUserFromContestParams{
+				ContestID: contestID,
+				UserID:    user.UserID,
+			},
+		)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot unregister user %s from contest %v", user.UserName, contestID),
+			)
+			return err
+		}
+		removedIDs = append(removedIDs, user.UserID)
+	}
+
+	// fill the freed seats
+	var promoted []uuid.UUID
+	if hasSettings {
+		promoted, err = c.promoteWaitlist(ctx, qtx, dbSettings)
+		if err != nil {
+			return err
+		}
+	}
+
+	// record the change, the waitlisted users take the freed seats
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionContestRemoveUsers,
+		EntityType: auditEntityContest,
+		EntityID:   contestID,
+		Before:     removedIDs,
+		After:      promoted,
+	})
+	if err != nil {
+		return err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after removing users from contest %v, %w",
+			flux_errors.ErrInternal,
+			contestID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	c.NotifyRegistered(ctx, contestID, promoted)
+
+	return nil
+}
+
+func (c *ContestService) getContestForRegistrationUpdate(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (Contest, error) {
+	// get contest
+	contest, err := c.GetContestByID(ctx, contestID)
+	if err != nil {
+		return Contest{}, err
+	}
+
+	// authorize
+	err = c.authorizeContestUpdate(ctx, contest)
+	if err != nil {
+		return Contest{}, err
+	}
+
+	// published contest cannot have any registered users
+	if contest.IsPublished {
+		return Contest{}, fmt.Errorf(
+			"%w, published contests cannot have any registered users",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	return contest, nil
+}
+
+// validateRegistrationWindow checks if users can register themselves into the contest now
+func (c *ContestService) validateRegistrationWindow(
+	ctx context.Context,
+	contestID uuid.UUID,
+) error {
+	// get the contest
+	contest, err := c.GetContestByID(ctx, contestID)
+	if err != nil {
+		return err
+	}
+
+	// published contests are open to everyone
+	if contest.IsPublished {
+		return fmt.Errorf(
+			"%w, published contests do not need registration",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// get the settings
+	dbSettings, err := c.DB.GetContestRegistrationSettings(ctx, contestID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf(
+				"%w, contest does not allow self registration",
+				flux_errors.ErrInvalidRequest,
+			)
+		}
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch registration settings of contest %v", contestID),
+		)
+		return err
+	}
+
+	// contest start time must not be nil
+	if contest.StartTime == nil {
+		err = fmt.Errorf(
+			"%w, contest %v has start time as nil, cannot register",
+			flux_errors.ErrInternal,
+			contestID,
+		)
+		log.Error(err)
+		return err
+	}
+
+	// registration closes with the window or the start of the contest
+	now := time.Now()
+	if now.Before(dbSettings.RegistrationStart) ||
+		now.After(dbSettings.RegistrationEnd) ||
+		now.After(*contest.StartTime) {
+		return fmt.Errorf(
+			"%w, registration is not open",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	return nil
+}
+
+// registerOrWaitlist registers the user if the contest has a free seat, waitlists
+// otherwise. Settings of the contest must be locked by the transaction of qtx.
+func (c *ContestService) registerOrWaitlist(
+	ctx context.Context,
+	qtx *database.Queries,
+	dbSettings database.ContestRegistrationSetting,
+	userID uuid.UUID,
+) (string, error) {
+	full, err := c.isContestFull(ctx, qtx, dbSettings)
+	if err != nil {
+		return "", err
+	}
+
+	// check for an existing request
+	_, err = qtx.GetRegistrationRequest(
+		ctx,
+		database.GetRegistrationRequestParams{
+			ContestID: dbSettings.ContestID,
+			UserID:    userID,
+		},
+	)
+	hasRequest := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf(
+				"cannot fetch registration request of user %v in contest %v",
+				userID,
+				dbSettings.ContestID,
+			),
+		)
+		return "", err
+	}
+
+	if full {
+		if hasRequest {
+			_, err = qtx.UpdateRegistrationRequestStatus(
+				ctx,
+				database.UpdateRegistrationRequestStatusParams{
+					ContestID: dbSettings.ContestID,
+					UserID:    userID,
+					Status:    database.RegistrationStatusWaitlisted,
+				},
+			)
+		} else {
+			_, err = qtx.CreateRegistrationRequest(
+				ctx,
+				database.CreateRegistrationRequestParams{
+					ContestID: dbSettings.ContestID,
+					UserID:    userID,
+					Status:    database.RegistrationStatusWaitlisted,
+				},
+			)
+		}
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf(
+					"cannot waitlist user %v in contest %v",
+					userID,
+					dbSettings.ContestID,
+				),
+			)
+			return "", err
+		}
+		return string(database.RegistrationStatusWaitlisted), nil
+	}
+
+	err = c.registerRequestedUser(ctx, qtx, dbSettings.ContestID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	return RegistrationStatusRegistered, nil
+}
+
+// promoteWaitlist registers the waitlisted users in the order of their requests
+// until the contest is full and returns the promoted users. Settings of the
+// contest must be locked by the transaction of qtx.
+func (c *ContestService) promoteWaitlist(
+	ctx context.Context,
+	qtx *database.Queries,
+	dbSettings database.ContestRegistrationSetting,
+) ([]uuid.UUID, error) {
+	var promoted []uuid.UUID
+	for {
+		full, err := c.isContestFull(ctx, qtx, dbSettings)
+		if err != nil {
+			return nil, err
+		}
+		if full {
+			return promoted, nil
+		}
+
+		dbRequest, err := qtx.GetFirstWaitlistedRequest(ctx, dbSettings.ContestID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return promoted, nil
+			}
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot fetch waitlist of contest %v", dbSettings.ContestID),
+			)
+			return nil, err
+		}
+
+		err = c.registerRequestedUser(ctx, qtx, dbSettings.ContestID, dbRequest.UserID)
+		if err != nil {
+			return nil, err
+		}
+		promoted = append(promoted, dbRequest.UserID)
+		log.Infof(
+			"promoted waitlisted user %v in contest %v",
+			dbRequest.UserID,
+			dbSettings.ContestID,
+		)
+	}
+}
+
+func (c *ContestService) isContestFull(
+	ctx context.Context,
+	qtx *database.Queries,
+	dbSettings database.ContestRegistrationSetting,
+) (bool, error) {
+	// no capacity means unlimited seats
+	if dbSettings.Capacity == nil {
+		return false, nil
+	}
+
+	count, err := qtx.CountContestRegisteredUsers(ctx, dbSettings.ContestID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot count registered users of contest %v", dbSettings.ContestID),
+		)
+		return false, err
+	}
+
+	return count >= int64(*dbSettings.Capacity), nil
+}
+
+// registerRequestedUser turns the request of the user, if any, into a registration
+func (c *ContestService) registerRequestedUser(
+	ctx context.Context,
+	qtx *database.Queries,
+	contestID uuid.UUID,
+	userID uuid.UUID,
+) error {
+	err := qtx.DeleteRegistrationRequest(
+		ctx,
+		database.DeleteRegistrationRequestParams{
+			ContestID: contestID,
+			UserID:    userID,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf(
+				"cannot delete registration request of user %v in contest %v",
+				userID,
+				contestID,
+			),
+		)
+		return err
+	}
+
+	_, err = qtx.RegisterUserToContest(
+		ctx,
+		database.RegisterUserToContestParams{
+			ContestID: contestID,
+			UserID:    userID,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot register user %v to contest %v", userID, contestID),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func dbRegistrationSettingsToServiceSettings(
+	dbSettings database.ContestRegistrationSetting,
+) RegistrationSettings {
+	return RegistrationSettings{
+		ContestID:         dbSettings.ContestID,
+		RegistrationStart: dbSettings.RegistrationStart.UTC(),
+		RegistrationEnd:   dbSettings.RegistrationEnd.UTC(),
+		Capacity:          dbSettings.Capacity,
+		RequiresApproval:  dbSettings.RequiresApproval,
+	}
+}
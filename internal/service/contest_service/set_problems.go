@@ -43,6 +43,12 @@ func (c *ContestService) SetProblemsInContest(
 		return err
 	}
 
+	// problems before the change
+	prevProblems, err := c.getClonedProblems(ctx, contest, nil)
+	if err != nil {
+		return err
+	}
+
 	// create a new transaction query tool if its nil
 	tx, err := service.GetNewTransaction(ctx)
 	if err != nil {
@@ -72,6 +78,18 @@ func (c *ContestService) SetProblemsInContest(
 		return err
 	}
 
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionContestSetProblems,
+		EntityType: auditEntityContest,
+		EntityID:   contestID,
+		Before:     prevProblems,
+		After:      problems,
+	})
+	if err != nil {
+		return err
+	}
+
 	// commit the transaction
 	if err = tx.Commit(ctx); err != nil {
 		err = fmt.Errorf(
@@ -0,0 +1,621 @@
+package contest_service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// SetContestTeamSettings turns the contest into a team contest
+func (c *ContestService) SetContestTeamSettings(
+	ctx context.Context,
+	settings ContestTeamSettings,
+) (ContestTeamSettings, error) {
+	// validate
+	err := service.ValidateInput(settings)
+	if err != nil {
+		return ContestTeamSettings{}, err
+	}
+
+	// get the contest
+	contest, err := c.GetContestByID(ctx, settings.ContestID)
+	if err != nil {
+		return ContestTeamSettings{}, err
+	}
+
+	// authorize
+	err = c.authorizeContestUpdate(ctx, contest)
+	if err != nil {
+		return ContestTeamSettings{}, err
+	}
+
+	// teams already registered must fit in the new size
+	teams, err := c.getContestTeams(ctx, settings.ContestID)
+	if err != nil {
+		return ContestTeamSettings{}, err
+	}
+	for _, team := range teams {
+		if len(team.members) > int(settings.MaxTeamSize) {
+			return ContestTeamSettings{}, fmt.Errorf(
+				"%w, team %s registered in the contest has more than %v members",
+				flux_errors.ErrInvalidRequest,
+				team.name,
+				settings.MaxTeamSize,
+			)
+		}
+	}
+
+	// settings before the change
+	prevSettings, err := c.findContestTeamSettings(ctx, settings.ContestID)
+	if err != nil {
+		return ContestTeamSettings{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return ContestTeamSettings{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	dbSettings, err := qtx.SetContestTeamSettings(
+		ctx,
+		database.SetContestTeamSettingsParams{
+			ContestID:   settings.ContestID,
+			MaxTeamSize: settings.MaxTeamSize,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot set team settings of contest %v", settings.ContestID),
+		)
+		return ContestTeamSettings{}, err
+	}
+	newSettings := ContestTeamSettings{
+		ContestID:   dbSettings.ContestID,
+		MaxTeamSize: dbSettings.MaxTeamSize,
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionContestSetTeamSettings,
+		EntityType: auditEntityContest,
+		EntityID:   settings.ContestID,
+		Before:     prevSettings,
+		After:      newSettings,
+	})
+	if err != nil {
+		return ContestTeamSettings{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return ContestTeamSettings{}, fmt.Errorf(
+			"%w, cannot commit transaction after setting team settings of contest %v, %w",
+			flux_errors.ErrInternal,
+			settings.ContestID,
+			err,
+		)
+	}
+
+	return newSettings, nil
+}
+
+func (c *ContestService) GetContestTeamSettings(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (ContestTeamSettings, error) {
+	dbSettings, err := c.DB.GetContestTeamSettings(ctx, contestID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch team settings of contest %v", contestID),
+		)
+		return ContestTeamSettings{}, err
+	}
+
+	return ContestTeamSettings{
+		ContestID:   dbSettings.ContestID,
+		MaxTeamSize: dbSettings.MaxTeamSize,
+	}, nil
+}
+
+// DeleteContestTeamSettings turns the contest back into an individual contest.
+// Teams registered in the contest are removed.
+func (c *ContestService) DeleteContestTeamSettings(
+	ctx context.Context,
+	contestID uuid.UUID,
+) error {
+	// get the contest
+	contest, err := c.GetContestByID(ctx, contestID)
+	if err != nil {
+		return err
+	}
+
+	// authorize
+	err = c.authorizeContestUpdate(ctx, contest)
+	if err != nil {
+		return err
+	}
+
+	// settings before the change
+	prevSettings, err := c.findContestTeamSettings(ctx, contestID)
+	if err != nil {
+		return err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	err = qtx.DeleteContestTeams(ctx, contestID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot delete teams of contest %v", contestID),
+		)
+		return err
+	}
+
+	err = qtx.DeleteContestTeamSettings(ctx, contestID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot delete team settings of contest %v", contestID),
+		)
+		return err
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionContestDeleteTeamSettings,
+		EntityType: auditEntityContest,
+		EntityID:   contestID,
+		Before:     prevSettings,
+	})
+	if err != nil {
+		return err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after deleting team settings of contest %v, %w",
+			flux_errors.ErrInternal,
+			contestID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// RegisterTeamToContest registers the team with its current members into a team
+// contest. Members must be able to take part in the contest on their own.
+func (c *ContestService) RegisterTeamToContest(
+	ctx context.Context,
+	contestID uuid.UUID,
+	teamID uuid.UUID,
+) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// get the contest and its team settings
+	contest, dbSettings, err := c.getTeamContestBeforeStart(ctx, contestID)
+	if err != nil {
+		return err
+	}
+
+	// get the team
+	team, err := c.TeamServiceConfig.GetTeamByID(ctx, teamID)
+	if err != nil {
+		return err
+	}
+
+	// only the captain can register the team
+	if team.CaptainID != claims.UserId {
+		log.Warnf(
+			"user %s tried to register team %v into contest %v",
+			claims.UserName,
+			teamID,
+			contestID,
+		)
+		return flux_errors.ErrUnAuthorized
+	}
+
+	// team must fit the contest
+	if len(team.Members) > int(dbSettings.MaxTeamSize) {
+		return fmt.Errorf(
+			"%w, team can have atmost %v members in this contest",
+			flux_errors.ErrInvalidRequest,
+			dbSettings.MaxTeamSize,
+		)
+	}
+
+	// team must not be registered already
+	registered, err := c.DB.IsTeamRegisteredInContest(
+		ctx,
+		database.IsTeamRegisteredInContestParams{
+			ContestID: contestID,
+			TeamID:    teamID,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot check if team %v is registered in contest %v", teamID, contestID),
+		)
+		return err
+	}
+	if registered {
+		return fmt.Errorf(
+			"%w, team is already registered in this contest",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// members of contests that need registration must be registered
+	if !(contest.LockId != nil && contest.IsPublished) {
+		for _, member := range team.Members {
+			isRegistered, err := c.DB.IsUserRegisteredInContest(
+				ctx,
+				database.IsUserRegisteredInContestParams{
+					ContestID: contestID,
+					UserID:    member.UserID,
+				},
+			)
+			if err != nil {
+				err = flux_errors.HandleDBErrors(
+					err,
+					errMsgs,
+					fmt.Sprintf(
+						"cannot check if user %s is registered in contest %v",
+						member.UserName,
+						contestID,
+					),
+				)
+				return err
+			}
+			if !isRegistered {
+				return fmt.Errorf(
+					"%w, member %s is not registered in the contest",
+					flux_errors.ErrInvalidRequest,
+					member.UserName,
+				)
+			}
+		}
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	// capture the current members
+	for _, member := range team.Members {
+		err = qtx.AddContestTeamMember(
+			ctx,
+			database.AddContestTeamMemberParams{
+				ContestID: contestID,
+				TeamID:    teamID,
+				UserID:    member.UserID,
+			},
+		)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf(
+					"cannot add member %s of team %v to contest %v",
+					member.UserName,
+					teamID,
+					contestID,
+				),
+			)
+			return err
+		}
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after registering team %v in contest %v, %w",
+			flux_errors.ErrInternal,
+			teamID,
+			contestID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+func (c *ContestService) UnregisterTeamFromContest(
+	ctx context.Context,
+	contestID uuid.UUID,
+	teamID uuid.UUID,
+) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// get the contest
+	_, _, err = c.getTeamContestBeforeStart(ctx, contestID)
+	if err != nil {
+		return err
+	}
+
+	// get the team
+	team, err := c.TeamServiceConfig.GetTeamByID(ctx, teamID)
+	if err != nil {
+		return err
+	}
+
+	// only the captain can unregister the team
+	if team.CaptainID != claims.UserId {
+		log.Warnf(
+			"user %s tried to unregister team %v from contest %v",
+			claims.UserName,
+			teamID,
+			contestID,
+		)
+		return flux_errors.ErrUnAuthorized
+	}
+
+	err = c.DB.DeleteContestTeam(
+		ctx,
+		database.DeleteContestTeamParams{
+			ContestID: contestID,
+			TeamID:    teamID,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot unregister team %v from contest %v", teamID, contestID),
+		)
+		return err
+	}
+
+	return nil
+}
+
+func (c *ContestService) GetContestTeams(
+	ctx context.Context,
+	contestID uuid.UUID,
+) ([]ContestTeam, error) {
+	teams, err := c.getContestTeams(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]ContestTeam, 0, len(teams))
+	for _, team := range teams {
+		res = append(res, ContestTeam{
+			TeamID:    team.id,
+			Name:      team.name,
+			MemberIDs: team.members,
+		})
+	}
+
+	return res, nil
+}
+
+// getContestTeams returns the teams registered in the contest ordered by their names
+func (c *ContestService) getContestTeams(
+	ctx context.Context,
+	contestID uuid.UUID,
+) ([]*standingsTeam, error) {
+	dbMembers, err := c.DB.GetContestTeamMembers(ctx, contestID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch teams of contest %v", contestID),
+		)
+		return nil, err
+	}
+
+	teams := make([]*standingsTeam, 0)
+	teamsMap := make(map[uuid.UUID]*standingsTeam)
+	for _, dbMember := range dbMembers {
+		team, ok := teamsMap[dbMember.TeamID]
+		if !ok {
+			team = &standingsTeam{id: dbMember.TeamID, name: dbMember.Name}
+			teamsMap[dbMember.TeamID] = team
+			teams = append(teams, team)
+		}
+		team.members = append(team.members, dbMember.UserID)
+	}
+
+	return teams, nil
+}
+
+// getUserTeams maps every member of a team contest to their team. It returns nil
+// if the contest is an individual contest.
+func (c *ContestService) getUserTeams(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (map[uuid.UUID]*standingsTeam, error) {
+	isTeamContest, err := c.isTeamContest(ctx, contestID)
+	if err != nil || !isTeamContest {
+		return nil, err
+	}
+
+	teams, err := c.getContestTeams(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+
+	userTeams := make(map[uuid.UUID]*standingsTeam)
+	for _, team := range teams {
+		for _, member := range team.members {
+			userTeams[member] = team
+		}
+	}
+
+	return userTeams, nil
+}
+
+func (c *ContestService) isTeamContest(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (bool, error) {
+	settings, err := c.findContestTeamSettings(ctx, contestID)
+	if err != nil {
+		return false, err
+	}
+	return settings != nil, nil
+}
+
+// findContestTeamSettings returns the team settings of the contest, nil if it
+// is not a team contest
+func (c *ContestService) findContestTeamSettings(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (*ContestTeamSettings, error) {
+	dbSettings, err := c.DB.GetContestTeamSettings(ctx, contestID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch team settings of contest %v", contestID),
+		)
+		return nil, err
+	}
+
+	return &ContestTeamSettings{
+		ContestID:   dbSettings.ContestID,
+		MaxTeamSize: dbSettings.MaxTeamSize,
+	}, nil
+}
+
+func (c *ContestService) authorizeTeamMember(
+	ctx context.Context,
+	contestID uuid.UUID,
+) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	inTeam, err := c.DB.IsUserInContestTeam(
+		ctx,
+		database.IsUserInContestTeamParams{
+			ContestID: contestID,
+			UserID:    claims.UserId,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot check if user %s is in a team of contest %v", claims.UserName, contestID),
+		)
+		return err
+	}
+	if !inTeam {
+		return fmt.Errorf(
+			"%w, you must be a part of a registered team to take part in this contest",
+			flux_errors.ErrUnAuthorized,
+		)
+	}
+
+	return nil
+}
+
+func (c *ContestService) getTeamContestBeforeStart(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (Contest, database.ContestTeamSetting, error) {
+	// get the contest
+	contest, err := c.GetContestByID(ctx, contestID)
+	if err != nil {
+		return Contest{}, database.ContestTeamSetting{}, err
+	}
+
+	// contest must be a team contest
+	dbSettings, err := c.DB.GetContestTeamSettings(ctx, contestID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Contest{}, database.ContestTeamSetting{}, fmt.Errorf(
+				"%w, contest is not a team contest",
+				flux_errors.ErrInvalidRequest,
+			)
+		}
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch team settings of contest %v", contestID),
+		)
+		return Contest{}, database.ContestTeamSetting{}, err
+	}
+
+	// contest start time must not be nil
+	if contest.StartTime == nil {
+		err = fmt.Errorf(
+			"%w, contest %v has start time as nil, cannot change its teams",
+			flux_errors.ErrInternal,
+			contestID,
+		)
+		log.Error(err)
+		return Contest{}, database.ContestTeamSetting{}, err
+	}
+
+	// teams cannot change once the contest starts
+	if time.Now().After(*contest.StartTime) {
+		return Contest{}, database.ContestTeamSetting{}, fmt.Errorf(
+			"%w, teams cannot be changed once the contest has started",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	return contest, dbSettings, nil
+}
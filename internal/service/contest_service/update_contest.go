@@ -6,6 +6,7 @@ import (
 
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
 )
 
 func (c *ContestService) UpdateContest(
@@ -27,7 +28,7 @@ func (c *ContestService) UpdateContest(
 	}
 
 	// authorize
-	if err = c.authorizeContestUpdate(ctx, contest); err != nil {
+	if err = c.authorizeContestUpdate(ctx, prevContest); err != nil {
 		return Contest{}, err
 	}
 
@@ -36,13 +37,26 @@ func (c *ContestService) UpdateContest(
 		return Contest{}, err
 	}
 
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return Contest{}, err
+	}
+
+	// if anything goes wrong roll back transaction
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
 	// update the contest
-	dbContest, err := c.DB.UpdateContest(
+	dbContest, err := qtx.UpdateContest(
 		ctx,
 		database.UpdateContestParams{
 			Title:     contest.Title,
 			StartTime: contest.StartTime,
 			EndTime:   contest.EndTime,
+			ID:        prevContest.ID,
 		},
 	)
 	if err != nil {
@@ -56,12 +70,35 @@ func (c *ContestService) UpdateContest(
 
 	// add support to return the contest in case
 	//  we might allow updating public contests
-	return Contest{
+	updatedContest := Contest{
 		Title:       dbContest.Title,
 		ID:          dbContest.ID,
 		StartTime:   contest.StartTime,
 		EndTime:     dbContest.EndTime,
 		CreatedBy:   dbContest.CreatedBy,
 		IsPublished: dbContest.IsPublished,
-	}, nil
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionContestUpdate,
+		EntityType: auditEntityContest,
+		EntityID:   prevContest.ID,
+		Before:     prevContest,
+		After:      updatedContest,
+	})
+	if err != nil {
+		return Contest{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Contest{}, fmt.Errorf(
+			"%w, cannot commit transaction after updating contest %v, %w",
+			flux_errors.ErrInternal,
+			prevContest.ID,
+			err,
+		)
+	}
+
+	return updatedContest, nil
 }
@@ -0,0 +1,201 @@
+package contest_service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+func (c *ContestService) StartVirtualParticipation(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (VirtualParticipation, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return VirtualParticipation{}, err
+	}
+
+	// get the contest
+	contest, err := c.GetContestByID(ctx, contestID)
+	if err != nil {
+		return VirtualParticipation{}, err
+	}
+
+	// contest start time must not be nil
+	if contest.StartTime == nil {
+		err = fmt.Errorf(
+			"%w, contest %v has start time as nil, cannot start virtual participation",
+			flux_errors.ErrInternal,
+			contestID,
+		)
+		log.Error(err)
+		return VirtualParticipation{}, err
+	}
+
+	// only ended contests can be replayed
+	if !time.Now().After(contest.EndTime) {
+		return VirtualParticipation{}, fmt.Errorf(
+			"%w, virtual participation is allowed only after the contest has ended",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// replayed by those who could take part in the real contest
+	err = c.authorizeContestParticipant(ctx, contest)
+	if err != nil {
+		log.Warnf(
+			"user %s tried to virtually participate in contest %v without being eligible",
+			claims.UserName,
+			contestID,
+		)
+		return VirtualParticipation{}, err
+	}
+
+	// users who took part in the real contest already know the problems
+	participated, err := c.DB.HasUserParticipatedInContest(
+		ctx,
+		database.HasUserParticipatedInContestParams{
+			ContestID:   &contestID,
+			SubmittedBy: claims.UserId,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf(
+				"cannot check if user %s participated in contest %v",
+				claims.UserName,
+				contestID,
+			),
+		)
+		return VirtualParticipation{}, err
+	}
+	if participated {
+		return VirtualParticipation{}, fmt.Errorf(
+			"%w, you have already participated in this contest",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// the personal run has the same duration as the original contest
+	startTime := time.Now().UTC()
+	endTime := startTime.Add(contest.EndTime.Sub(*contest.StartTime))
+
+	dbParticipation, err := c.DB.CreateVirtualParticipation(
+		ctx,
+		database.CreateVirtualParticipationParams{
+			ContestID: contestID,
+			UserID:    claims.UserId,
+			StartTime: startTime,
+			EndTime:   endTime,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf(
+				"cannot create virtual participation of user %s in contest %v",
+				claims.UserName,
+				contestID,
+			),
+		)
+		return VirtualParticipation{}, err
+	}
+
+	return dbVirtualParticipationToServiceVirtualParticipation(dbParticipation), nil
+}
+
+func (c *ContestService) GetVirtualParticipation(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (VirtualParticipation, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return VirtualParticipation{}, err
+	}
+
+	dbParticipation, err := c.DB.GetUserVirtualParticipation(
+		ctx,
+		database.GetUserVirtualParticipationParams{
+			ContestID: contestID,
+			UserID:    claims.UserId,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf(
+				"cannot fetch virtual participation of user %s in contest %v",
+				claims.UserName,
+				contestID,
+			),
+		)
+		return VirtualParticipation{}, err
+	}
+
+	return dbVirtualParticipationToServiceVirtualParticipation(dbParticipation), nil
+}
+
+func (c *ContestService) canSubmitVirtually(
+	ctx context.Context,
+	claims service.UserCredentialClaims,
+	problemID int32,
+	contestID uuid.UUID,
+	virtualParticipationID uuid.UUID,
+) error {
+	// get the participation
+	dbParticipation, err := c.DB.GetVirtualParticipationByID(ctx, virtualParticipationID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch virtual participation with id %v", virtualParticipationID),
+		)
+		return err
+	}
+
+	// participation must belong to the user and the contest
+	if dbParticipation.UserID != claims.UserId || dbParticipation.ContestID != contestID {
+		log.Warnf(
+			"user %s tried to submit to contest %v using virtual participation %v",
+			claims.UserName,
+			contestID,
+			virtualParticipationID,
+		)
+		return flux_errors.ErrUnAuthorized
+	}
+
+	// submission must be within the personal window
+	now := time.Now()
+	if now.Before(dbParticipation.StartTime) || now.After(dbParticipation.EndTime) {
+		return fmt.Errorf(
+			"%w, virtual participation is not running",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	return c.validateContestProblem(ctx, claims, problemID, contestID)
+}
+
+func dbVirtualParticipationToServiceVirtualParticipation(
+	dbParticipation database.VirtualParticipation,
+) VirtualParticipation {
+	return VirtualParticipation{
+		ID:        dbParticipation.ID,
+		ContestID: dbParticipation.ContestID,
+		UserID:    dbParticipation.UserID,
+		StartTime: dbParticipation.StartTime.UTC(),
+		EndTime:   dbParticipation.EndTime.UTC(),
+	}
+}
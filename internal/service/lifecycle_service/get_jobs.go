@@ -0,0 +1,48 @@
+package lifecycle_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// GetContestLifecycleJobs returns the scheduled jobs of the contest along with
+// their status, for the managers to keep an eye on failures
+func (l *LifecycleService) GetContestLifecycleJobs(
+	ctx context.Context,
+	contestID uuid.UUID,
+) ([]LifecycleJob, error) {
+	// authorize
+	err := l.UserServiceConfig.Authorize(ctx, user_service.PermLifecycleView, user_service.Resource{})
+	if err != nil {
+		return nil, err
+	}
+
+	dbJobs, err := l.DB.GetContestLifecycleJobs(ctx, contestID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot get lifecycle jobs of contest %v", contestID),
+		)
+		return nil, err
+	}
+
+	jobs := make([]LifecycleJob, 0, len(dbJobs))
+	for _, dbJob := range dbJobs {
+		jobs = append(jobs, LifecycleJob{
+			ID:        dbJob.ID,
+			ContestID: dbJob.ContestID,
+			Event:     EventType(dbJob.Event),
+			RunAt:     dbJob.RunAt,
+			Status:    string(dbJob.Status),
+			Attempts:  dbJob.Attempts,
+			LastError: dbJob.LastError,
+		})
+	}
+
+	return jobs, nil
+}
@@ -0,0 +1,118 @@
+package lifecycle_service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/email"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+)
+
+// handleContestStart publishes the contest, informs the participants and emits
+// the start event
+func (l *LifecycleService) handleContestStart(ctx context.Context, job database.ContestLifecycleJob) error {
+	contest, err := l.ContestServiceConfig.GetContestByID(ctx, job.ContestID)
+	if err != nil {
+		return err
+	}
+
+	// the start may have been moved, the job is rescheduled by the next sync
+	if contest.StartTime == nil || time.Now().Before(*contest.StartTime) {
+		l.logger.Infof("contest %v has not started yet, skipping stale start job", contest.ID)
+		return nil
+	}
+
+	if !contest.IsPublished {
+		err = l.DB.PublishContest(ctx, contest.ID)
+		if err != nil {
+			return flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot publish contest %v", contest.ID),
+			)
+		}
+	}
+
+	l.mailParticipants(ctx, contest)
+
+	l.emit(ctx, Event{
+		Type:       EventContestStarted,
+		ContestID:  contest.ID,
+		OccurredAt: *contest.StartTime,
+	})
+	return nil
+}
+
+// handleContestEnd emits the end event. Post contest work, like computing the
+// ratings, is done by its subscribers.
+func (l *LifecycleService) handleContestEnd(ctx context.Context, job database.ContestLifecycleJob) error {
+	contest, err := l.ContestServiceConfig.GetContestByID(ctx, job.ContestID)
+	if err != nil {
+		return err
+	}
+
+	// the end may have been moved, the job is rescheduled by the next sync
+	if !time.Now().After(contest.EndTime) {
+		l.logger.Infof("contest %v has not ended yet, skipping stale end job", contest.ID)
+		return nil
+	}
+
+	l.emit(ctx, Event{
+		Type:       EventContestEnded,
+		ContestID:  contest.ID,
+		OccurredAt: contest.EndTime,
+	})
+	return nil
+}
+
+// handleLockTimeout emits the lock expiry event
+func (l *LifecycleService) handleLockTimeout(ctx context.Context, job database.ContestLifecycleJob) error {
+	contest, err := l.ContestServiceConfig.GetContestByID(ctx, job.ContestID)
+	if err != nil {
+		return err
+	}
+
+	// the lock may have been changed or removed
+	if contest.LockTimeout == nil || time.Now().Before(*contest.LockTimeout) {
+		l.logger.Infof("lock of contest %v has not expired, skipping stale lock job", contest.ID)
+		return nil
+	}
+
+	l.emit(ctx, Event{
+		Type:       EventLockExpired,
+		ContestID:  contest.ID,
+		OccurredAt: *contest.LockTimeout,
+	})
+	return nil
+}
+
+// mailParticipants informs the registered users and team members that the
+// contest has started. Mails are sent on a best effort basis.
+func (l *LifecycleService) mailParticipants(ctx context.Context, contest contest_service.Contest) {
+	mails, err := l.DB.GetContestParticipantMailIDs(ctx, contest.ID)
+	if err != nil {
+		l.logger.Errorf("cannot get participant mails of contest %v, %v", contest.ID, err)
+		return
+	}
+
+	subject := fmt.Sprintf("%s has started", contest.Title)
+	body := fmt.Sprintf(
+		"Hello,\n\nThe contest %s has started and will end at %s.\n\nAll the best!",
+		contest.Title,
+		contest.EndTime.Format(time.RFC1123),
+	)
+
+	// one mail per participant, so the addresses are not shared
+	for _, mail := range mails {
+		err = email.NewMail(
+			ctx, subject, body, email.KeyEmailBodyPlain, email.PurposeContestStarted, mail,
+		)
+		if err != nil {
+			l.logger.Errorf("cannot mail participants of contest %v, %v", contest.ID, err)
+			return
+		}
+	}
+}
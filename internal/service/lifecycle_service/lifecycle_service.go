@@ -0,0 +1,143 @@
+package lifecycle_service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+)
+
+// Start recovers the jobs interrupted by a previous shutdown and launches the
+// poller that schedules and runs the due jobs and sends the due reminders
+func (l *LifecycleService) Start(pollInterval time.Duration) {
+	if l.DB == nil {
+		panic("lifecycle service expects non-nil db")
+	}
+	if l.ContestServiceConfig == nil {
+		panic("lifecycle service expects non-nil contest service")
+	}
+
+	l.logger = logrus.WithField("from", "lifecycle service")
+
+	// jobs left running by a crash are run again
+	if err := l.DB.ResetRunningLifecycleJobs(context.Background()); err != nil {
+		l.logger.Errorf("cannot reset interrupted lifecycle jobs, %v", err)
+	}
+
+	go l.pollJobs(pollInterval)
+	l.logger.Info("lifecycle service started polling jobs")
+}
+
+// Subscribe registers a subscriber for an event. Subscribers of an event are
+// called one after another in the order they subscribed.
+func (l *LifecycleService) Subscribe(eventType EventType, subscriber Subscriber) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.subscribers == nil {
+		l.subscribers = make(map[EventType][]Subscriber)
+	}
+	l.subscribers[eventType] = append(l.subscribers[eventType], subscriber)
+}
+
+func (l *LifecycleService) pollJobs(pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx := context.Background()
+
+		// reminders are independent of the jobs
+		if err := l.ContestServiceConfig.SendDueReminders(ctx); err != nil {
+			l.logger.Errorf("cannot send due contest reminders, %v", err)
+		}
+
+		// pick up the contests created or changed since the last poll
+		if err := l.DB.SyncContestLifecycleJobs(ctx); err != nil {
+			l.logger.Errorf("cannot sync lifecycle jobs, %v", err)
+			continue
+		}
+
+		jobs, err := l.DB.ClaimDueLifecycleJobs(ctx, jobBatchSize)
+		if err != nil {
+			l.logger.Errorf("cannot claim due lifecycle jobs, %v", err)
+			continue
+		}
+
+		for _, job := range jobs {
+			l.runJob(ctx, job)
+		}
+	}
+}
+
+func (l *LifecycleService) runJob(parent context.Context, job database.ContestLifecycleJob) {
+	ctx, cancel := context.WithTimeout(parent, jobTimeout)
+	defer cancel()
+
+	jobLogger := l.logger.WithFields(logrus.Fields{
+		"contest_id": job.ContestID,
+		"event":      job.Event,
+		"attempt":    job.Attempts,
+	})
+
+	var err error
+	switch job.Event {
+	case database.LifecycleEventContestStart:
+		err = l.handleContestStart(ctx, job)
+	case database.LifecycleEventContestEnd:
+		err = l.handleContestEnd(ctx, job)
+	case database.LifecycleEventLockTimeout:
+		err = l.handleLockTimeout(ctx, job)
+	default:
+		err = fmt.Errorf("unknown lifecycle event %v", job.Event)
+	}
+
+	if err == nil {
+		if err = l.DB.CompleteLifecycleJob(ctx, job.ID); err != nil {
+			jobLogger.Errorf("cannot mark lifecycle job as done, %v", err)
+			return
+		}
+		jobLogger.Info("lifecycle job done")
+		return
+	}
+
+	// retry with a growing delay until the attempts run out
+	lastError := err.Error()
+	status := database.LifecycleJobStatusPending
+	if job.Attempts >= maxJobAttempts {
+		status = database.LifecycleJobStatusFailed
+	}
+	jobLogger.Errorf("lifecycle job failed, %v", err)
+	err = l.DB.FailLifecycleJob(
+		ctx,
+		database.FailLifecycleJobParams{
+			ID:        job.ID,
+			Status:    status,
+			RunAt:     time.Now().Add(time.Duration(job.Attempts) * retryDelay),
+			LastError: &lastError,
+		},
+	)
+	if err != nil {
+		jobLogger.Errorf("cannot mark lifecycle job as failed, %v", err)
+	}
+}
+
+// emit notifies the subscribers of the event
+func (l *LifecycleService) emit(ctx context.Context, event Event) {
+	l.mu.RLock()
+	subscribers := l.subscribers[event.Type]
+	l.mu.RUnlock()
+
+	for _, subscriber := range subscribers {
+		if err := subscriber(ctx, event); err != nil {
+			l.logger.Errorf(
+				"subscriber of %v event of contest %v failed, %v",
+				event.Type,
+				event.ContestID,
+				err,
+			)
+		}
+	}
+}
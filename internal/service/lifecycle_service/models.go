@@ -0,0 +1,66 @@
+package lifecycle_service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+var (
+	errMsgs = map[string]map[string]string{}
+)
+
+type EventType string
+
+// events emitted once the corresponding job of a contest has run
+const (
+	EventContestStarted EventType = EventType(database.LifecycleEventContestStart)
+	EventContestEnded   EventType = EventType(database.LifecycleEventContestEnd)
+	EventLockExpired    EventType = EventType(database.LifecycleEventLockTimeout)
+)
+
+const (
+	// jobs claimed in a single poll
+	jobBatchSize = 20
+	// a failed job is retried after attempts * retryDelay
+	maxJobAttempts = 5
+	retryDelay     = time.Minute
+	// time given to a single job, including its subscribers
+	jobTimeout = 2 * time.Minute
+)
+
+type Event struct {
+	Type       EventType `json:"type"`
+	ContestID  uuid.UUID `json:"contest_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Subscriber is notified about the events it has subscribed to. Errors of the
+// subscribers are only logged, they do not fail the job.
+type Subscriber func(ctx context.Context, event Event) error
+
+type LifecycleService struct {
+	DB                   *database.Queries
+	ContestServiceConfig *contest_service.ContestService
+	UserServiceConfig    *user_service.UserService
+
+	subscribers map[EventType][]Subscriber
+	mu          sync.RWMutex
+	logger      *logrus.Entry
+}
+
+type LifecycleJob struct {
+	ID        uuid.UUID `json:"job_id"`
+	ContestID uuid.UUID `json:"contest_id"`
+	Event     EventType `json:"event"`
+	RunAt     time.Time `json:"run_at"`
+	Status    string    `json:"status"`
+	Attempts  int32     `json:"attempts"`
+	LastError *string   `json:"last_error"`
+}
@@ -0,0 +1,386 @@
+package lock_service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// IsOpenAt reports if the lock is open at the given time. A timer lock opens
+// after its timeout, a manual lock once released and a composite lock when
+// all (and) or any (or) of its conditions hold.
+func (c LockCondition) IsOpenAt(at time.Time) bool {
+	switch c.Type {
+	case database.LockTypeTimer:
+		return c.Timeout != nil && at.After(*c.Timeout)
+	case database.LockTypeManual:
+		return c.ReleasedAt != nil && !at.Before(*c.ReleasedAt)
+	case database.LockTypeComposite:
+		conditions := make([]bool, 0, len(c.Children)+len(c.ContestEndTimes))
+		for _, child := range c.Children {
+			conditions = append(conditions, child.IsOpenAt(at))
+		}
+		for _, endTime := range c.ContestEndTimes {
+			conditions = append(conditions, at.After(endTime))
+		}
+		if len(conditions) == 0 {
+			return false
+		}
+		for _, open := range conditions {
+			if c.Operator == OperatorOr && open {
+				return true
+			}
+			if c.Operator != OperatorOr && !open {
+				return false
+			}
+		}
+		return c.Operator != OperatorOr
+	}
+	return false
+}
+
+// IsLockOpen evaluates the lock for everyone, irrespective of the role of the
+// user. It does not need claims so it can be used internally.
+func (l *LockService) IsLockOpen(ctx context.Context, lockID uuid.UUID) (bool, error) {
+	condition, err := l.GetLockCondition(ctx, lockID)
+	if err != nil {
+		return false, err
+	}
+
+	return condition.IsOpenAt(time.Now()), nil
+}
+
+// GetLockCondition loads the condition of the lock, so that it can be
+// evaluated at any time. It does not need claims so it can be used internally.
+func (l *LockService) GetLockCondition(ctx context.Context, lockID uuid.UUID) (LockCondition, error) {
+	dbLock, err := l.DB.GetLockById(ctx, lockID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot get lock with id %v from db", lockID),
+		)
+		return LockCondition{}, err
+	}
+
+	return l.getLockCondition(ctx, dbLock, 0)
+}
+
+// ReleaseLock opens a manual lock for everyone
+func (l *LockService) ReleaseLock(ctx context.Context, lockID uuid.UUID) (FluxLock, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// get the lock
+	lock, err := l.GetLockById(ctx, lockID)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// authorize
+	err = l.UserServiceConfig.Authorize(
+		ctx,
+		user_service.PermLockEditAny,
+		lockResource(lock),
+	)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	if lock.Type != database.LockTypeManual {
+		return FluxLock{}, fmt.Errorf(
+			"%w, only manual locks can be released",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+	if lock.ReleasedAt != nil {
+		return FluxLock{}, fmt.Errorf(
+			"%w, lock is already released",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := l.DB.WithTx(tx)
+
+	dbLock, err := qtx.ReleaseLock(ctx, lock.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot release lock with id %v", lock.ID),
+		)
+		return FluxLock{}, err
+	}
+	res := dbLockToServiceLock(dbLock)
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionLockRelease,
+		EntityType: auditEntityLock,
+		EntityID:   lock.ID,
+		Before:     lock,
+		After:      res,
+	})
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return FluxLock{}, fmt.Errorf(
+			"%w, cannot commit transaction after releasing lock %v, %w",
+			flux_errors.ErrInternal,
+			lock.ID,
+			err,
+		)
+	}
+
+	log.Infof("user %s released lock %v", claims.UserName, lock.ID)
+	return res, nil
+}
+
+// createCompositeLock creates the lock with its child locks and contest
+// dependencies. Children must already exist, so no cycle can be formed.
+func (l *LockService) createCompositeLock(
+	ctx context.Context,
+	lock FluxLock,
+	createdBy uuid.UUID,
+) (FluxLock, error) {
+	// children must be visible to the user
+	for _, childID := range lock.ChildLockIDs {
+		child, err := l.GetLockById(ctx, childID)
+		if err != nil {
+			return FluxLock{}, err
+		}
+
+		// keep the nesting bounded
+		dbChild, err := l.DB.GetLockById(ctx, child.ID)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot get lock with id %v from db", child.ID),
+			)
+			return FluxLock{}, err
+		}
+		if _, err = l.getLockCondition(ctx, dbChild, 1); err != nil {
+			return FluxLock{}, err
+		}
+	}
+
+	// contests must exist
+	if len(lock.AfterContestIDs) > 0 {
+		contests, err := l.DB.GetContestEndTimes(ctx, lock.AfterContestIDs)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				"cannot fetch contests of composite lock",
+			)
+			return FluxLock{}, err
+		}
+		if len(contests) != len(lock.AfterContestIDs) {
+			return FluxLock{}, fmt.Errorf(
+				"%w, some of the contest ids are invalid",
+				flux_errors.ErrInvalidRequest,
+			)
+		}
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := l.DB.WithTx(tx)
+
+	dbLock, err := qtx.CreateLock(ctx, database.CreateLockParams{
+		LockType:    lock.Type,
+		Name:        lock.Name,
+		CreatedBy:   createdBy,
+		Description: lock.Description,
+		Operator:    lock.Operator,
+	})
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			"cannot create composite lock",
+		)
+		return FluxLock{}, err
+	}
+
+	for _, childID := range lock.ChildLockIDs {
+		err = qtx.AddLockChild(ctx, database.AddLockChildParams{
+			ParentID: dbLock.ID,
+			ChildID:  childID,
+		})
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot add child lock %v to lock %v", childID, dbLock.ID),
+			)
+			return FluxLock{}, err
+		}
+	}
+	for _, contestID := range lock.AfterContestIDs {
+		err = qtx.AddLockContestDependency(ctx, database.AddLockContestDependencyParams{
+			LockID:    dbLock.ID,
+			ContestID: contestID,
+		})
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot add contest %v to lock %v", contestID, dbLock.ID),
+			)
+			return FluxLock{}, err
+		}
+	}
+
+	res := dbLockToServiceLock(dbLock)
+	res.ChildLockIDs = lock.ChildLockIDs
+	res.AfterContestIDs = lock.AfterContestIDs
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionLockCreate,
+		EntityType: auditEntityLock,
+		EntityID:   res.ID,
+		After:      res,
+	})
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return FluxLock{}, fmt.Errorf(
+			"%w, cannot commit transaction after creating composite lock, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+	}
+
+	return res, nil
+}
+
+// getLockCondition loads the condition of the lock with the ones of its
+// children, depth is the nesting of the lock in its root
+func (l *LockService) getLockCondition(
+	ctx context.Context,
+	dbLock database.Lock,
+	depth int,
+) (LockCondition, error) {
+	condition := LockCondition{
+		Type:       dbLock.LockType,
+		Timeout:    dbLock.Timeout,
+		ReleasedAt: dbLock.ReleasedAt,
+	}
+	if dbLock.LockType != database.LockTypeComposite {
+		return condition, nil
+	}
+
+	if depth >= maxLockDepth {
+		return LockCondition{}, fmt.Errorf(
+			"%w, lock %v is nested deeper than %d locks",
+			flux_errors.ErrInvalidRequest,
+			dbLock.ID,
+			maxLockDepth,
+		)
+	}
+	if dbLock.Operator != nil {
+		condition.Operator = *dbLock.Operator
+	}
+
+	children, err := l.DB.GetLockChildren(ctx, dbLock.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch children of lock %v", dbLock.ID),
+		)
+		return LockCondition{}, err
+	}
+	for _, child := range children {
+		childCondition, err := l.getLockCondition(ctx, child, depth+1)
+		if err != nil {
+			return LockCondition{}, err
+		}
+		condition.Children = append(condition.Children, childCondition)
+	}
+
+	contests, err := l.DB.GetLockContestDependencies(ctx, dbLock.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch contests of lock %v", dbLock.ID),
+		)
+		return LockCondition{}, err
+	}
+	for _, contest := range contests {
+		condition.ContestEndTimes = append(condition.ContestEndTimes, contest.EndTime)
+	}
+
+	return condition, nil
+}
+
+// fillCompositeDetails sets the child locks and contests of a composite lock
+func (l *LockService) fillCompositeDetails(ctx context.Context, lock *FluxLock) error {
+	if lock.Type != database.LockTypeComposite {
+		return nil
+	}
+
+	children, err := l.DB.GetLockChildren(ctx, lock.ID)
+	if err != nil {
+		return flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch children of lock %v", lock.ID),
+		)
+	}
+	lock.ChildLockIDs = make([]uuid.UUID, 0, len(children))
+	for _, child := range children {
+		lock.ChildLockIDs = append(lock.ChildLockIDs, child.ID)
+	}
+
+	contests, err := l.DB.GetLockContestDependencies(ctx, lock.ID)
+	if err != nil {
+		return flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch contests of lock %v", lock.ID),
+		)
+	}
+	lock.AfterContestIDs = make([]uuid.UUID, 0, len(contests))
+	for _, contest := range contests {
+		lock.AfterContestIDs = append(lock.AfterContestIDs, contest.ID)
+	}
+
+	return nil
+}
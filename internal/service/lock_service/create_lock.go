@@ -21,11 +21,7 @@ func (l *LockService) CreateLock(
 	}
 
 	// authorize user
-	err = l.UserServiceConfig.AuthorizeUserRole(
-		ctx,
-		user_service.RoleManager,
-		fmt.Sprintf("user %s tried to create a lock", claims.UserName),
-	)
+	err = l.UserServiceConfig.Authorize(ctx, user_service.PermLockCreate, user_service.Resource{})
 	if err != nil {
 		return FluxLock{}, err
 	}
@@ -35,8 +31,25 @@ func (l *LockService) CreateLock(
 		return FluxLock{}, err
 	}
 
+	// composite locks are created along with their conditions
+	if lock.Type == database.LockTypeComposite {
+		return l.createCompositeLock(ctx, lock, claims.UserId)
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := l.DB.WithTx(tx)
+
 	// create the lock
-	dbLock, err := l.DB.CreateLock(ctx, database.CreateLockParams{
+	dbLock, err := qtx.CreateLock(ctx, database.CreateLockParams{
 		Timeout:     lock.Timeout,
 		LockType:    lock.Type,
 		Name:        lock.Name,
@@ -44,13 +57,33 @@ func (l *LockService) CreateLock(
 		Description: lock.Description,
 	})
 	if err != nil {
-		flux_errors.HandleDBErrors(
+		err = flux_errors.HandleDBErrors(
 			err,
 			errMsgs,
 			"cannot create lock",
 		)
 		return FluxLock{}, err
 	}
+	res := dbLockToServiceLock(dbLock)
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionLockCreate,
+		EntityType: auditEntityLock,
+		EntityID:   res.ID,
+		After:      res,
+	})
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return FluxLock{}, fmt.Errorf(
+			"%w, cannot commit transaction after creating lock, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+	}
 
-	return dbLockToServiceLock(dbLock), nil
+	return res, nil
 }
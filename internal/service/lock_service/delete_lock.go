@@ -8,15 +8,10 @@ import (
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 func (l *LockService) DeleteLock(ctx context.Context, lockId uuid.UUID) error {
-	// get claims
-	claims, err := service.GetClaimsFromContext(ctx)
-	if err != nil {
-		return err
-	}
-
 	// get the lock
 	lock, err := l.GetLockById(ctx, lockId)
 	if err != nil {
@@ -24,14 +19,10 @@ func (l *LockService) DeleteLock(ctx context.Context, lockId uuid.UUID) error {
 	}
 
 	// authorize
-	err = l.UserServiceConfig.AuthorizeCreatorAccess(
+	err = l.UserServiceConfig.Authorize(
 		ctx,
-		lock.CreatedBy,
-		fmt.Sprintf(
-			"user %s tried to delete lock with id %v",
-			claims.UserName,
-			lock.ID,
-		),
+		user_service.PermLockEditAny,
+		lockResource(lock),
 	)
 	if err != nil {
 		return err
@@ -44,7 +35,19 @@ func (l *LockService) DeleteLock(ctx context.Context, lockId uuid.UUID) error {
 			flux_errors.ErrInvalidRequest,
 		)
 	}
-	err = l.DB.DeleteLockById(ctx, lockId)
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := l.DB.WithTx(tx)
+
+	err = qtx.DeleteLockById(ctx, lockId)
 	if err != nil {
 		err = flux_errors.HandleDBErrors(
 			err,
@@ -54,5 +57,25 @@ func (l *LockService) DeleteLock(ctx context.Context, lockId uuid.UUID) error {
 		return err
 	}
 
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionLockDelete,
+		EntityType: auditEntityLock,
+		EntityID:   lock.ID,
+		Before:     lock,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf(
+			"%w, cannot commit transaction after deleting lock %v, %w",
+			flux_errors.ErrInternal,
+			lock.ID,
+			err,
+		)
+	}
+
 	return nil
 }
@@ -17,12 +17,6 @@ func (l *LockService) GetLockById(
 	ctx context.Context,
 	id uuid.UUID,
 ) (res FluxLock, err error) {
-	// get the user details from claims
-	claims, err := service.GetClaimsFromContext(ctx)
-	if err != nil {
-		return
-	}
-
 	// get lock from db
 	dbLock, err := l.DB.GetLockById(ctx, id)
 	if err != nil {
@@ -35,14 +29,11 @@ func (l *LockService) GetLockById(
 	}
 
 	// authorize
-	if err = l.UserServiceConfig.AuthorizeUserRole(
+	res = dbLockToServiceLock(dbLock)
+	if err = l.UserServiceConfig.Authorize(
 		ctx,
-		dbLock.Access,
-		fmt.Sprintf(
-			"user %s tried to get lock with id %v",
-			claims.UserName,
-			dbLock.ID,
-		),
+		user_service.PermLockView,
+		lockResource(res),
 	); err != nil {
 		if errors.Is(err, flux_errors.ErrUnAuthorized) {
 			return FluxLock{}, fmt.Errorf(
@@ -53,7 +44,11 @@ func (l *LockService) GetLockById(
 		return FluxLock{}, err
 	}
 
-	return dbLockToServiceLock(dbLock), nil
+	if err = l.fillCompositeDetails(ctx, &res); err != nil {
+		return FluxLock{}, err
+	}
+
+	return res, nil
 }
 
 func (l *LockService) GetLocksByFilters(
@@ -66,22 +61,9 @@ func (l *LockService) GetLocksByFilters(
 		return nil, err
 	}
 
-	// get the user details from claims
-	claims, err := service.GetClaimsFromContext(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	// authorize
 	// only manager or above can view locks
-	err = l.UserServiceConfig.AuthorizeUserRole(
-		ctx,
-		user_service.RoleManager,
-		fmt.Sprintf(
-			"user %s tried to view lock with filters",
-			claims.UserName,
-		),
-	)
+	err = l.UserServiceConfig.Authorize(ctx, user_service.PermLockSearch, user_service.Resource{})
 	if err != nil {
 		return nil, err
 	}
@@ -126,14 +108,22 @@ func (l *LockService) GetLocksByFilters(
 	// convert the locks to service locks
 	locks := make([]FluxLock, 0, len(dbLocks))
 	for _, dbLock := range dbLocks {
-		if err = l.UserServiceConfig.AuthorizeUserRole(
+		lock := dbLockToServiceLock(dbLock)
+		allowed, err := l.UserServiceConfig.IsAllowed(
 			ctx,
-			dbLock.Access,
-			"",
-		); err != nil {
+			user_service.PermLockView,
+			lockResource(lock),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
 			continue
 		}
-		locks = append(locks, dbLockToServiceLock(dbLock))
+		if err = l.fillCompositeDetails(ctx, &lock); err != nil {
+			return nil, err
+		}
+		locks = append(locks, lock)
 	}
 
 	return locks, nil
@@ -0,0 +1,404 @@
+package lock_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// GrantAllows reports if any of the held permissions covers the required
+// one. An edit grant also covers view.
+func GrantAllows(
+	held []database.LockGrantPermission,
+	required database.LockGrantPermission,
+) bool {
+	for _, permission := range held {
+		if permission == required || permission == database.LockGrantPermissionEdit {
+			return true
+		}
+	}
+	return false
+}
+
+// SetLockGrant grants the user or the team the given permission on the lock.
+// Only the creator of the lock can manage its grants.
+func (l *LockService) SetLockGrant(
+	ctx context.Context,
+	request SetLockGrantRequest,
+) (LockGrant, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return LockGrant{}, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return LockGrant{}, err
+	}
+	if (request.UserName == nil) == (request.TeamID == nil) {
+		return LockGrant{}, fmt.Errorf(
+			"%w, exactly one of user_name or team_id must be provided",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// get the lock
+	lock, err := l.GetLockById(ctx, request.LockID)
+	if err != nil {
+		return LockGrant{}, err
+	}
+
+	// authorize
+	err = l.UserServiceConfig.Authorize(
+		ctx,
+		user_service.PermLockEditAny,
+		lockResource(lock),
+	)
+	if err != nil {
+		return LockGrant{}, err
+	}
+
+	// resolve the grantee
+	var userID, teamID *uuid.UUID
+	if request.UserName != nil {
+		user, err := l.UserServiceConfig.FetchUserByUserName(ctx, *request.UserName)
+		if err != nil {
+			return LockGrant{}, err
+		}
+		userID = &user.UserID
+	} else {
+		team, err := l.DB.GetTeamByID(ctx, *request.TeamID)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot get team with id %v from db", *request.TeamID),
+			)
+			return LockGrant{}, err
+		}
+		teamID = &team.ID
+	}
+
+	// replace the permission of an existing grant
+	grants, err := l.GetLockGrants(ctx, lock.ID)
+	if err != nil {
+		return LockGrant{}, err
+	}
+	var prevGrant *LockGrant
+	for _, grant := range grants {
+		if sameGrantee(grant, userID, teamID) {
+			prevGrant = &grant
+			break
+		}
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return LockGrant{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := l.DB.WithTx(tx)
+
+	var grant LockGrant
+	if prevGrant != nil {
+		dbGrant, err := qtx.UpdateLockGrantPermission(
+			ctx,
+			database.UpdateLockGrantPermissionParams{
+				ID:         prevGrant.ID,
+				Permission: request.Permission,
+			},
+		)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot update lock grant with id %v", prevGrant.ID),
+			)
+			return LockGrant{}, err
+		}
+		grant = *prevGrant
+		grant.Permission = dbGrant.Permission
+	} else {
+		dbGrant, err := qtx.CreateLockGrant(
+			ctx,
+			database.CreateLockGrantParams{
+				LockID:     lock.ID,
+				UserID:     userID,
+				TeamID:     teamID,
+				Permission: request.Permission,
+				GrantedBy:  claims.UserId,
+			},
+		)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot grant access to lock with id %v", lock.ID),
+			)
+			return LockGrant{}, err
+		}
+		grant = LockGrant{
+			ID:         dbGrant.ID,
+			LockID:     dbGrant.LockID,
+			UserID:     dbGrant.UserID,
+			UserName:   request.UserName,
+			TeamID:     dbGrant.TeamID,
+			Permission: dbGrant.Permission,
+			GrantedBy:  dbGrant.GrantedBy,
+			CreatedAt:  dbGrant.CreatedAt,
+		}
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionLockGrant,
+		EntityType: auditEntityLock,
+		EntityID:   lock.ID,
+		Before:     prevGrant,
+		After:      grant,
+	})
+	if err != nil {
+		return LockGrant{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return LockGrant{}, fmt.Errorf(
+			"%w, cannot commit transaction after granting access to lock %v, %w",
+			flux_errors.ErrInternal,
+			lock.ID,
+			err,
+		)
+	}
+
+	log.Infof(
+		"user %s granted %s access on lock %v",
+		claims.UserName,
+		request.Permission,
+		lock.ID,
+	)
+	return grant, nil
+}
+
+// GetLockGrants lists the grants of the lock to its creator
+func (l *LockService) GetLockGrants(
+	ctx context.Context,
+	lockID uuid.UUID,
+) ([]LockGrant, error) {
+	// get the lock
+	lock, err := l.GetLockById(ctx, lockID)
+	if err != nil {
+		return nil, err
+	}
+
+	// authorize
+	err = l.UserServiceConfig.Authorize(
+		ctx,
+		user_service.PermLockEditAny,
+		lockResource(lock),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dbGrants, err := l.DB.GetLockGrants(ctx, lock.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch grants of lock %v", lock.ID),
+		)
+		return nil, err
+	}
+
+	grants := make([]LockGrant, 0, len(dbGrants))
+	for _, dbGrant := range dbGrants {
+		grants = append(grants, LockGrant{
+			ID:         dbGrant.ID,
+			LockID:     dbGrant.LockID,
+			UserID:     dbGrant.UserID,
+			UserName:   dbGrant.UserName,
+			TeamID:     dbGrant.TeamID,
+			TeamName:   dbGrant.TeamName,
+			Permission: dbGrant.Permission,
+			GrantedBy:  dbGrant.GrantedBy,
+			CreatedAt:  dbGrant.CreatedAt,
+		})
+	}
+
+	return grants, nil
+}
+
+// RevokeLockGrant deletes the grant, only the creator of its lock can do it
+func (l *LockService) RevokeLockGrant(
+	ctx context.Context,
+	grantID uuid.UUID,
+) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// get the grant
+	dbGrant, err := l.DB.GetLockGrantByID(ctx, grantID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot get lock grant with id %v from db", grantID),
+		)
+		return err
+	}
+
+	// get the lock
+	lock, err := l.GetLockById(ctx, dbGrant.LockID)
+	if err != nil {
+		return err
+	}
+
+	// authorize
+	err = l.UserServiceConfig.Authorize(
+		ctx,
+		user_service.PermLockEditAny,
+		lockResource(lock),
+	)
+	if err != nil {
+		return err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := l.DB.WithTx(tx)
+
+	err = qtx.DeleteLockGrant(ctx, dbGrant.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot delete lock grant with id %v", dbGrant.ID),
+		)
+		return err
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionLockRevokeGrant,
+		EntityType: auditEntityLock,
+		EntityID:   lock.ID,
+		Before:     dbGrant,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf(
+			"%w, cannot commit transaction after revoking grant %v, %w",
+			flux_errors.ErrInternal,
+			dbGrant.ID,
+			err,
+		)
+	}
+
+	log.Infof("user %s revoked grant %v of lock %v", claims.UserName, dbGrant.ID, lock.ID)
+	return nil
+}
+
+// HasLockGrant reports if the user holds the permission on the lock, either
+// directly or through one of their teams
+func (l *LockService) HasLockGrant(
+	ctx context.Context,
+	lockID uuid.UUID,
+	permission database.LockGrantPermission,
+) (bool, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	held, err := l.DB.GetUserLockGrantPermissions(
+		ctx,
+		database.GetUserLockGrantPermissionsParams{
+			LockID: lockID,
+			UserID: &claims.UserId,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch grants of user %v on lock %v", claims.UserId, lockID),
+		)
+		return false, err
+	}
+
+	return GrantAllows(held, permission), nil
+}
+
+// AuthorizeLockEdit lets in the users allowed to edit a resource or having an
+// edit grant on the lock of the resource
+func (l *LockService) AuthorizeLockEdit(
+	ctx context.Context,
+	lockID *uuid.UUID,
+	permission string,
+	resource user_service.Resource,
+) error {
+	allowed, err := l.UserServiceConfig.IsAllowed(ctx, permission, resource)
+	if err != nil || allowed {
+		return err
+	}
+
+	if lockID != nil {
+		granted, err := l.HasLockGrant(ctx, *lockID, database.LockGrantPermissionEdit)
+		if err != nil {
+			return err
+		}
+		if granted {
+			return nil
+		}
+	}
+
+	// warn
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	log.Warnf(
+		"user %s tried to edit %s %v without %s",
+		claims.UserName,
+		resource.Kind,
+		resource.ID,
+		permission,
+	)
+
+	return flux_errors.ErrUnAuthorized
+}
+
+func sameGrantee(grant LockGrant, userID, teamID *uuid.UUID) bool {
+	if userID != nil {
+		return grant.UserID != nil && *grant.UserID == *userID
+	}
+	return grant.TeamID != nil && *grant.TeamID == *teamID
+}
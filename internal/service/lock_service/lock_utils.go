@@ -5,14 +5,19 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 func validateLock(lock FluxLock) error {
-	if lock.Type == database.LockTypeManual {
+	switch lock.Type {
+	case database.LockTypeManual:
 		return validateManualLock(lock)
+	case database.LockTypeComposite:
+		return validateCompositeLock(lock)
 	}
 
 	return validateTimerLockTimeout(lock)
@@ -25,6 +30,10 @@ func validateManualLock(lock FluxLock) error {
 		return err
 	}
 
+	if err = validateNotComposite(lock); err != nil {
+		return err
+	}
+
 	if lock.Timeout != nil {
 		return fmt.Errorf(
 			"%w, manual lock cannot have a timer",
@@ -42,6 +51,10 @@ func validateTimerLockTimeout(lock FluxLock) error {
 		return err
 	}
 
+	if err = validateNotComposite(lock); err != nil {
+		return err
+	}
+
 	if lock.Timeout == nil {
 		return fmt.Errorf(
 			"%w, timer lock must have a timeout",
@@ -68,6 +81,67 @@ func validateTimerLockTimeout(lock FluxLock) error {
 	return nil
 }
 
+func validateCompositeLock(lock FluxLock) error {
+	// raw validation
+	err := service.ValidateInput(lock)
+	if err != nil {
+		return err
+	}
+
+	if lock.Timeout != nil {
+		return fmt.Errorf(
+			"%w, composite lock cannot have a timer, add a timer lock as its child instead",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	if lock.Operator == nil {
+		return fmt.Errorf(
+			"%w, composite lock must have an operator",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	if len(lock.ChildLockIDs)+len(lock.AfterContestIDs) == 0 {
+		return fmt.Errorf(
+			"%w, composite lock must have atleast one child lock or contest",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(lock.ChildLockIDs)+len(lock.AfterContestIDs))
+	for _, id := range append(append([]uuid.UUID{}, lock.ChildLockIDs...), lock.AfterContestIDs...) {
+		if seen[id] {
+			return fmt.Errorf(
+				"%w, %v is given more than once",
+				flux_errors.ErrInvalidRequest,
+				id,
+			)
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
+
+func validateNotComposite(lock FluxLock) error {
+	if lock.Operator != nil || len(lock.ChildLockIDs) > 0 || len(lock.AfterContestIDs) > 0 {
+		return fmt.Errorf(
+			"%w, only composite locks can have an operator, child locks or contests",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+	return nil
+}
+
+func lockResource(lock FluxLock) user_service.Resource {
+	return user_service.Resource{
+		Kind:    "lock",
+		ID:      lock.ID,
+		OwnerID: &lock.CreatedBy,
+	}
+}
+
 func dbLockToServiceLock(dbLock database.Lock) FluxLock {
 	var timeout *time.Time
 	if dbLock.Timeout != nil {
@@ -83,48 +157,48 @@ func dbLockToServiceLock(dbLock database.Lock) FluxLock {
 		Description: dbLock.Description,
 		Type:        dbLock.LockType,
 		Access:      dbLock.Access,
+		ReleasedAt:  dbLock.ReleasedAt,
+		Operator:    dbLock.Operator,
 	}
 }
 
-func (l *LockService) IsLockExpired(
-	lock FluxLock,
-	delayMinutes int32,
-) (bool, error) {
-	if lock.Type == database.LockTypeManual {
-		return false, nil
-	}
-
-	// very rare, but for safety purpose
-	if lock.Timeout == nil {
-		return false, fmt.Errorf(
-			"%w, timer lock has timeout as nil",
-			flux_errors.ErrInternal,
-		)
-	}
-
-	if time.Now().Add(
-		time.Minute * time.Duration(delayMinutes)).After(*lock.Timeout) {
-		return true, nil
-	}
-
-	return false, nil
-}
-
+// AuthorizeLock lets the user in if the lock is open, if they are granted
+// access to the lock or if they have the access role of the lock. Timer locks
+// are decided by the given timeout, the state of the other locks is looked up.
 func (l *LockService) AuthorizeLock(
 	ctx context.Context,
+	lockID uuid.UUID,
 	timeout *time.Time,
 	access string,
 	warnMessage string,
 ) error {
-	// timer lock expired
 	if timeout != nil {
+		// timer lock expired
 		if time.Now().After(*timeout) {
 			return nil
 		}
+	} else {
+		// manual or composite lock
+		open, err := l.IsLockOpen(ctx, lockID)
+		if err != nil {
+			return err
+		}
+		if open {
+			return nil
+		}
+	}
+
+	// explicit grants to the user or their teams
+	granted, err := l.HasLockGrant(ctx, lockID, database.LockGrantPermissionView)
+	if err != nil {
+		return err
+	}
+	if granted {
+		return nil
 	}
 
 	// authorize
-	err := l.UserServiceConfig.AuthorizeUserRole(
+	err = l.UserServiceConfig.AuthorizeUserRole(
 		ctx,
 		access,
 		warnMessage,
@@ -5,11 +5,45 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 var (
-	errMsgs = map[string]map[string]string{}
+	msgForeignKey = map[string]string{
+		"fk_lock_children_child":       "Lock is used by a composite lock.",
+		"fk_lock_dependencies_contest": "Contest does not exist.",
+	}
+
+	msgUniqueConstraint = map[string]string{
+		"uq_lock_grant_user": "User already has a grant on the lock.",
+		"uq_lock_grant_team": "Team already has a grant on the lock.",
+	}
+
+	errMsgs = map[string]map[string]string{
+		flux_errors.CodeForeignKeyConstraint: msgForeignKey,
+		flux_errors.CodeUniqueConstraint:     msgUniqueConstraint,
+	}
+)
+
+// operators combining the conditions of a composite lock
+const (
+	OperatorAnd = "and"
+	OperatorOr  = "or"
+
+	// composite locks nested deeper than this are not evaluated
+	maxLockDepth = 8
+)
+
+// actions recorded in the audit log
+const (
+	auditEntityLock            = "lock"
+	auditActionLockCreate      = "lock.create"
+	auditActionLockUpdate      = "lock.update"
+	auditActionLockRelease     = "lock.release"
+	auditActionLockDelete      = "lock.delete"
+	auditActionLockGrant       = "lock.grant"
+	auditActionLockRevokeGrant = "lock.revoke_grant"
 )
 
 type LockService struct {
@@ -21,11 +55,55 @@ type FluxLock struct {
 	ID          uuid.UUID         `json:"lock_id"`
 	Name        string            `json:"name" validate:"min=4"`
 	CreatedBy   uuid.UUID         `json:"created_by"`
-	Type        database.LockType `json:"lock_type" validate:"oneof=timer manual"`
+	Type        database.LockType `json:"lock_type" validate:"oneof=timer manual composite"`
 	CreatedAt   time.Time         `json:"created_at"`
 	Timeout     *time.Time        `json:"timeout"`
 	Description string            `json:"description"`
 	Access      string            `json:"-"`
+	// set once a manual lock is released for everyone
+	ReleasedAt *time.Time `json:"released_at"`
+
+	// conditions of a composite lock, the child locks must open and the
+	// contests must end
+	Operator        *string     `json:"operator" validate:"omitempty,oneof=and or"`
+	ChildLockIDs    []uuid.UUID `json:"child_lock_ids" validate:"max=10"`
+	AfterContestIDs []uuid.UUID `json:"after_contest_ids" validate:"max=10"`
+}
+
+// LockCondition is the state a lock is evaluated from. Composite locks
+// combine the conditions of their children and the contests they wait for.
+type LockCondition struct {
+	Type       database.LockType
+	Timeout    *time.Time
+	ReleasedAt *time.Time
+	Operator   string
+	Children   []LockCondition
+	// end times of the contests the lock waits for
+	ContestEndTimes []time.Time
+}
+
+// LockGrant lets a user, or every member of a team, past a closed lock
+// without the access role of the lock. Edit grants also allow to edit the
+// resources behind the lock.
+type LockGrant struct {
+	ID         uuid.UUID                    `json:"grant_id"`
+	LockID     uuid.UUID                    `json:"lock_id"`
+	UserID     *uuid.UUID                   `json:"user_id"`
+	UserName   *string                      `json:"user_name"`
+	TeamID     *uuid.UUID                   `json:"team_id"`
+	TeamName   *string                      `json:"team_name"`
+	Permission database.LockGrantPermission `json:"permission"`
+	GrantedBy  uuid.UUID                    `json:"granted_by"`
+	CreatedAt  time.Time                    `json:"created_at"`
+}
+
+// SetLockGrantRequest grants to either a user or a team, an existing grant
+// of the same grantee has its permission replaced
+type SetLockGrantRequest struct {
+	LockID     uuid.UUID                    `json:"lock_id" validate:"required"`
+	UserName   *string                      `json:"user_name"`
+	TeamID     *uuid.UUID                   `json:"team_id"`
+	Permission database.LockGrantPermission `json:"permission" validate:"oneof=view edit"`
 }
 
 type GetLocksRequest struct {
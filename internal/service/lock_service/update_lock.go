@@ -7,18 +7,13 @@ import (
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 func (l *LockService) UpdateLock(
 	ctx context.Context,
 	lock FluxLock,
 ) (res FluxLock, err error) {
-	// get the user details from claims
-	claims, err := service.GetClaimsFromContext(ctx)
-	if err != nil {
-		return
-	}
-
 	// get previous lock
 	previousLock, err := l.GetLockById(ctx, lock.ID)
 	if err != nil {
@@ -26,14 +21,10 @@ func (l *LockService) UpdateLock(
 	}
 
 	// authorize
-	err = l.UserServiceConfig.AuthorizeCreatorAccess(
+	err = l.UserServiceConfig.Authorize(
 		ctx,
-		previousLock.CreatedBy,
-		fmt.Sprintf(
-			"user %s tried to update lock with id %v",
-			claims.UserName,
-			lock.ID,
-		),
+		user_service.PermLockEditAny,
+		lockResource(previousLock),
 	)
 	if err != nil {
 		return
@@ -45,8 +36,20 @@ func (l *LockService) UpdateLock(
 		return
 	}
 
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := l.DB.WithTx(tx)
+
 	// update the lock
-	dbLock, err := l.DB.UpdateLockDetails(
+	dbLock, err := qtx.UpdateLockDetails(
 		ctx,
 		database.UpdateLockDetailsParams{
 			Timeout:     lock.Timeout,
@@ -64,7 +67,32 @@ func (l *LockService) UpdateLock(
 		return FluxLock{}, err
 	}
 
-	return dbLockToServiceLock(dbLock), nil
+	res = dbLockToServiceLock(dbLock)
+	res.ChildLockIDs = previousLock.ChildLockIDs
+	res.AfterContestIDs = previousLock.AfterContestIDs
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionLockUpdate,
+		EntityType: auditEntityLock,
+		EntityID:   previousLock.ID,
+		Before:     previousLock,
+		After:      res,
+	})
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return FluxLock{}, fmt.Errorf(
+			"%w, cannot commit transaction after updating lock %v, %w",
+			flux_errors.ErrInternal,
+			previousLock.ID,
+			err,
+		)
+	}
+
+	return res, nil
 }
 
 func (l *LockService) validateLockUpdate(
@@ -85,5 +113,13 @@ func (l *LockService) validateLockUpdate(
 		)
 	}
 
+	// only the name and the description of a composite lock can change
+	if previousLock.Type == database.LockTypeComposite {
+		newLock.Operator = previousLock.Operator
+		newLock.ChildLockIDs = previousLock.ChildLockIDs
+		newLock.AfterContestIDs = previousLock.AfterContestIDs
+		return validateCompositeLock(newLock)
+	}
+
 	return validateManualLock(newLock)
 }
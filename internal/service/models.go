@@ -8,6 +8,12 @@ import (
 type UserCredentialClaims struct {
 	UserId   uuid.UUID `json:"user_id"`
 	UserName string    `json:"user_name"`
+	// set if the session was started with the second factor
+	TwoFactor bool `json:"two_factor,omitempty"`
+	// set only for requests made with a personal access token, such requests
+	// can use only the permissions in the scopes of the token
+	AccessTokenID *uuid.UUID `json:"-"`
+	Scopes        []string   `json:"-"`
 	jwt.RegisteredClaims
 }
 
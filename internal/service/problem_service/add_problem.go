@@ -16,20 +16,8 @@ func (p *ProblemService) AddStandardProblem(
 	problemRequest Problem,
 	spdRequest StandardProblemData,
 ) (Problem, StandardProblemData, error) {
-	// get the user details from claims
-	claims, err := service.GetClaimsFromContext(ctx)
-	if err != nil {
-		return Problem{}, StandardProblemData{}, err
-	}
-
 	// authorize (only managers can add problems)
-	err = p.UserServiceConfig.AuthorizeUserRole(
-		ctx, user_service.RoleManager,
-		fmt.Sprintf(
-			"user %s tried for manager access to add a problem",
-			claims.UserName,
-		),
-	)
+	err := p.UserServiceConfig.Authorize(ctx, user_service.PermProblemCreate, user_service.Resource{})
 	if err != nil {
 		return Problem{}, StandardProblemData{}, err
 	}
@@ -43,7 +31,7 @@ func (p *ProblemService) AddStandardProblem(
 		problemRequest.LockTimeout = lock.Timeout
 		problemRequest.LockAccess = &lock.Access
 	}
-	err = p.validateProblem(problemRequest)
+	err = p.validateProblem(ctx, problemRequest)
 	if err != nil {
 		return Problem{}, StandardProblemData{}, err
 	}
@@ -106,6 +94,17 @@ func (p *ProblemService) AddStandardProblem(
 		return Problem{}, StandardProblemData{}, err
 	}
 
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionProblemCreate,
+		EntityType: auditEntityProblem,
+		EntityID:   problemResponse.ID,
+		After:      standardProblemSnapshot{Problem: problemResponse, Data: spdResponse},
+	})
+	if err != nil {
+		return Problem{}, StandardProblemData{}, err
+	}
+
 	// commit the tx
 	if err = tx.Commit(ctx); err != nil {
 		err = fmt.Errorf(
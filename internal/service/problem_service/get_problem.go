@@ -40,6 +40,7 @@ func (p *ProblemService) GetProblemByID(
 	if dbProblem.Access != nil && !internal {
 		err = p.LockServiceConfig.AuthorizeLock(
 			ctx,
+			*dbProblem.LockID,
 			dbProblem.Timeout,
 			*dbProblem.Access,
 			fmt.Sprintf(
@@ -55,7 +56,9 @@ func (p *ProblemService) GetProblemByID(
 				id,
 			)
 		}
-		return Problem{}, err
+		if err != nil {
+			return Problem{}, err
+		}
 	}
 
 	// convert and return
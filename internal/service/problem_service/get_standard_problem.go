@@ -105,6 +105,7 @@ func (p *ProblemService) GetProblemsByFilters(
 		if dbProblem.Access != nil {
 			err := p.LockServiceConfig.AuthorizeLock(
 				ctx,
+				*dbProblem.LockID,
 				dbProblem.Timeout,
 				*dbProblem.Access,
 				"",
@@ -34,6 +34,21 @@ const (
 	InternalProblemQuery service.InternalContextKey = "internal_problem_query"
 )
 
+// actions recorded in the audit log
+const (
+	auditEntityProblem            = "problem"
+	auditActionProblemCreate      = "problem.create"
+	auditActionProblemUpdate      = "problem.update"
+	auditActionProblemUpdateData  = "problem.update_data"
+	auditActionProblemSetSubtasks = "problem.set_subtasks"
+)
+
+// standardProblemSnapshot is a standard problem as recorded in the audit log
+type standardProblemSnapshot struct {
+	Problem Problem             `json:"problem"`
+	Data    StandardProblemData `json:"data"`
+}
+
 type ProblemService struct {
 	DB                *database.Queries
 	UserServiceConfig *user_service.UserService
@@ -93,3 +108,18 @@ type GetProblemsRequest struct {
 	CreatorUserName *string    `json:"creator_user_name"`
 	CreatorRollNo   *string    `json:"creator_roll_number"`
 }
+
+// Subtask is a group of testcases worth some points. It is awarded only if all
+// its testcases pass and all the subtasks it depends on are awarded. A subtask
+// can depend only on the subtasks before it.
+type Subtask struct {
+	SubtaskID int32   `json:"subtask_id" validate:"min=1"`
+	Points    int32   `json:"points" validate:"min=0,max=10000"`
+	Testcases []int32 `json:"testcases" validate:"min=1,dive,min=1"`
+	DependsOn []int32 `json:"depends_on" validate:"dive,min=1"`
+}
+
+type SetSubtasksRequest struct {
+	ProblemID int32     `json:"problem_id"`
+	Subtasks  []Subtask `json:"subtasks" validate:"max=50,dive"`
+}
@@ -6,14 +6,17 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	log "github.com/sirupsen/logrus"
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 func (p *ProblemService) validateProblem(
+	ctx context.Context,
 	ap Problem,
 ) error {
 	// raw validations
@@ -23,18 +26,33 @@ func (p *ProblemService) validateProblem(
 	}
 
 	// validate its lock
-	if ap.LockTimeout != nil {
-		if time.Now().After(*ap.LockTimeout) {
-			return fmt.Errorf(
-				"%w, expired lock cannot be used to create problem",
-				flux_errors.ErrInvalidRequest,
-			)
+	if ap.LockID != nil {
+		err = p.validateProblemLock(ctx, *ap.LockID)
+		if err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// validateProblemLock fails if the lock is open already, as it would not hide
+// the problem. Composite locks are evaluated like everywhere else.
+func (p *ProblemService) validateProblemLock(ctx context.Context, lockID uuid.UUID) error {
+	condition, err := p.LockServiceConfig.GetLockCondition(ctx, lockID)
+	if err != nil {
+		return err
+	}
+	if condition.IsOpenAt(time.Now()) {
+		return fmt.Errorf(
+			"%w, expired lock cannot be used to lock a problem",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	return nil
+}
+
 func (p *ProblemService) validateStandardProblemData(spd StandardProblemData) error {
 	// perform validation using validator first
 	err := service.ValidateInput(spd)
@@ -64,6 +82,14 @@ func (p *ProblemService) validateStandardProblemData(spd StandardProblemData) er
 	return nil
 }
 
+func problemResource(problem Problem) user_service.Resource {
+	return user_service.Resource{
+		Kind:    "problem",
+		ID:      problem.ID,
+		OwnerID: &problem.CreatedBy,
+	}
+}
+
 func getDbSpdParams(
 	functionDefinitions map[string]string,
 	exampleTestCases *ExampleTestCases,
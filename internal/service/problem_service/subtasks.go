@@ -0,0 +1,221 @@
+package problem_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// SetProblemSubtasks replaces the subtasks of the problem. An empty list makes
+// the problem all-or-nothing again.
+func (p *ProblemService) SetProblemSubtasks(
+	ctx context.Context,
+	request SetSubtasksRequest,
+) ([]Subtask, error) {
+	// get problem
+	problem, err := p.GetProblemByID(ctx, request.ProblemID)
+	if err != nil {
+		return nil, err
+	}
+
+	// authorize
+	err = p.LockServiceConfig.AuthorizeLockEdit(
+		ctx,
+		problem.LockID,
+		user_service.PermProblemEditAny,
+		problemResource(problem),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return nil, err
+	}
+	err = validateSubtasks(request.Subtasks)
+	if err != nil {
+		return nil, err
+	}
+
+	// subtasks before the change
+	prevSubtasks, err := p.GetProblemSubtasks(ctx, problem.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := p.DB.WithTx(tx)
+
+	// replace the old subtasks
+	err = qtx.DeleteProblemSubtasks(ctx, problem.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot delete subtasks of problem with id %v", problem.ID),
+		)
+		return nil, err
+	}
+	for _, subtask := range request.Subtasks {
+		dependsOn := subtask.DependsOn
+		if dependsOn == nil {
+			dependsOn = []int32{}
+		}
+		err = qtx.CreateProblemSubtask(
+			ctx,
+			database.CreateProblemSubtaskParams{
+				ProblemID: problem.ID,
+				SubtaskID: subtask.SubtaskID,
+				Points:    subtask.Points,
+				Testcases: subtask.Testcases,
+				DependsOn: dependsOn,
+			},
+		)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf(
+					"cannot create subtask %d of problem with id %v",
+					subtask.SubtaskID,
+					problem.ID,
+				),
+			)
+			return nil, err
+		}
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionProblemSetSubtasks,
+		EntityType: auditEntityProblem,
+		EntityID:   problem.ID,
+		Before:     prevSubtasks,
+		After:      request.Subtasks,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf(
+			"%w, cannot commit transaction after setting subtasks of problem with id %v, %w",
+			flux_errors.ErrInternal,
+			problem.ID,
+			err,
+		)
+	}
+
+	return p.GetProblemSubtasks(ctx, problem.ID)
+}
+
+func (p *ProblemService) GetProblemSubtasks(
+	ctx context.Context,
+	problemID int32,
+) ([]Subtask, error) {
+	// authorize through the problem
+	problem, err := p.GetProblemByID(ctx, problemID)
+	if err != nil {
+		return nil, err
+	}
+
+	dbSubtasks, err := p.DB.GetProblemSubtasks(ctx, problem.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch subtasks of problem with id %v", problem.ID),
+		)
+		return nil, err
+	}
+
+	subtasks := make([]Subtask, 0, len(dbSubtasks))
+	for _, dbSubtask := range dbSubtasks {
+		subtasks = append(subtasks, Subtask{
+			SubtaskID: dbSubtask.SubtaskID,
+			Points:    dbSubtask.Points,
+			Testcases: dbSubtask.Testcases,
+			DependsOn: dbSubtask.DependsOn,
+		})
+	}
+
+	return subtasks, nil
+}
+
+// ScoreSubtasks returns the sum of the points of the awarded subtasks along
+// with the subtasks that were awarded. A subtask passed by the evaluator is
+// not awarded if any of its dependencies is not awarded.
+func ScoreSubtasks(subtasks []Subtask, passed map[int32]bool) (int32, map[int32]bool) {
+	awarded := make(map[int32]bool, len(subtasks))
+	var score int32
+
+	// dependencies come before the subtask, so one pass is enough
+	for _, subtask := range subtasks {
+		if !passed[subtask.SubtaskID] {
+			continue
+		}
+		ok := true
+		for _, dependency := range subtask.DependsOn {
+			if !awarded[dependency] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			awarded[subtask.SubtaskID] = true
+			score += subtask.Points
+		}
+	}
+
+	return score, awarded
+}
+
+// validateSubtasks checks that the subtasks are numbered from 1 in order and
+// depend only on the subtasks before them, which also rules out cycles
+func validateSubtasks(subtasks []Subtask) error {
+	for i, subtask := range subtasks {
+		if subtask.SubtaskID != int32(i+1) {
+			return fmt.Errorf(
+				"%w, subtasks must be numbered from 1 in order, found %d at position %d",
+				flux_errors.ErrInvalidRequest,
+				subtask.SubtaskID,
+				i+1,
+			)
+		}
+		seen := make(map[int32]bool, len(subtask.DependsOn))
+		for _, dependency := range subtask.DependsOn {
+			if dependency >= subtask.SubtaskID {
+				return fmt.Errorf(
+					"%w, subtask %d can depend only on the subtasks before it",
+					flux_errors.ErrInvalidRequest,
+					subtask.SubtaskID,
+				)
+			}
+			if seen[dependency] {
+				return fmt.Errorf(
+					"%w, subtask %d depends on subtask %d more than once",
+					flux_errors.ErrInvalidRequest,
+					subtask.SubtaskID,
+					dependency,
+				)
+			}
+			seen[dependency] = true
+		}
+	}
+	return nil
+}
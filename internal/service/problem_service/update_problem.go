@@ -7,6 +7,7 @@ import (
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 func (p *ProblemService) UpdateProblem(
@@ -26,26 +27,48 @@ func (p *ProblemService) UpdateProblem(
 	}
 
 	// authorize
-	if err = p.UserServiceConfig.AuthorizeCreatorAccess(
+	if err = p.LockServiceConfig.AuthorizeLockEdit(
 		ctx,
-		problem.CreatedBy,
-		fmt.Sprintf(
-			"user %s tried to update unauthorized problem with id %v",
-			claims.UserName,
-			problem.ID,
-		),
+		oldProblem.LockID,
+		user_service.PermProblemEditAny,
+		problemResource(oldProblem),
 	); err != nil {
 		return Problem{}, err
 	}
 
+	// grantees of the lock cannot move the problem to another lock
+	lockChanged := (oldProblem.LockID == nil) != (problem.LockID == nil) ||
+		(oldProblem.LockID != nil && *oldProblem.LockID != *problem.LockID)
+	if lockChanged {
+		if err = p.UserServiceConfig.Authorize(
+			ctx,
+			user_service.PermProblemEditAny,
+			problemResource(oldProblem),
+		); err != nil {
+			return Problem{}, err
+		}
+	}
+
 	// validate
 	if err = p.validateProblemUpdate(ctx, oldProblem, problem); err != nil {
 		return Problem{}, err
 	}
 
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return Problem{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := p.DB.WithTx(tx)
+
 	// update
 	dbProblem, err := updateProblem(
-		ctx, p.DB,
+		ctx, qtx,
 		database.UpdateProblemParams{
 			ID:            problem.ID,
 			Title:         problem.Title,
@@ -59,15 +82,38 @@ func (p *ProblemService) UpdateProblem(
 		return Problem{}, err
 	}
 
-	// convert and return
-	return Problem{
+	// convert
+	updatedProblem := Problem{
 		ID:         dbProblem.ID,
 		Title:      dbProblem.Title,
 		Difficulty: dbProblem.Difficulty,
 		Evaluator:  dbProblem.Evaluator,
 		LockID:     dbProblem.LockID,
 		CreatedBy:  dbProblem.CreatedBy,
-	}, nil
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionProblemUpdate,
+		EntityType: auditEntityProblem,
+		EntityID:   oldProblem.ID,
+		Before:     oldProblem,
+		After:      updatedProblem,
+	})
+	if err != nil {
+		return Problem{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Problem{}, fmt.Errorf(
+			"%w, cannot commit transaction after updating problem with id %v, %w",
+			flux_errors.ErrInternal,
+			oldProblem.ID,
+			err,
+		)
+	}
+
+	return updatedProblem, nil
 }
 
 func (p *ProblemService) validateProblemUpdate(
@@ -98,12 +144,23 @@ func (p *ProblemService) validateProblemUpdate(
 		return nil
 	}
 
+	// lock is same
+	if oldProblem.LockID != nil && *oldProblem.LockID == *newProblem.LockID {
+		return nil
+	}
+
 	// new problem's lock is not nil
 	newProblemLock, err := p.LockServiceConfig.GetLockById(ctx, *newProblem.LockID)
 	if err != nil {
 		return err
 	}
 
+	// a new lock must still hide the problem
+	err = p.validateProblemLock(ctx, newProblemLock.ID)
+	if err != nil {
+		return err
+	}
+
 	// problem is being locked
 	if oldProblem.LockID == nil {
 		if newProblemLock.Type == database.LockTypeTimer {
@@ -115,11 +172,6 @@ func (p *ProblemService) validateProblemUpdate(
 		return nil
 	}
 
-	// lock is same
-	if oldProblem.LockID == newProblem.LockID {
-		return nil
-	}
-
 	// lock is being changed
 	// timer lock cannot be changed
 	if oldProblem.LockTimeout != nil {
@@ -5,7 +5,9 @@ import (
 	"fmt"
 
 	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 func (p *ProblemService) UpdateStandardProblem(
@@ -25,14 +27,11 @@ func (p *ProblemService) UpdateStandardProblem(
 	}
 
 	// authorize
-	err = p.UserServiceConfig.AuthorizeCreatorAccess(
+	err = p.LockServiceConfig.AuthorizeLockEdit(
 		ctx,
-		problem.CreatedBy,
-		fmt.Sprintf(
-			"user %s tried to update standard_problem with id %v",
-			claims.UserName,
-			problem.ID,
-		),
+		problem.LockID,
+		user_service.PermProblemEditAny,
+		problemResource(problem),
 	)
 	if err != nil {
 		return StandardProblemData{}, err
@@ -44,12 +43,42 @@ func (p *ProblemService) UpdateStandardProblem(
 		return StandardProblemData{}, err
 	}
 
+	// data before the change
+	dbPrevSpd, err := p.DB.GetStandardProblemData(ctx, problem.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch standard_problem_data with id %v", problem.ID),
+		)
+		return StandardProblemData{}, err
+	}
+	prevSpd, err := dbSpdToServiceSpd(dbPrevSpd)
+	if err != nil {
+		return StandardProblemData{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return StandardProblemData{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := p.DB.WithTx(tx)
+
 	// updates spd
 	updateParams, err := getDbSpdParams(
 		spd.FunctionDefinitions,
 		spd.ExampleTestCases,
 	)
-	dbSpd, err := p.DB.UpdateStandardProblemData(
+	if err != nil {
+		return StandardProblemData{}, err
+	}
+	dbSpd, err := qtx.UpdateStandardProblemData(
 		ctx,
 		database.UpdateStandardProblemDataParams{
 			ProblemID:          spd.ProblemID,
@@ -65,6 +94,14 @@ func (p *ProblemService) UpdateStandardProblem(
 			LastUpdatedBy:      claims.UserId,
 		},
 	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot update standard_problem_data with id %v", spd.ProblemID),
+		)
+		return StandardProblemData{}, err
+	}
 
 	// convert dbSpd to serviceSpd
 	spdResponse, err := dbSpdToServiceSpd(dbSpd)
@@ -72,5 +109,26 @@ func (p *ProblemService) UpdateStandardProblem(
 		return StandardProblemData{}, err
 	}
 
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionProblemUpdateData,
+		EntityType: auditEntityProblem,
+		EntityID:   problem.ID,
+		Before:     prevSpd,
+		After:      spdResponse,
+	})
+	if err != nil {
+		return StandardProblemData{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return StandardProblemData{}, fmt.Errorf(
+			"%w, cannot commit transaction after updating standard_problem_data with id %v, %w",
+			flux_errors.ErrInternal,
+			problem.ID,
+			err,
+		)
+	}
+
 	return spdResponse, nil
 }
@@ -0,0 +1,103 @@
+package rating_service
+
+import (
+	"math"
+	"sort"
+)
+
+// CalculateRatingChanges computes the rating change of every participant of a
+// contest using the codeforces rating formula. The returned deltas are in the
+// same order as the participants. Participants sharing a rank are considered
+// to have taken the last place of their tie.
+func CalculateRatingChanges(participants []RatingParticipant) []int32 {
+	n := len(participants)
+	deltas := make([]int32, n)
+	if n < 2 {
+		return deltas
+	}
+
+	// tied participants share the worst place of their tie
+	places := make([]float64, n)
+	for i := range participants {
+		for j := range participants {
+			if participants[j].Rank <= participants[i].Rank {
+				places[i]++
+			}
+		}
+	}
+
+	// expected place of each participant, given its rating
+	seeds := make([]float64, n)
+	for i := range participants {
+		seeds[i] = expectedPlace(participants, float64(participants[i].Rating), i)
+	}
+
+	sum := 0
+	for i, participant := range participants {
+		// the rating which would have made the performance expected
+		midPlace := math.Sqrt(seeds[i] * places[i])
+		needRating := ratingForPlace(participants, midPlace, i)
+		deltas[i] = int32((needRating - int(participant.Rating)) / 2)
+		sum += int(deltas[i])
+	}
+
+	// rating must not inflate, so the total is made slightly negative
+	inc := int32(-sum/n - 1)
+	for i := range deltas {
+		deltas[i] += inc
+	}
+
+	// top rated participants must not gain rating on their total
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return participants[order[a]].Rating > participants[order[b]].Rating
+	})
+	topCount := min(n, int(4*math.Round(math.Sqrt(float64(n)))))
+	topSum := 0
+	for _, i := range order[:topCount] {
+		topSum += int(deltas[i])
+	}
+	inc = int32(min(max(-topSum/topCount, -10), 0))
+	for i := range deltas {
+		deltas[i] += inc
+	}
+
+	return deltas
+}
+
+// winProbability returns the probability of a participant with rating a
+// finishing ahead of one with rating b
+func winProbability(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// expectedPlace returns the place a participant with the given rating is
+// expected to take against everyone except the participant at index skip
+func expectedPlace(participants []RatingParticipant, rating float64, skip int) float64 {
+	place := 1.0
+	for i, participant := range participants {
+		if i == skip {
+			continue
+		}
+		place += winProbability(float64(participant.Rating), rating)
+	}
+	return place
+}
+
+// ratingForPlace finds the highest rating whose expected place is not better
+// than the given place
+func ratingForPlace(participants []RatingParticipant, place float64, skip int) int {
+	low, high := 1, 8000
+	for high-low > 1 {
+		mid := (low + high) / 2
+		if expectedPlace(participants, float64(mid), skip) < place {
+			high = mid
+		} else {
+			low = mid
+		}
+	}
+	return low
+}
@@ -0,0 +1,57 @@
+package rating_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tcp_snm/flux/internal/flux_errors"
+)
+
+// GetUserRating returns the current rating of the user along with the changes
+// of every rated contest the user took part in
+func (r *RatingService) GetUserRating(
+	ctx context.Context,
+	userName string,
+) (UserRating, error) {
+	// get the user
+	user, err := r.UserServiceConfig.FetchUserByUserName(ctx, userName)
+	if err != nil {
+		return UserRating{}, err
+	}
+
+	// get the history
+	dbHistory, err := r.DB.GetUserRatingHistory(ctx, user.UserID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot get rating history of user %s", userName),
+		)
+		return UserRating{}, err
+	}
+
+	history := make([]RatingChange, 0, len(dbHistory))
+	for _, dbChange := range dbHistory {
+		history = append(history, RatingChange{
+			ContestID:    dbChange.ContestID,
+			ContestTitle: dbChange.Title,
+			EndTime:      dbChange.EndTime,
+			Rank:         dbChange.Rank,
+			OldRating:    dbChange.OldRating,
+			NewRating:    dbChange.NewRating,
+		})
+	}
+
+	// rating after the latest contest is the current one
+	rating := int32(InitialRating)
+	if len(history) > 0 {
+		rating = history[len(history)-1].NewRating
+	}
+
+	return UserRating{
+		UserID:   user.UserID,
+		UserName: user.UserName,
+		Rating:   rating,
+		History:  history,
+	}, nil
+}
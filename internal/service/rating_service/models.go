@@ -0,0 +1,65 @@
+package rating_service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+var (
+	errMsgs = map[string]map[string]string{}
+)
+
+const (
+	// rating of a user who has not taken part in any rated contest
+	InitialRating = 1500
+)
+
+type RatingService struct {
+	DB                   *database.Queries
+	UserServiceConfig    *user_service.UserService
+	ContestServiceConfig *contest_service.ContestService
+
+	// serializes the recomputations, as each one rewrites the history
+	// of all the contests after the one it starts from
+	recomputeMu sync.Mutex
+}
+
+// RatingParticipant is a participant of a rated contest as seen by the
+// rating engine
+type RatingParticipant struct {
+	UserID uuid.UUID
+	Rank   int32
+	Rating int32
+}
+
+type ContestRating struct {
+	ContestID   uuid.UUID  `json:"contest_id"`
+	IsRated     bool       `json:"is_rated"`
+	FinalizedAt *time.Time `json:"finalized_at"`
+}
+
+type SetContestRatedRequest struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	IsRated   bool      `json:"is_rated"`
+}
+
+type RatingChange struct {
+	ContestID    uuid.UUID `json:"contest_id"`
+	ContestTitle string    `json:"contest_title"`
+	EndTime      time.Time `json:"end_time"`
+	Rank         int32     `json:"rank"`
+	OldRating    int32     `json:"old_rating"`
+	NewRating    int32     `json:"new_rating"`
+}
+
+type UserRating struct {
+	UserID   uuid.UUID      `json:"user_id"`
+	UserName string         `json:"user_name"`
+	Rating   int32          `json:"rating"`
+	History  []RatingChange `json:"history"`
+}
@@ -0,0 +1,267 @@
+package rating_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+func (r *RatingService) GetContestRating(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (ContestRating, error) {
+	dbRating, err := r.DB.GetContestRating(ctx, contestID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot get rating status of contest %v", contestID),
+		)
+		if errors.Is(err, flux_errors.ErrNotFound) {
+			return ContestRating{ContestID: contestID}, nil
+		}
+		return ContestRating{}, err
+	}
+
+	return ContestRating{
+		ContestID:   dbRating.ContestID,
+		IsRated:     true,
+		FinalizedAt: dbRating.FinalizedAt,
+	}, nil
+}
+
+// SetContestRated marks the contest as rated or unrated. Making a finalized
+// contest unrated drops its rating changes and recomputes the ratings of the
+// contests after it.
+func (r *RatingService) SetContestRated(
+	ctx context.Context,
+	request SetContestRatedRequest,
+) (ContestRating, error) {
+	// only managers can change the ratings
+	err := r.UserServiceConfig.Authorize(ctx, user_service.PermRatingManage, user_service.Resource{})
+	if err != nil {
+		return ContestRating{}, err
+	}
+
+	// get the contest
+	contest, err := r.ContestServiceConfig.GetContestByID(ctx, request.ContestID)
+	if err != nil {
+		return ContestRating{}, err
+	}
+
+	if request.IsRated {
+		err = r.DB.SetContestRated(ctx, contest.ID)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot mark contest %v as rated", contest.ID),
+			)
+			return ContestRating{}, err
+		}
+		return r.GetContestRating(ctx, contest.ID)
+	}
+
+	current, err := r.GetContestRating(ctx, contest.ID)
+	if err != nil {
+		return ContestRating{}, err
+	}
+	if !current.IsRated {
+		return current, nil
+	}
+
+	r.recomputeMu.Lock()
+	defer r.recomputeMu.Unlock()
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return ContestRating{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := r.DB.WithTx(tx)
+
+	// rating changes of the contest are deleted with it
+	err = qtx.DeleteContestRating(ctx, contest.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot mark contest %v as unrated", contest.ID),
+		)
+		return ContestRating{}, err
+	}
+
+	// later contests were rated with the changes of this one
+	if current.FinalizedAt != nil {
+		err = r.recomputeSince(ctx, qtx, contest.EndTime)
+		if err != nil {
+			return ContestRating{}, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return ContestRating{}, fmt.Errorf(
+			"%w, cannot commit transaction after marking contest %v as unrated, %w",
+			flux_errors.ErrInternal,
+			contest.ID,
+			err,
+		)
+	}
+
+	return ContestRating{ContestID: contest.ID}, nil
+}
+
+// FinalizeContestRatings computes the rating changes of a rated contest from
+// its final standings
+func (r *RatingService) FinalizeContestRatings(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (ContestRating, error) {
+	return r.computeContestRatings(ctx, contestID, false)
+}
+
+// RecomputeRatings recomputes the rating changes of a finalized contest and of
+// all the contests after it. It is used after corrections to the standings,
+// like rejudges or disqualifications.
+func (r *RatingService) RecomputeRatings(
+	ctx context.Context,
+	contestID uuid.UUID,
+) (ContestRating, error) {
+	return r.computeContestRatings(ctx, contestID, true)
+}
+
+func (r *RatingService) computeContestRatings(
+	ctx context.Context,
+	contestID uuid.UUID,
+	recompute bool,
+) (ContestRating, error) {
+	// only managers can change the ratings
+	err := r.UserServiceConfig.Authorize(ctx, user_service.PermRatingManage, user_service.Resource{})
+	if err != nil {
+		return ContestRating{}, err
+	}
+
+	// get the contest
+	contest, err := r.ContestServiceConfig.GetContestByID(ctx, contestID)
+	if err != nil {
+		return ContestRating{}, err
+	}
+
+	return r.rateEndedContest(ctx, contest, recompute)
+}
+
+// FinalizeEndedContest finalizes the ratings of a contest once it ends. It is
+// meant to be run by the contest lifecycle, so it does not authorize and it
+// ignores the contests which are unrated or already finalized.
+func (r *RatingService) FinalizeEndedContest(ctx context.Context, contestID uuid.UUID) error {
+	current, err := r.GetContestRating(ctx, contestID)
+	if err != nil {
+		return err
+	}
+	if !current.IsRated || current.FinalizedAt != nil {
+		return nil
+	}
+
+	contest, err := r.ContestServiceConfig.GetContestByID(ctx, contestID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.rateEndedContest(ctx, contest, false)
+	return err
+}
+
+func (r *RatingService) rateEndedContest(
+	ctx context.Context,
+	contest contest_service.Contest,
+	recompute bool,
+) (ContestRating, error) {
+	// standings are final only after the contest ends
+	if !time.Now().After(contest.EndTime) {
+		return ContestRating{}, fmt.Errorf(
+			"%w, ratings can be computed only after the contest has ended",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	r.recomputeMu.Lock()
+	defer r.recomputeMu.Unlock()
+
+	// check the rated status
+	current, err := r.GetContestRating(ctx, contest.ID)
+	if err != nil {
+		return ContestRating{}, err
+	}
+	if !current.IsRated {
+		return ContestRating{}, fmt.Errorf(
+			"%w, contest is not rated",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+	if recompute && current.FinalizedAt == nil {
+		return ContestRating{}, fmt.Errorf(
+			"%w, ratings of the contest are not finalized yet",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+	if !recompute && current.FinalizedAt != nil {
+		return ContestRating{}, fmt.Errorf(
+			"%w, ratings of the contest are already finalized",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return ContestRating{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := r.DB.WithTx(tx)
+
+	if !recompute {
+		dbRating, err := qtx.FinalizeContestRating(ctx, contest.ID)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot finalize ratings of contest %v", contest.ID),
+			)
+			return ContestRating{}, err
+		}
+		current.FinalizedAt = dbRating.FinalizedAt
+	}
+
+	// contests ended after this one may have been rated already
+	err = r.recomputeSince(ctx, qtx, contest.EndTime)
+	if err != nil {
+		return ContestRating{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return ContestRating{}, fmt.Errorf(
+			"%w, cannot commit transaction after computing ratings of contest %v, %w",
+			flux_errors.ErrInternal,
+			contest.ID,
+			err,
+		)
+	}
+
+	return current, nil
+}
@@ -0,0 +1,124 @@
+package rating_service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+)
+
+// recomputeSince rewrites the rating changes of all the finalized contests that
+// ended at or after the given time, in the order they ended. Each contest is
+// rated with the ratings the users had after the contests before it.
+func (r *RatingService) recomputeSince(
+	ctx context.Context,
+	qtx *database.Queries,
+	since time.Time,
+) error {
+	// get the contests to be rated again
+	contests, err := qtx.GetFinalizedRatedContestsSince(ctx, since)
+	if err != nil {
+		return flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot get rated contests ended since %v", since),
+		)
+	}
+
+	// drop their old changes
+	err = qtx.DeleteRatingChangesSince(ctx, since)
+	if err != nil {
+		return flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot delete rating changes of contests ended since %v", since),
+		)
+	}
+
+	for _, contest := range contests {
+		err = r.rateContest(ctx, qtx, contest.ID, contest.EndTime)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rateContest computes and stores the rating changes of a single contest
+func (r *RatingService) rateContest(
+	ctx context.Context,
+	qtx *database.Queries,
+	contestID uuid.UUID,
+	endTime time.Time,
+) error {
+	// get the final standings
+	standings, err := r.ContestServiceConfig.GetFinalStandings(ctx, contestID)
+	if err != nil {
+		return err
+	}
+
+	// get the ratings of the users before the contest
+	dbRatings, err := qtx.GetRatingsBefore(ctx, endTime)
+	if err != nil {
+		return flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot get ratings before contest %v", contestID),
+		)
+	}
+	ratings := make(map[uuid.UUID]int32, len(dbRatings))
+	for _, dbRating := range dbRatings {
+		ratings[dbRating.UserID] = dbRating.NewRating
+	}
+
+	// every member of a team takes the rank of the team
+	participants := make([]RatingParticipant, 0, len(standings.Rows))
+	for _, row := range standings.Rows {
+		userIDs := row.MemberIDs
+		if len(userIDs) == 0 {
+			userIDs = []uuid.UUID{row.UserID}
+		}
+		for _, userID := range userIDs {
+			rating, ok := ratings[userID]
+			if !ok {
+				rating = InitialRating
+			}
+			participants = append(participants, RatingParticipant{
+				UserID: userID,
+				Rank:   row.Rank,
+				Rating: rating,
+			})
+		}
+	}
+
+	deltas := CalculateRatingChanges(participants)
+	for i, participant := range participants {
+		err = qtx.CreateRatingChange(
+			ctx,
+			database.CreateRatingChangeParams{
+				ContestID: contestID,
+				UserID:    participant.UserID,
+				OldRating: participant.Rating,
+				NewRating: participant.Rating + deltas[i],
+				Rank:      participant.Rank,
+			},
+		)
+		if err != nil {
+			return flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf(
+					"cannot store rating change of user %v in contest %v",
+					participant.UserID,
+					contestID,
+				),
+			)
+		}
+	}
+
+	return nil
+}
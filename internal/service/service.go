@@ -28,6 +28,8 @@ const (
 	KeyExp                                  = "exp"
 	KeyIAt                                  = "iat"
 	KeyCtxUserCredClaims InternalContextKey = "UserCredClaims"
+	KeyCtxRequestID      InternalContextKey = "RequestID"
+	AuditActorServer                        = "server"
 )
 
 var (
@@ -29,6 +29,17 @@ const (
 	internalSubmissionQuery service.InternalContextKey = "internal-subission-query"
 )
 
+// actions recorded in the audit log
+const (
+	auditEntityBot       = "bot"
+	auditActionBotCreate = "bot.create"
+	auditActionBotUpdate = "bot.update"
+	auditActionBotDelete = "bot.delete"
+	// subtask verdicts reported by the judge
+	auditEntitySubmission             = "submission"
+	auditActionSubmissionSubtaskScore = "submission.subtask_score"
+)
+
 type mailID string
 
 // used by masters to send workers to sleep
@@ -66,9 +77,10 @@ type SubmissionService struct {
 }
 
 type SubmissionRequest struct {
-	ProblemID int32             `json:"problem_id"`
-	ContestID *uuid.UUID        `json:"contest_id"`
-	Solution  map[string]string `json:"solution"`
+	ProblemID              int32             `json:"problem_id"`
+	ContestID              *uuid.UUID        `json:"contest_id"`
+	VirtualParticipationID *uuid.UUID        `json:"virtual_participation_id"`
+	Solution               map[string]string `json:"solution"`
 }
 
 type Evaluator interface {
@@ -131,6 +143,28 @@ type fluxSubmission struct {
 	State        string            `json:"submission_state"`
 	SubmitedAt   time.Time         `json:"submitted_at"`
 	UpdatedAt    time.Time         `json:"updated_at"`
+	// set only for problems with subtasks
+	Score          *int32          `json:"score,omitempty"`
+	SubtaskResults []SubtaskResult `json:"subtask_results,omitempty"`
+}
+
+// SubtaskResult is the verdict of a single subtask reported by the evaluator
+type SubtaskResult struct {
+	SubtaskID int32 `json:"subtask_id"`
+	Passed    bool  `json:"passed"`
+}
+
+// RecordSubtaskResultsRequest is sent by the judge once a submission is
+// evaluated
+type RecordSubtaskResultsRequest struct {
+	SubmissionID uuid.UUID       `json:"submission_id" validate:"required"`
+	Results      []SubtaskResult `json:"results" validate:"required"`
+}
+
+type SubtaskScore struct {
+	SubmissionID uuid.UUID       `json:"submission_id"`
+	Score        int32           `json:"score"`
+	Results      []SubtaskResult `json:"results"`
 }
 
 // used as a generic sentinel signal to signal components to stop
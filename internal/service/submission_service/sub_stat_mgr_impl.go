@@ -44,6 +44,14 @@ func (ssm *subStatManagerImpl) getSubmission(ctx context.Context, subID uuid.UUI
 		return nil, err
 	}
 
+	// get the subtask verdicts of partially scored submissions
+	if fluxSub.Score != nil {
+		fluxSub.SubtaskResults, err = ssm.getSubtaskResults(ctx, subID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// get submission from db based on evaluator
 	switch problem.Evaluator {
 	case platformCodeforces:
@@ -109,6 +117,30 @@ func (ssm *subStatManagerImpl) getCfSubmissionByID(ctx context.Context, fluxSub
 	return res, nil
 }
 
+func (ssm *subStatManagerImpl) getSubtaskResults(
+	ctx context.Context,
+	subID uuid.UUID,
+) ([]SubtaskResult, error) {
+	dbResults, err := ssm.db.GetSubtaskResults(ctx, subID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot get subtask results of submission with id %v from db", subID),
+		)
+		return nil, err
+	}
+
+	results := make([]SubtaskResult, 0, len(dbResults))
+	for _, dbResult := range dbResults {
+		results = append(results, SubtaskResult{
+			SubtaskID: dbResult.SubtaskID,
+			Passed:    dbResult.Passed,
+		})
+	}
+	return results, nil
+}
+
 // used for updating submssion status along with some other table in a transaction
 func (ssm *subStatManagerImpl) updateSubmission(
 	ctx context.Context,
@@ -105,17 +105,8 @@ func (sub *SubmissionService) Start(
 }
 
 func (sub *SubmissionService) AddBot(ctx context.Context, bot Bot) (Bot, error) {
-	// get claims
-	claims, err := service.GetClaimsFromContext(ctx)
-	if err != nil {
-		return Bot{}, err
-	}
-
 	// authorize
-	if err = sub.UserService.AuthorizeUserRole(
-		ctx, user_service.RoleManager,
-		fmt.Sprintf("user %s tried for manager access to add a bot", claims.UserName),
-	); err != nil {
+	if err := sub.UserService.Authorize(ctx, user_service.PermBotManage, user_service.Resource{}); err != nil {
 		return Bot{}, err
 	}
 
@@ -134,6 +125,12 @@ func (sub *SubmissionService) AddBot(ctx context.Context, bot Bot) (Bot, error)
 
 	qtx := sub.DB.WithTx(tx)
 
+	// bot before the change
+	oldBot, err := sub.lockBot(ctx, qtx, bot.Name)
+	if err != nil {
+		return Bot{}, err
+	}
+
 	// marshal cookies
 	cookieBytes, err := json.Marshal(bot.Cookies)
 	if err != nil {
@@ -166,6 +163,18 @@ func (sub *SubmissionService) AddBot(ctx context.Context, bot Bot) (Bot, error)
 		return Bot{}, err
 	}
 
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionBotCreate,
+		EntityType: auditEntityBot,
+		EntityID:   fluxBot.Name,
+		Before:     botAuditSnapshot(oldBot),
+		After:      botAuditSnapshot(fluxBot),
+	})
+	if err != nil {
+		return Bot{}, err
+	}
+
 	// refresh bots
 	if err = sub.RefreshBots(ctx); err != nil {
 		sub.logger.Error(
@@ -189,20 +198,8 @@ func (sub *SubmissionService) AddBot(ctx context.Context, bot Bot) (Bot, error)
 }
 
 func (sub *SubmissionService) RefreshBots(ctx context.Context) error {
-	// get claims
-	claims, err := service.GetClaimsFromContext(ctx)
-	if err != nil {
-		return err
-	}
-
 	// authroize
-	if err = sub.UserService.AuthorizeUserRole(
-		ctx, user_service.RoleManager,
-		fmt.Sprintf(
-			"user %s tried to for manager access to refresh bots",
-			claims.UserName,
-		),
-	); err != nil {
+	if err := sub.UserService.Authorize(ctx, user_service.PermBotManage, user_service.Resource{}); err != nil {
 		return err
 	}
 
@@ -219,17 +216,8 @@ func (sub *SubmissionService) RefreshBots(ctx context.Context) error {
 }
 
 func (sub *SubmissionService) UpdateBot(ctx context.Context, bot Bot) (Bot, error) {
-	// get claims
-	claims, err := service.GetClaimsFromContext(ctx)
-	if err != nil {
-		return Bot{}, err
-	}
-
 	// authorize
-	if err = sub.UserService.AuthorizeUserRole(
-		ctx, user_service.RoleManager,
-		fmt.Sprintf("user %s tried for manager access to update a bot", claims.UserName),
-	); err != nil {
+	if err := sub.UserService.Authorize(ctx, user_service.PermBotManage, user_service.Resource{}); err != nil {
 		return Bot{}, err
 	}
 
@@ -248,6 +236,12 @@ func (sub *SubmissionService) UpdateBot(ctx context.Context, bot Bot) (Bot, erro
 
 	qtx := sub.DB.WithTx(tx)
 
+	// bot before the change
+	oldBot, err := sub.lockBot(ctx, qtx, bot.Name)
+	if err != nil {
+		return Bot{}, err
+	}
+
 	// marshal cookies
 	cookieBytes, err := json.Marshal(bot.Cookies)
 	if err != nil {
@@ -279,6 +273,18 @@ func (sub *SubmissionService) UpdateBot(ctx context.Context, bot Bot) (Bot, erro
 		return Bot{}, err
 	}
 
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionBotUpdate,
+		EntityType: auditEntityBot,
+		EntityID:   fluxBot.Name,
+		Before:     botAuditSnapshot(oldBot),
+		After:      botAuditSnapshot(fluxBot),
+	})
+	if err != nil {
+		return Bot{}, err
+	}
+
 	// refresh bots
 	if err = sub.RefreshBots(ctx); err != nil {
 		sub.logger.Error(
@@ -302,22 +308,29 @@ func (sub *SubmissionService) UpdateBot(ctx context.Context, bot Bot) (Bot, erro
 }
 
 func (sub *SubmissionService) DeleteBot(ctx context.Context, name string) error {
-	// get claims
-	claims, err := service.GetClaimsFromContext(ctx)
+	// authorize
+	err := sub.UserService.Authorize(ctx, user_service.PermBotManage, user_service.Resource{})
 	if err != nil {
 		return err
 	}
 
-	// authorize
-	if err = sub.UserService.AuthorizeUserRole(
-		ctx, user_service.RoleManager,
-		fmt.Sprintf("user %s tried for manager access to delete a bot", claims.UserName),
-	); err != nil {
+	// create a new transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sub.DB.WithTx(tx)
+
+	// bot before the change
+	oldBot, err := sub.lockBot(ctx, qtx, name)
+	if err != nil {
 		return err
 	}
 
 	// delete bot
-	err = sub.DB.DeleteBot(ctx, name)
+	err = qtx.DeleteBot(ctx, name)
 	if err != nil {
 		err = flux_errors.HandleDBErrors(
 			err,
@@ -327,6 +340,27 @@ func (sub *SubmissionService) DeleteBot(ctx context.Context, name string) error
 		return err
 	}
 
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionBotDelete,
+		EntityType: auditEntityBot,
+		EntityID:   name,
+		Before:     botAuditSnapshot(oldBot),
+	})
+	if err != nil {
+		return err
+	}
+
+	// commit transaction
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf(
+			"%w, cannot commit transaction after deleting bot %v, %w",
+			flux_errors.ErrInternal,
+			name,
+			err,
+		)
+	}
+
 	// refresh bots
 	if err = sub.RefreshBots(ctx); err != nil {
 		sub.logger.Errorf(
@@ -339,17 +373,8 @@ func (sub *SubmissionService) DeleteBot(ctx context.Context, name string) error
 }
 
 func (sub *SubmissionService) GetBots(ctx context.Context) ([]Bot, error) {
-	// get claims
-	claims, err := service.GetClaimsFromContext(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	// authorize
-	if err = sub.UserService.AuthorizeUserRole(
-		ctx, user_service.RoleManager,
-		fmt.Sprintf("user %s tried for manager access to see bots", claims.UserName),
-	); err != nil {
+	if err := sub.UserService.Authorize(ctx, user_service.PermBotManage, user_service.Resource{}); err != nil {
 		return nil, err
 	}
 
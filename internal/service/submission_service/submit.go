@@ -27,10 +27,23 @@ func (s *SubmissionService) Submit(
 		return err
 	}
 
+	// virtual participations are always bound to a contest
+	if req.VirtualParticipationID != nil && req.ContestID == nil {
+		return fmt.Errorf(
+			"%w, contest_id is required for a virtual participation submission",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
 	// if the contest is not nil, ask the contest if the user is
 	// able to submit to the contest
 	if req.ContestID != nil {
-		err := s.ContestService.CanSubmit(ctx, req.ProblemID, *req.ContestID)
+		err := s.ContestService.CanSubmit(
+			ctx,
+			req.ProblemID,
+			*req.ContestID,
+			req.VirtualParticipationID,
+		)
 		if err != nil {
 			return err
 		}
@@ -90,11 +103,12 @@ func (s *SubmissionService) Submit(
 	dbSub, err := s.DB.InsertSubmission(
 		ctx,
 		database.InsertSubmissionParams{
-			SubmittedBy: claims.UserId,
-			ContestID:   req.ContestID,
-			ProblemID:   req.ProblemID,
-			Solution:    json.RawMessage(bytes),
-			State:       SubStatusFluxQueued,
+			SubmittedBy:            claims.UserId,
+			ContestID:              req.ContestID,
+			ProblemID:              req.ProblemID,
+			Solution:               json.RawMessage(bytes),
+			State:                  SubStatusFluxQueued,
+			VirtualParticipationID: req.VirtualParticipationID,
 		},
 	)
 	if err != nil {
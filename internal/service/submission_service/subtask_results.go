@@ -0,0 +1,182 @@
+package submission_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/problem_service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// RecordSubtaskResults stores the per subtask verdicts reported by the judge
+// and records the sum of the points of the awarded subtasks as the score of the
+// submission, which the standings take. Subtasks missing from the results are
+// considered failed.
+func (sub *SubmissionService) RecordSubtaskResults(
+	ctx context.Context,
+	request RecordSubtaskResultsRequest,
+) (SubtaskScore, error) {
+	// validate
+	err := service.ValidateInput(request)
+	if err != nil {
+		return SubtaskScore{}, err
+	}
+
+	// only the judge reports verdicts
+	err = sub.UserService.Authorize(ctx, user_service.PermSubmissionJudge, user_service.Resource{})
+	if err != nil {
+		return SubtaskScore{}, err
+	}
+
+	subID, results := request.SubmissionID, request.Results
+	ctx = context.WithValue(ctx, problem_service.InternalProblemQuery, struct{}{})
+
+	// get the submission
+	dbSub, err := sub.DB.GetSubmissionByID(ctx, subID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot get submission with id %v from db", subID),
+		)
+		return SubtaskScore{}, err
+	}
+
+	// get the subtasks of the problem
+	subtasks, err := sub.ProblemService.GetProblemSubtasks(ctx, dbSub.ProblemID)
+	if err != nil {
+		return SubtaskScore{}, err
+	}
+	if len(subtasks) == 0 {
+		return SubtaskScore{}, fmt.Errorf(
+			"%w, problem %v has no subtasks",
+			flux_errors.ErrInvalidRequest,
+			dbSub.ProblemID,
+		)
+	}
+
+	// every reported subtask must belong to the problem
+	known := make(map[int32]bool, len(subtasks))
+	for _, subtask := range subtasks {
+		known[subtask.SubtaskID] = true
+	}
+	passed := make(map[int32]bool, len(results))
+	for _, result := range results {
+		if !known[result.SubtaskID] {
+			return SubtaskScore{}, fmt.Errorf(
+				"%w, problem %v has no subtask %d",
+				flux_errors.ErrInvalidRequest,
+				dbSub.ProblemID,
+				result.SubtaskID,
+			)
+		}
+		if _, ok := passed[result.SubtaskID]; ok {
+			return SubtaskScore{}, fmt.Errorf(
+				"%w, subtask %d is reported more than once",
+				flux_errors.ErrInvalidRequest,
+				result.SubtaskID,
+			)
+		}
+		passed[result.SubtaskID] = result.Passed
+	}
+	score, _ := problem_service.ScoreSubtasks(subtasks, passed)
+	subtaskScore := SubtaskScore{
+		SubmissionID: subID,
+		Score:        score,
+		Results:      make([]SubtaskResult, 0, len(subtasks)),
+	}
+	for _, subtask := range subtasks {
+		subtaskScore.Results = append(subtaskScore.Results, SubtaskResult{
+			SubtaskID: subtask.SubtaskID,
+			Passed:    passed[subtask.SubtaskID],
+		})
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return SubtaskScore{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := sub.DB.WithTx(tx)
+
+	// replace the verdicts of an earlier evaluation
+	err = qtx.DeleteSubtaskResults(ctx, subID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot delete subtask results of submission with id %v", subID),
+		)
+		return SubtaskScore{}, err
+	}
+	for _, subtask := range subtasks {
+		err = qtx.CreateSubtaskResult(
+			ctx,
+			database.CreateSubtaskResultParams{
+				SubmissionID: subID,
+				SubtaskID:    subtask.SubtaskID,
+				Passed:       passed[subtask.SubtaskID],
+			},
+		)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf(
+					"cannot store result of subtask %d of submission with id %v",
+					subtask.SubtaskID,
+					subID,
+				),
+			)
+			return SubtaskScore{}, err
+		}
+	}
+
+	err = qtx.SetSubmissionScore(
+		ctx,
+		database.SetSubmissionScoreParams{
+			ID:    subID,
+			Score: &score,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot set score of submission with id %v", subID),
+		)
+		return SubtaskScore{}, err
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionSubmissionSubtaskScore,
+		EntityType: auditEntitySubmission,
+		EntityID:   subID,
+		Before:     dbSub.Score,
+		After:      subtaskScore,
+	})
+	if err != nil {
+		return SubtaskScore{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return SubtaskScore{}, fmt.Errorf(
+			"%w, cannot commit transaction after recording subtask results of submission with id %v, %w",
+			flux_errors.ErrInternal,
+			subID,
+			err,
+		)
+	}
+
+	sub.logger.Infof("recorded score %d of submission %v", score, subID)
+	return subtaskScore, nil
+}
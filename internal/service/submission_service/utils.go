@@ -175,6 +175,7 @@ func dbSubmissionToFluxSubmission(sub database.Submission) (fluxSubmission, erro
 		State:        sub.State,
 		SubmitedAt:   sub.SubmittedAt,
 		UpdatedAt:    sub.UpdatedAt,
+		Score:        sub.Score,
 	}, nil
 }
 
@@ -246,3 +247,35 @@ func dbBotToFluxBot(dbBot database.Bot) (Bot, error) {
 	}, nil
 
 }
+
+// botAuditSnapshot drops the cookies of the bot, they are its credentials
+// and must not end up in the audit log
+func botAuditSnapshot(bot Bot) Bot {
+	return Bot{
+		Name:     bot.Name,
+		Platform: bot.Platform,
+	}
+}
+
+// lockBot returns the bot for an update in the transaction, without its
+// cookies as only its audit snapshot is needed
+func (sub *SubmissionService) lockBot(
+	ctx context.Context,
+	qtx *database.Queries,
+	name string,
+) (Bot, error) {
+	dbBot, err := qtx.LockBot(ctx, name)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot get bot %v from db", name),
+		)
+		return Bot{}, err
+	}
+
+	return Bot{
+		Name:     dbBot.Name,
+		Platform: dbBot.Platform,
+	}, nil
+}
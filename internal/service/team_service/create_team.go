@@ -0,0 +1,85 @@
+package team_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// CreateTeam creates a team with the user as its captain and only member
+func (t *TeamService) CreateTeam(
+	ctx context.Context,
+	team Team,
+) (Team, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return Team{}, err
+	}
+
+	// validate
+	err = service.ValidateInput(team)
+	if err != nil {
+		return Team{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return Team{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
+	dbTeam, err := qtx.CreateTeam(
+		ctx,
+		database.CreateTeamParams{
+			Name:      team.Name,
+			CaptainID: claims.UserId,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot create team %s of user %s", team.Name, claims.UserName),
+		)
+		return Team{}, err
+	}
+
+	// captain is also a member
+	_, err = qtx.AddTeamMember(
+		ctx,
+		database.AddTeamMemberParams{
+			TeamID: dbTeam.ID,
+			UserID: claims.UserId,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot add captain %s to team %v", claims.UserName, dbTeam.ID),
+		)
+		return Team{}, err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		return Team{}, fmt.Errorf(
+			"%w, cannot commit transaction after creating team %s, %w",
+			flux_errors.ErrInternal,
+			team.Name,
+			err,
+		)
+	}
+
+	return t.GetTeamByID(ctx, dbTeam.ID)
+}
@@ -0,0 +1,64 @@
+package team_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+func (t *TeamService) GetTeamByID(
+	ctx context.Context,
+	teamID uuid.UUID,
+) (Team, error) {
+	dbTeam, err := t.DB.GetTeamByID(ctx, teamID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch team with id %v", teamID),
+		)
+		return Team{}, err
+	}
+
+	team := dbTeamToServiceTeam(dbTeam)
+	team.Members, err = t.getTeamMembers(ctx, teamID)
+	if err != nil {
+		return Team{}, err
+	}
+
+	return team, nil
+}
+
+// GetUserTeams returns the teams the user is a member of
+func (t *TeamService) GetUserTeams(ctx context.Context) ([]Team, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dbTeams, err := t.DB.GetTeamsByUser(ctx, claims.UserId)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch teams of user %s", claims.UserName),
+		)
+		return nil, err
+	}
+
+	teams := make([]Team, 0, len(dbTeams))
+	for _, dbTeam := range dbTeams {
+		team := dbTeamToServiceTeam(dbTeam)
+		team.Members, err = t.getTeamMembers(ctx, dbTeam.ID)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+
+	return teams, nil
+}
@@ -0,0 +1,199 @@
+package team_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+func (t *TeamService) InviteUser(
+	ctx context.Context,
+	request InviteUserRequest,
+) (TeamInvitation, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return TeamInvitation{}, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return TeamInvitation{}, err
+	}
+
+	// only the captain can invite
+	_, err = t.authorizeTeamCaptain(
+		ctx,
+		request.TeamID,
+		fmt.Sprintf("user %s tried to invite users to team %v", claims.UserName, request.TeamID),
+	)
+	if err != nil {
+		return TeamInvitation{}, err
+	}
+
+	// get the invited user
+	user, err := t.UserServiceConfig.FetchUserByUserName(ctx, request.UserName)
+	if err != nil {
+		return TeamInvitation{}, err
+	}
+
+	// members need not be invited again
+	isMember, err := t.DB.IsUserTeamMember(
+		ctx,
+		database.IsUserTeamMemberParams{
+			TeamID: request.TeamID,
+			UserID: user.UserID,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot check if user %s is a member of team %v", user.UserName, request.TeamID),
+		)
+		return TeamInvitation{}, err
+	}
+	if isMember {
+		return TeamInvitation{}, fmt.Errorf(
+			"%w, user is already a member of the team",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	dbInvitation, err := t.DB.CreateTeamInvitation(
+		ctx,
+		database.CreateTeamInvitationParams{
+			TeamID:    request.TeamID,
+			UserID:    user.UserID,
+			InvitedBy: claims.UserId,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot invite user %s to team %v", user.UserName, request.TeamID),
+		)
+		return TeamInvitation{}, err
+	}
+
+	return dbInvitationToServiceInvitation(dbInvitation), nil
+}
+
+// GetUserInvitations returns the invitations received by the user
+func (t *TeamService) GetUserInvitations(ctx context.Context) ([]TeamInvitation, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dbInvitations, err := t.DB.GetUserTeamInvitations(ctx, claims.UserId)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch team invitations of user %s", claims.UserName),
+		)
+		return nil, err
+	}
+
+	res := make([]TeamInvitation, 0, len(dbInvitations))
+	for _, dbInvitation := range dbInvitations {
+		res = append(res, dbInvitationToServiceInvitation(dbInvitation))
+	}
+
+	return res, nil
+}
+
+// RespondToInvitation makes the user a member of the team if accepted.
+// The invitation is removed either way.
+func (t *TeamService) RespondToInvitation(
+	ctx context.Context,
+	request RespondToInvitationRequest,
+) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
+	// the invitation must exist
+	_, err = qtx.GetTeamInvitation(
+		ctx,
+		database.GetTeamInvitationParams{
+			TeamID: request.TeamID,
+			UserID: claims.UserId,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch invitation of user %s to team %v", claims.UserName, request.TeamID),
+		)
+		return err
+	}
+
+	err = qtx.DeleteTeamInvitation(
+		ctx,
+		database.DeleteTeamInvitationParams{
+			TeamID: request.TeamID,
+			UserID: claims.UserId,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot delete invitation of user %s to team %v", claims.UserName, request.TeamID),
+		)
+		return err
+	}
+
+	if request.Accept {
+		_, err = qtx.AddTeamMember(
+			ctx,
+			database.AddTeamMemberParams{
+				TeamID: request.TeamID,
+				UserID: claims.UserId,
+			},
+		)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot add user %s to team %v", claims.UserName, request.TeamID),
+			)
+			return err
+		}
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf(
+			"%w, cannot commit transaction after responding to invitation of team %v, %w",
+			flux_errors.ErrInternal,
+			request.TeamID,
+			err,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package team_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// RemoveTeamMember removes a member from the team. Captain can remove anyone
+// but themselves, other members can only leave the team.
+func (t *TeamService) RemoveTeamMember(
+	ctx context.Context,
+	teamID uuid.UUID,
+	userName string,
+) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// get the team
+	dbTeam, err := t.DB.GetTeamByID(ctx, teamID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch team with id %v", teamID),
+		)
+		return err
+	}
+
+	// get the member
+	user, err := t.UserServiceConfig.FetchUserByUserName(ctx, userName)
+	if err != nil {
+		return err
+	}
+
+	// authorize
+	if user.UserID != claims.UserId && dbTeam.CaptainID != claims.UserId {
+		log.Warnf(
+			"user %s tried to remove user %s from team %v",
+			claims.UserName,
+			userName,
+			teamID,
+		)
+		return flux_errors.ErrUnAuthorized
+	}
+	if user.UserID == dbTeam.CaptainID {
+		return fmt.Errorf(
+			"%w, captain cannot leave the team, delete the team instead",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	err = t.DB.RemoveTeamMember(
+		ctx,
+		database.RemoveTeamMemberParams{
+			TeamID: teamID,
+			UserID: user.UserID,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot remove user %s from team %v", userName, teamID),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteTeam deletes the team along with its members and invitations.
+// Teams that have taken part in contests cannot be deleted.
+func (t *TeamService) DeleteTeam(
+	ctx context.Context,
+	teamID uuid.UUID,
+) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// only the captain can delete
+	_, err = t.authorizeTeamCaptain(
+		ctx,
+		teamID,
+		fmt.Sprintf("user %s tried to delete team %v", claims.UserName, teamID),
+	)
+	if err != nil {
+		return err
+	}
+
+	err = t.DB.DeleteTeam(ctx, teamID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot delete team %v", teamID),
+		)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package team_service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+var (
+	msgUniqueConstraint = map[string]string{
+		"uq_team_name":          "team with that name already exists",
+		"team_members_pkey":     "user is already a member of the team",
+		"team_invitations_pkey": "user is already invited to the team",
+	}
+
+	msgForeignKey = map[string]string{
+		"contest_team_members_team_id_fkey": "team has taken part in contests and cannot be deleted",
+	}
+
+	errMsgs = map[string]map[string]string{
+		flux_errors.CodeUniqueConstraint:     msgUniqueConstraint,
+		flux_errors.CodeForeignKeyConstraint: msgForeignKey,
+	}
+)
+
+type TeamService struct {
+	DB                *database.Queries
+	UserServiceConfig *user_service.UserService
+}
+
+type Team struct {
+	ID        uuid.UUID    `json:"team_id"`
+	Name      string       `json:"name" validate:"required,min=3,max=50"`
+	CaptainID uuid.UUID    `json:"captain_id"`
+	CreatedAt time.Time    `json:"created_at"`
+	Members   []TeamMember `json:"members"`
+}
+
+type TeamMember struct {
+	UserID   uuid.UUID `json:"user_id"`
+	UserName string    `json:"user_name"`
+	RollNo   string    `json:"roll_no"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type TeamInvitation struct {
+	TeamID    uuid.UUID `json:"team_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	InvitedBy uuid.UUID `json:"invited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type InviteUserRequest struct {
+	TeamID   uuid.UUID `json:"team_id"`
+	UserName string    `json:"user_name" validate:"required"`
+}
+
+type RespondToInvitationRequest struct {
+	TeamID uuid.UUID `json:"team_id"`
+	Accept bool      `json:"accept"`
+}
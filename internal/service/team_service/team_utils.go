@@ -0,0 +1,118 @@
+package team_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+func (t *TeamService) getTeamMembers(
+	ctx context.Context,
+	teamID uuid.UUID,
+) ([]TeamMember, error) {
+	dbMembers, err := t.DB.GetTeamMembers(ctx, teamID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch members of team %v", teamID),
+		)
+		return nil, err
+	}
+
+	// check for empty
+	if len(dbMembers) == 0 {
+		return make([]TeamMember, 0), nil
+	}
+
+	// fetch the user details
+	userIDs := make([]uuid.UUID, 0, len(dbMembers))
+	for _, dbMember := range dbMembers {
+		userIDs = append(userIDs, dbMember.UserID)
+	}
+	users, err := t.UserServiceConfig.GetUsersByFilters(
+		ctx,
+		user_service.GetUsersRequest{
+			UserIDs:    userIDs,
+			PageNumber: 1,
+			PageSize:   int32(len(userIDs)),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	usersMap := make(map[uuid.UUID]user_service.UserMetaData, len(users))
+	for _, user := range users {
+		usersMap[user.UserID] = user
+	}
+
+	members := make([]TeamMember, 0, len(dbMembers))
+	for _, dbMember := range dbMembers {
+		user, ok := usersMap[dbMember.UserID]
+		if !ok {
+			log.Warnf("user %v is a member of team %v but missing in fetched users", dbMember.UserID, teamID)
+		}
+		members = append(members, TeamMember{
+			UserID:   dbMember.UserID,
+			UserName: user.UserName,
+			RollNo:   user.RollNo,
+			JoinedAt: dbMember.JoinedAt.UTC(),
+		})
+	}
+
+	return members, nil
+}
+
+// authorizeTeamCaptain fetches the team and checks if the user is its captain
+func (t *TeamService) authorizeTeamCaptain(
+	ctx context.Context,
+	teamID uuid.UUID,
+	warnMessage string,
+) (database.Team, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return database.Team{}, err
+	}
+
+	dbTeam, err := t.DB.GetTeamByID(ctx, teamID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch team with id %v", teamID),
+		)
+		return database.Team{}, err
+	}
+
+	if dbTeam.CaptainID != claims.UserId {
+		log.Warn(warnMessage)
+		return database.Team{}, flux_errors.ErrUnAuthorized
+	}
+
+	return dbTeam, nil
+}
+
+func dbTeamToServiceTeam(dbTeam database.Team) Team {
+	return Team{
+		ID:        dbTeam.ID,
+		Name:      dbTeam.Name,
+		CaptainID: dbTeam.CaptainID,
+		CreatedAt: dbTeam.CreatedAt.UTC(),
+	}
+}
+
+func dbInvitationToServiceInvitation(dbInvitation database.TeamInvitation) TeamInvitation {
+	return TeamInvitation{
+		TeamID:    dbInvitation.TeamID,
+		UserID:    dbInvitation.UserID,
+		InvitedBy: dbInvitation.InvitedBy,
+		CreatedAt: dbInvitation.CreatedAt.UTC(),
+	}
+}
@@ -0,0 +1,639 @@
+package tournament_service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// SetAdvancementRule sets the rule deciding who advances from the round
+func (t *TournamentService) SetAdvancementRule(
+	ctx context.Context,
+	request AdvancementRule,
+) (AdvancementRule, error) {
+	// fetch claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return AdvancementRule{}, err
+	}
+
+	// authorize (only managers can decide the advancement)
+	err = t.UserServiceConfig.Authorize(ctx, user_service.PermTournamentManage, user_service.Resource{})
+	if err != nil {
+		return AdvancementRule{}, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return AdvancementRule{}, err
+	}
+	err = validateAdvancementRule(request)
+	if err != nil {
+		return AdvancementRule{}, err
+	}
+
+	// get the round
+	round, err := t.getRound(ctx, request.TournamentID, request.RoundNumber)
+	if err != nil {
+		return AdvancementRule{}, err
+	}
+
+	// resolve the manual picks
+	manualUserIDs := []uuid.UUID{}
+	if len(request.ManualUserNames) > 0 {
+		users, err := t.UserServiceConfig.GetUsersByFilters(
+			ctx,
+			user_service.GetUsersRequest{
+				UserNames:  request.ManualUserNames,
+				PageNumber: 1,
+				PageSize:   int32(len(request.ManualUserNames)),
+			},
+		)
+		if err != nil {
+			return AdvancementRule{}, err
+		}
+		found := make(map[string]bool, len(users))
+		for _, user := range users {
+			found[user.UserName] = true
+			manualUserIDs = append(manualUserIDs, user.UserID)
+		}
+		for _, userName := range request.ManualUserNames {
+			if !found[userName] {
+				return AdvancementRule{}, fmt.Errorf(
+					"%w, user %s does not exist",
+					flux_errors.ErrInvalidRequest,
+					userName,
+				)
+			}
+		}
+	}
+
+	// fetch the previous rule for the audit log
+	var prevRule *AdvancementRule
+	dbPrevRule, err := t.DB.GetRoundAdvancement(ctx, round.ID)
+	if err == nil {
+		rule, err := t.dbAdvancementToService(ctx, round, dbPrevRule)
+		if err != nil {
+			return AdvancementRule{}, err
+		}
+		prevRule = &rule
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch advancement rule of tournament round %v", round.ID),
+		)
+		return AdvancementRule{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return AdvancementRule{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
+	dbRule, err := qtx.SetRoundAdvancement(
+		ctx,
+		database.SetRoundAdvancementParams{
+			RoundID:       round.ID,
+			Rule:          database.AdvancementRule(request.Rule),
+			TopK:          request.TopK,
+			MinScore:      request.MinScore,
+			ManualUserIds: manualUserIDs,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot set advancement rule of tournament round %v", round.ID),
+		)
+		return AdvancementRule{}, err
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionTournamentSetAdvancementRule,
+		EntityType: auditEntityTournament,
+		EntityID:   request.TournamentID,
+		Before:     prevRule,
+		After:      request,
+	})
+	if err != nil {
+		return AdvancementRule{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return AdvancementRule{}, fmt.Errorf(
+			"%w, cannot commit transaction after setting advancement rule of tournament round %v, %w",
+			flux_errors.ErrInternal,
+			round.ID,
+			err,
+		)
+	}
+
+	log.Infof(
+		"user %s set advancement rule %s for round %v of tournament %v",
+		claims.UserName,
+		request.Rule,
+		request.RoundNumber,
+		request.TournamentID,
+	)
+
+	return t.dbAdvancementToService(ctx, round, dbRule)
+}
+
+func (t *TournamentService) GetAdvancementRule(
+	ctx context.Context,
+	tournamentID uuid.UUID,
+	roundNumber int32,
+) (AdvancementRule, error) {
+	// authorize
+	err := t.UserServiceConfig.Authorize(ctx, user_service.PermTournamentManage, user_service.Resource{})
+	if err != nil {
+		return AdvancementRule{}, err
+	}
+
+	round, err := t.getRound(ctx, tournamentID, roundNumber)
+	if err != nil {
+		return AdvancementRule{}, err
+	}
+
+	dbRule, err := t.getRoundAdvancement(ctx, round)
+	if err != nil {
+		return AdvancementRule{}, err
+	}
+
+	return t.dbAdvancementToService(ctx, round, dbRule)
+}
+
+// PreviewAdvancement evaluates the rule of the round against its final
+// standings without registering anyone
+func (t *TournamentService) PreviewAdvancement(
+	ctx context.Context,
+	request AdvanceRoundRequest,
+) (AdvancementPreview, error) {
+	// authorize
+	err := t.UserServiceConfig.Authorize(ctx, user_service.PermTournamentManage, user_service.Resource{})
+	if err != nil {
+		return AdvancementPreview{}, err
+	}
+
+	preview, _, err := t.evaluateAdvancement(ctx, request)
+	return preview, err
+}
+
+// AdvanceRound registers the qualifiers of the round into every contest of the
+// next round. Registrations of those contests are replaced, so advancing again
+// after changing the rule is safe.
+func (t *TournamentService) AdvanceRound(
+	ctx context.Context,
+	request AdvanceRoundRequest,
+) (AdvancementPreview, error) {
+	// fetch claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return AdvancementPreview{}, err
+	}
+
+	// authorize
+	err = t.UserServiceConfig.Authorize(ctx, user_service.PermTournamentManage, user_service.Resource{})
+	if err != nil {
+		return AdvancementPreview{}, err
+	}
+
+	preview, round, err := t.evaluateAdvancement(ctx, request)
+	if err != nil {
+		return AdvancementPreview{}, err
+	}
+
+	userNames := make([]string, 0, len(preview.Qualifiers))
+	for _, qualifier := range preview.Qualifiers {
+		userNames = append(userNames, qualifier.UserName)
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return AdvancementPreview{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
+	// register the qualifiers
+	newUserIDs := make(map[uuid.UUID][]uuid.UUID, len(preview.NextRoundContests))
+	for _, contestID := range preview.NextRoundContests {
+		newUserIDs[contestID], err = t.ContestServiceConfig.ReplaceContestUsers(
+			ctx, qtx, contestID, userNames,
+		)
+		if err != nil {
+			return AdvancementPreview{}, err
+		}
+	}
+
+	err = qtx.MarkRoundAdvanced(ctx, round.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot mark tournament round %v as advanced", round.ID),
+		)
+		return AdvancementPreview{}, err
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionTournamentAdvance,
+		EntityType: auditEntityTournament,
+		EntityID:   request.TournamentID,
+		After:      preview,
+	})
+	if err != nil {
+		return AdvancementPreview{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return AdvancementPreview{}, fmt.Errorf(
+			"%w, cannot commit transaction after advancing tournament round %v, %w",
+			flux_errors.ErrInternal,
+			round.ID,
+			err,
+		)
+	}
+
+	log.Infof(
+		"user %s advanced %d qualifiers from round %v of tournament %v",
+		claims.UserName,
+		len(userNames),
+		request.RoundNumber,
+		request.TournamentID,
+	)
+
+	// confirm the registrations only after they are committed
+	for contestID, userIDs := range newUserIDs {
+		t.ContestServiceConfig.NotifyRegistered(ctx, contestID, userIDs)
+	}
+
+	return preview, nil
+}
+
+// SelectQualifiers applies a standings based rule on the final standings of the
+// contests of a round. Members of a team qualify together. A user qualifying
+// more than once is listed once, with the best result.
+func SelectQualifiers(rule AdvancementRule, standings []contest_service.ContestStandings) []Qualifier {
+	var entries []Qualifier
+	for _, contestStandings := range standings {
+		contestID := contestStandings.ContestID
+		for _, row := range contestStandings.Rows {
+			if row.IsVirtual {
+				continue
+			}
+			entry := Qualifier{
+				UserID:    row.UserID,
+				UserName:  row.UserName,
+				ContestID: &contestID,
+				Rank:      row.Rank,
+				Score:     row.Score,
+				Penalty:   row.Penalty,
+			}
+			if row.TeamID == nil {
+				entries = append(entries, entry)
+				continue
+			}
+			for _, memberID := range row.MemberIDs {
+				entry.UserID = memberID
+				entry.UserName = ""
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	// best result first, so that the first entry of a user is kept
+	sortQualifiers(entries)
+
+	var qualifiers []Qualifier
+	seen := make(map[uuid.UUID]bool, len(entries))
+	switch rule.Rule {
+	case AdvancementTopOverall:
+		// rank everyone together, equal results share the rank
+		for _, entry := range entries {
+			if seen[entry.UserID] {
+				continue
+			}
+			seen[entry.UserID] = true
+			n := len(qualifiers)
+			if n > 0 && entry.Score == qualifiers[n-1].Score && entry.Penalty == qualifiers[n-1].Penalty {
+				entry.Rank = qualifiers[n-1].Rank
+			} else {
+				entry.Rank = int32(len(seen))
+			}
+			if rule.TopK == nil || entry.Rank > *rule.TopK {
+				break
+			}
+			qualifiers = append(qualifiers, entry)
+		}
+	case AdvancementTopPerContest:
+		for _, entry := range entries {
+			if seen[entry.UserID] || rule.TopK == nil || entry.Rank > *rule.TopK {
+				continue
+			}
+			seen[entry.UserID] = true
+			qualifiers = append(qualifiers, entry)
+		}
+	case AdvancementScoreThreshold:
+		for _, entry := range entries {
+			if seen[entry.UserID] || rule.MinScore == nil || entry.Score < *rule.MinScore {
+				continue
+			}
+			seen[entry.UserID] = true
+			qualifiers = append(qualifiers, entry)
+		}
+	}
+
+	return qualifiers
+}
+
+func sortQualifiers(qualifiers []Qualifier) {
+	sort.SliceStable(qualifiers, func(i, j int) bool {
+		if qualifiers[i].Score != qualifiers[j].Score {
+			return qualifiers[i].Score > qualifiers[j].Score
+		}
+		if qualifiers[i].Penalty != qualifiers[j].Penalty {
+			return qualifiers[i].Penalty < qualifiers[j].Penalty
+		}
+		return qualifiers[i].Rank < qualifiers[j].Rank
+	})
+}
+
+// evaluateAdvancement computes the qualifiers of the round along with the
+// contests of the next round they are registered into
+func (t *TournamentService) evaluateAdvancement(
+	ctx context.Context,
+	request AdvanceRoundRequest,
+) (AdvancementPreview, database.GetTournamentRoundByNumberRow, error) {
+	round, err := t.getRound(ctx, request.TournamentID, request.RoundNumber)
+	if err != nil {
+		return AdvancementPreview{}, round, err
+	}
+
+	dbRule, err := t.getRoundAdvancement(ctx, round)
+	if err != nil {
+		return AdvancementPreview{}, round, err
+	}
+	rule, err := t.dbAdvancementToService(ctx, round, dbRule)
+	if err != nil {
+		return AdvancementPreview{}, round, err
+	}
+
+	// qualifiers are registered into the next round
+	nextRound, err := t.getRound(ctx, request.TournamentID, request.RoundNumber+1)
+	if err != nil {
+		if errors.Is(err, flux_errors.ErrNotFound) {
+			return AdvancementPreview{}, round, fmt.Errorf(
+				"%w, round %v does not exist yet, create it before advancing",
+				flux_errors.ErrInvalidRequest,
+				request.RoundNumber+1,
+			)
+		}
+		return AdvancementPreview{}, round, err
+	}
+	nextContests, err := t.DB.GetTournamentContests(ctx, nextRound.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch contests of tournament round with id %v", nextRound.ID),
+		)
+		return AdvancementPreview{}, round, err
+	}
+	if len(nextContests) == 0 {
+		return AdvancementPreview{}, round, fmt.Errorf(
+			"%w, round %v has no contests to register the qualifiers into",
+			flux_errors.ErrInvalidRequest,
+			request.RoundNumber+1,
+		)
+	}
+
+	var qualifiers []Qualifier
+	if rule.Rule == AdvancementManual {
+		for _, userID := range dbRule.ManualUserIds {
+			qualifiers = append(qualifiers, Qualifier{UserID: userID})
+		}
+	} else {
+		standings, err := t.getRoundFinalStandings(ctx, round)
+		if err != nil {
+			return AdvancementPreview{}, round, err
+		}
+		qualifiers = SelectQualifiers(rule, standings)
+	}
+
+	err = t.fillQualifierNames(ctx, qualifiers)
+	if err != nil {
+		return AdvancementPreview{}, round, err
+	}
+
+	return AdvancementPreview{
+		TournamentID:      request.TournamentID,
+		RoundNumber:       request.RoundNumber,
+		Rule:              rule.Rule,
+		Qualifiers:        qualifiers,
+		NextRoundContests: nextContests,
+	}, round, nil
+}
+
+// getRoundFinalStandings returns the final standings of every contest of the
+// round. All the contests must have ended.
+func (t *TournamentService) getRoundFinalStandings(
+	ctx context.Context,
+	round database.GetTournamentRoundByNumberRow,
+) ([]contest_service.ContestStandings, error) {
+	contestIDs, err := t.DB.GetTournamentContests(ctx, round.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch contests of tournament round with id %v", round.ID),
+		)
+		return nil, err
+	}
+
+	standings := make([]contest_service.ContestStandings, 0, len(contestIDs))
+	for _, contestID := range contestIDs {
+		contest, err := t.ContestServiceConfig.GetContestByID(ctx, contestID)
+		if err != nil {
+			return nil, err
+		}
+		if !time.Now().After(contest.EndTime) {
+			return nil, fmt.Errorf(
+				"%w, contest %v of the round has not ended yet",
+				flux_errors.ErrInvalidRequest,
+				contest.ID,
+			)
+		}
+
+		contestStandings, err := t.ContestServiceConfig.GetFinalStandings(ctx, contest.ID)
+		if err != nil {
+			return nil, err
+		}
+		standings = append(standings, contestStandings)
+	}
+
+	return standings, nil
+}
+
+// fillQualifierNames sets the user names missing from the qualifiers, like
+// those of team members
+func (t *TournamentService) fillQualifierNames(ctx context.Context, qualifiers []Qualifier) error {
+	var missing []uuid.UUID
+	for _, qualifier := range qualifiers {
+		if qualifier.UserName == "" {
+			missing = append(missing, qualifier.UserID)
+		}
+	}
+
+	names, err := t.getUserNames(ctx, missing)
+	if err != nil {
+		return err
+	}
+	for i := range qualifiers {
+		if qualifiers[i].UserName != "" {
+			continue
+		}
+		name, ok := names[qualifiers[i].UserID]
+		if !ok {
+			return fmt.Errorf(
+				"%w, qualifier %v does not exist anymore",
+				flux_errors.ErrInvalidRequest,
+				qualifiers[i].UserID,
+			)
+		}
+		qualifiers[i].UserName = name
+	}
+
+	return nil
+}
+
+func (t *TournamentService) getRound(
+	ctx context.Context,
+	tournamentID uuid.UUID,
+	roundNumber int32,
+) (database.GetTournamentRoundByNumberRow, error) {
+	round, err := t.DB.GetTournamentRoundByNumber(
+		ctx,
+		database.GetTournamentRoundByNumberParams{
+			TournamentID: tournamentID,
+			RoundNumber:  roundNumber,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch round %v of tournament %v", roundNumber, tournamentID),
+		)
+		return round, err
+	}
+
+	return round, nil
+}
+
+func (t *TournamentService) getRoundAdvancement(
+	ctx context.Context,
+	round database.GetTournamentRoundByNumberRow,
+) (database.RoundAdvancement, error) {
+	dbRule, err := t.DB.GetRoundAdvancement(ctx, round.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch advancement rule of tournament round %v", round.ID),
+		)
+		if errors.Is(err, flux_errors.ErrNotFound) {
+			return dbRule, fmt.Errorf(
+				"%w, round %v has no advancement rule",
+				flux_errors.ErrNotFound,
+				round.RoundNumber,
+			)
+		}
+		return dbRule, err
+	}
+
+	return dbRule, nil
+}
+
+func (t *TournamentService) dbAdvancementToService(
+	ctx context.Context,
+	round database.GetTournamentRoundByNumberRow,
+	dbRule database.RoundAdvancement,
+) (AdvancementRule, error) {
+	rule := AdvancementRule{
+		TournamentID:    round.TournamentID,
+		RoundNumber:     round.RoundNumber,
+		Rule:            string(dbRule.Rule),
+		TopK:            dbRule.TopK,
+		MinScore:        dbRule.MinScore,
+		ManualUserNames: []string{},
+		AdvancedAt:      dbRule.AdvancedAt,
+	}
+	if len(dbRule.ManualUserIds) == 0 {
+		return rule, nil
+	}
+
+	users, err := t.UserServiceConfig.GetUsersByFilters(
+		ctx,
+		user_service.GetUsersRequest{
+			UserIDs:    dbRule.ManualUserIds,
+			PageNumber: 1,
+			PageSize:   int32(len(dbRule.ManualUserIds)),
+		},
+	)
+	if err != nil {
+		return AdvancementRule{}, err
+	}
+	for _, user := range users {
+		rule.ManualUserNames = append(rule.ManualUserNames, user.UserName)
+	}
+
+	return rule, nil
+}
+
+// validateAdvancementRule checks that the rule has the fields it needs
+func validateAdvancementRule(rule AdvancementRule) error {
+	switch rule.Rule {
+	case AdvancementTopOverall, AdvancementTopPerContest:
+		if rule.TopK == nil {
+			return fmt.Errorf("%w, top_k is required for %s rule", flux_errors.ErrInvalidRequest, rule.Rule)
+		}
+	case AdvancementScoreThreshold:
+		if rule.MinScore == nil {
+			return fmt.Errorf("%w, min_score is required for %s rule", flux_errors.ErrInvalidRequest, rule.Rule)
+		}
+	case AdvancementManual:
+		if len(rule.ManualUserNames) == 0 {
+			return fmt.Errorf("%w, manual rule needs at least one user", flux_errors.ErrInvalidRequest)
+		}
+	}
+	return nil
+}
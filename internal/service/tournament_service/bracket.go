@@ -0,0 +1,850 @@
+package tournament_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+	"github.com/tcp_snm/flux/internal/service/rating_service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// CreateBracket seeds the participants into a knockout bracket and draws its
+// first round. Top seeds get the byes when the participants are not a power of two.
+func (t *TournamentService) CreateBracket(
+	ctx context.Context,
+	request CreateBracketRequest,
+) (Bracket, error) {
+	// fetch claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return Bracket{}, err
+	}
+
+	// authorize (only managers can create a bracket)
+	err = t.UserServiceConfig.Authorize(ctx, user_service.PermTournamentManage, user_service.Resource{})
+	if err != nil {
+		return Bracket{}, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return Bracket{}, err
+	}
+	pool := make(map[int32]bool, len(request.ProblemPool))
+	for _, problemID := range request.ProblemPool {
+		if pool[problemID] {
+			return Bracket{}, fmt.Errorf(
+				"%w, problem %d is given more than once in the pool",
+				flux_errors.ErrInvalidRequest,
+				problemID,
+			)
+		}
+		pool[problemID] = true
+	}
+	if int(request.ProblemsPerMatch) > len(request.ProblemPool) {
+		return Bracket{}, fmt.Errorf(
+			"%w, pool has fewer problems than a match needs",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// get the tournament
+	tournament, err := t.GetTournamentByID(ctx, request.TournamentID)
+	if err != nil {
+		return Bracket{}, err
+	}
+
+	// a bracket replaces the rounds of the tournament
+	if tournament.Rounds > 0 {
+		return Bracket{}, fmt.Errorf(
+			"%w, tournament with rounds cannot have a bracket",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// resolve the participants
+	players, err := t.getBracketPlayers(ctx, request.UserNames)
+	if err != nil {
+		return Bracket{}, err
+	}
+
+	// order them by their seeds
+	if request.Seeding == BracketSeedingRating {
+		ratings := make(map[uuid.UUID]int32, len(players))
+		for _, player := range players {
+			ratings[player], err = t.getCurrentRating(ctx, player)
+			if err != nil {
+				return Bracket{}, err
+			}
+		}
+		sort.SliceStable(players, func(i, j int) bool {
+			return ratings[players[i]] > ratings[players[j]]
+		})
+	}
+
+	size := int32(1)
+	for size < int32(len(players)) {
+		size *= 2
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return Bracket{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
+	_, err = qtx.CreateBracket(
+		ctx,
+		database.CreateBracketParams{
+			TournamentID:         tournament.ID,
+			Seeding:              database.BracketSeeding(request.Seeding),
+			ProblemPool:          request.ProblemPool,
+			ProblemsPerMatch:     request.ProblemsPerMatch,
+			MatchDurationMinutes: request.MatchDurationMinutes,
+			Size:                 size,
+			CreatedBy:            claims.UserId,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot create bracket of tournament %v", tournament.ID),
+		)
+		return Bracket{}, err
+	}
+
+	for i, player := range players {
+		err = qtx.AddBracketParticipant(
+			ctx,
+			database.AddBracketParticipantParams{
+				TournamentID: tournament.ID,
+				UserID:       player,
+				Seed:         int32(i + 1),
+			},
+		)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot add participant %v to bracket of tournament %v", player, tournament.ID),
+			)
+			return Bracket{}, err
+		}
+	}
+
+	// draw the first round, a missing seed is a bye
+	order := BracketSeedOrder(int(size))
+	for i := 0; i < len(order); i += 2 {
+		params := database.CreateBracketMatchParams{
+			TournamentID: tournament.ID,
+			RoundNumber:  1,
+			MatchNumber:  int32(i/2 + 1),
+			Status:       database.BracketMatchStatusPending,
+		}
+		if int(order[i]) <= len(players) {
+			params.Player1ID = &players[order[i]-1]
+		}
+		if int(order[i+1]) <= len(players) {
+			params.Player2ID = &players[order[i+1]-1]
+		}
+		if params.Player2ID == nil {
+			params.WinnerID = params.Player1ID
+			params.Status = database.BracketMatchStatusFinished
+		}
+		err = qtx.CreateBracketMatch(ctx, params)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot create first round of bracket of tournament %v", tournament.ID),
+			)
+			return Bracket{}, err
+		}
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionTournamentCreateBracket,
+		EntityType: auditEntityTournament,
+		EntityID:   tournament.ID,
+		After:      request,
+	})
+	if err != nil {
+		return Bracket{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Bracket{}, fmt.Errorf(
+			"%w, cannot commit transaction after creating bracket of tournament %v, %w",
+			flux_errors.ErrInternal,
+			tournament.ID,
+			err,
+		)
+	}
+
+	log.Infof(
+		"user %s created a bracket of %d participants for tournament %v",
+		claims.UserName,
+		len(players),
+		tournament.ID,
+	)
+
+	return t.GetBracket(ctx, tournament.ID)
+}
+
+// GetBracket returns the participants and the matches of the bracket
+func (t *TournamentService) GetBracket(
+	ctx context.Context,
+	tournamentID uuid.UUID,
+) (Bracket, error) {
+	// check if the tournament exists
+	tournament, err := t.GetTournamentByID(ctx, tournamentID)
+	if err != nil {
+		return Bracket{}, err
+	}
+
+	dbBracket, err := t.DB.GetBracket(ctx, tournament.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch bracket of tournament %v", tournament.ID),
+		)
+		return Bracket{}, err
+	}
+
+	dbParticipants, err := t.DB.GetBracketParticipants(ctx, tournament.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch participants of bracket of tournament %v", tournament.ID),
+		)
+		return Bracket{}, err
+	}
+
+	dbMatches, err := t.DB.GetBracketMatches(ctx, tournament.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch matches of bracket of tournament %v", tournament.ID),
+		)
+		return Bracket{}, err
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(dbParticipants))
+	for _, dbParticipant := range dbParticipants {
+		userIDs = append(userIDs, dbParticipant.UserID)
+	}
+	names, err := t.getUserNames(ctx, userIDs)
+	if err != nil {
+		return Bracket{}, err
+	}
+
+	bracket := Bracket{
+		TournamentID:         dbBracket.TournamentID,
+		Seeding:              string(dbBracket.Seeding),
+		ProblemsPerMatch:     dbBracket.ProblemsPerMatch,
+		MatchDurationMinutes: dbBracket.MatchDurationMinutes,
+		Size:                 dbBracket.Size,
+		Rounds:               bracketRounds(dbBracket.Size),
+		Participants:         make([]BracketParticipant, 0, len(dbParticipants)),
+		Matches:              make([]BracketMatch, 0, len(dbMatches)),
+	}
+	for _, dbParticipant := range dbParticipants {
+		bracket.Participants = append(bracket.Participants, BracketParticipant{
+			UserID:   dbParticipant.UserID,
+			UserName: names[dbParticipant.UserID],
+			Seed:     dbParticipant.Seed,
+		})
+	}
+	for _, dbMatch := range dbMatches {
+		bracket.Matches = append(bracket.Matches, BracketMatch{
+			ID:           dbMatch.ID,
+			RoundNumber:  dbMatch.RoundNumber,
+			MatchNumber:  dbMatch.MatchNumber,
+			Player1ID:    dbMatch.Player1ID,
+			Player2ID:    dbMatch.Player2ID,
+			ContestID:    dbMatch.ContestID,
+			Player1Score: dbMatch.Player1Score,
+			Player2Score: dbMatch.Player2Score,
+			WinnerID:     dbMatch.WinnerID,
+			Status:       string(dbMatch.Status),
+		})
+		if dbMatch.RoundNumber == bracket.Rounds {
+			bracket.ChampionID = dbMatch.WinnerID
+		}
+	}
+
+	return bracket, nil
+}
+
+// StartBracketRound creates a private contest for every pending match of the
+// round. Each contest gets random problems from the pool of the bracket.
+func (t *TournamentService) StartBracketRound(
+	ctx context.Context,
+	request StartBracketRoundRequest,
+) (Bracket, error) {
+	// authorize (only managers can start a round)
+	err := t.UserServiceConfig.Authorize(ctx, user_service.PermTournamentManage, user_service.Resource{})
+	if err != nil {
+		return Bracket{}, err
+	}
+
+	tournament, err := t.GetTournamentByID(ctx, request.TournamentID)
+	if err != nil {
+		return Bracket{}, err
+	}
+
+	dbBracket, err := t.DB.GetBracket(ctx, tournament.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch bracket of tournament %v", tournament.ID),
+		)
+		return Bracket{}, err
+	}
+
+	dbMatches, err := t.getBracketRoundMatches(ctx, dbBracket, request.RoundNumber)
+	if err != nil {
+		return Bracket{}, err
+	}
+
+	var players []uuid.UUID
+	for _, dbMatch := range dbMatches {
+		if dbMatch.Player1ID != nil && dbMatch.Player2ID != nil {
+			players = append(players, *dbMatch.Player1ID, *dbMatch.Player2ID)
+		}
+	}
+	names, err := t.getUserNames(ctx, players)
+	if err != nil {
+		return Bracket{}, err
+	}
+
+	endTime := request.StartTime.Add(time.Duration(dbBracket.MatchDurationMinutes) * time.Minute)
+	for _, dbMatch := range dbMatches {
+		// byes and already started matches need no contest
+		if dbMatch.Status != database.BracketMatchStatusPending ||
+			dbMatch.Player1ID == nil || dbMatch.Player2ID == nil {
+			continue
+		}
+
+		// pick random problems from the pool
+		problems := make([]contest_service.ContestProblem, 0, dbBracket.ProblemsPerMatch)
+		for _, i := range rand.Perm(len(dbBracket.ProblemPool))[:dbBracket.ProblemsPerMatch] {
+			problems = append(problems, contest_service.ContestProblem{
+				ProblemId: dbBracket.ProblemPool[i],
+				Score:     bracketProblemScore,
+			})
+		}
+
+		startTime := request.StartTime
+		contest, err := t.ContestServiceConfig.CreateContest(
+			ctx,
+			contest_service.CreateContestRequest{
+				ContestDetails: contest_service.Contest{
+					Title:     bracketMatchTitle(tournament.Title, dbMatch.RoundNumber, dbMatch.MatchNumber),
+					StartTime: &startTime,
+					EndTime:   endTime,
+				},
+				RegisteredUsers: []string{names[*dbMatch.Player1ID], names[*dbMatch.Player2ID]},
+				ContestProblems: problems,
+			},
+		)
+		if err != nil {
+			return Bracket{}, err
+		}
+
+		err = t.DB.SetBracketMatchContest(
+			ctx,
+			database.SetBracketMatchContestParams{
+				ID:        dbMatch.ID,
+				ContestID: &contest.ID,
+			},
+		)
+		if err != nil {
+			err = flux_errors.HandleDBErrors(
+				err,
+				errMsgs,
+				fmt.Sprintf("cannot set contest %v of bracket match %v", contest.ID, dbMatch.ID),
+			)
+			return Bracket{}, err
+		}
+	}
+
+	// the match contests are recorded by the contest service
+	err = service.RecordAudit(ctx, t.DB, service.AuditEntry{
+		Action:     auditActionTournamentStartBracketRound,
+		EntityType: auditEntityTournament,
+		EntityID:   tournament.ID,
+		After:      request,
+	})
+	if err != nil {
+		return Bracket{}, err
+	}
+
+	return t.GetBracket(ctx, tournament.ID)
+}
+
+// ResolveBracketRound decides the scheduled matches of the round whose
+// contests have ended. It is needed only if the automatic resolution failed.
+func (t *TournamentService) ResolveBracketRound(
+	ctx context.Context,
+	request ResolveBracketRoundRequest,
+) (Bracket, error) {
+	// authorize (only managers can resolve a round)
+	err := t.UserServiceConfig.Authorize(ctx, user_service.PermTournamentManage, user_service.Resource{})
+	if err != nil {
+		return Bracket{}, err
+	}
+
+	dbBracket, err := t.DB.GetBracket(ctx, request.TournamentID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch bracket of tournament %v", request.TournamentID),
+		)
+		return Bracket{}, err
+	}
+
+	dbMatches, err := t.getBracketRoundMatches(ctx, dbBracket, request.RoundNumber)
+	if err != nil {
+		return Bracket{}, err
+	}
+
+	for _, dbMatch := range dbMatches {
+		if dbMatch.Status != database.BracketMatchStatusScheduled {
+			continue
+		}
+		err = t.resolveMatch(ctx, dbBracket, dbMatch)
+		if err != nil {
+			return Bracket{}, err
+		}
+	}
+
+	return t.GetBracket(ctx, dbBracket.TournamentID)
+}
+
+// ResolveMatchContest decides the bracket match played in the ended contest.
+// Contests that are not of any match are ignored.
+func (t *TournamentService) ResolveMatchContest(ctx context.Context, contestID uuid.UUID) error {
+	dbMatch, err := t.DB.GetBracketMatchByContest(ctx, &contestID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch bracket match of contest %v", contestID),
+		)
+		if errors.Is(err, flux_errors.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if dbMatch.Status != database.BracketMatchStatusScheduled {
+		return nil
+	}
+
+	dbBracket, err := t.DB.GetBracket(ctx, dbMatch.TournamentID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch bracket of tournament %v", dbMatch.TournamentID),
+		)
+		return err
+	}
+
+	return t.resolveMatch(ctx, dbBracket, dbMatch)
+}
+
+// BracketSeedOrder returns the seeds of a bracket of the given size in the
+// order of their slots, so that seeds 1 and 2 can only meet in the final.
+// Consecutive slots meet in the first round.
+func BracketSeedOrder(size int) []int32 {
+	order := []int32{1}
+	for len(order) < size {
+		next := make([]int32, 0, 2*len(order))
+		for _, seed := range order {
+			next = append(next, seed, int32(2*len(order)+1)-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// MatchWinner returns the player with the higher score. Ties are broken by the
+// lower penalty and then by the better seed.
+func MatchWinner(a, b MatchResult) uuid.UUID {
+	if a.Score != b.Score {
+		if a.Score > b.Score {
+			return a.UserID
+		}
+		return b.UserID
+	}
+	if a.Penalty != b.Penalty {
+		if a.Penalty < b.Penalty {
+			return a.UserID
+		}
+		return b.UserID
+	}
+	if a.Seed <= b.Seed {
+		return a.UserID
+	}
+	return b.UserID
+}
+
+// resolveMatch finishes the match with the final standings of its contest and
+// draws the next round once every match of the round is finished
+func (t *TournamentService) resolveMatch(
+	ctx context.Context,
+	dbBracket database.TournamentBracket,
+	dbMatch database.BracketMatch,
+) error {
+	if dbMatch.ContestID == nil || dbMatch.Player1ID == nil || dbMatch.Player2ID == nil {
+		return fmt.Errorf(
+			"%w, bracket match %v is scheduled without a contest or players",
+			flux_errors.ErrInternal,
+			dbMatch.ID,
+		)
+	}
+
+	contest, err := t.ContestServiceConfig.GetContestByID(ctx, *dbMatch.ContestID)
+	if err != nil {
+		return err
+	}
+	if !time.Now().After(contest.EndTime) {
+		return fmt.Errorf(
+			"%w, contest of match %d of round %d has not ended yet",
+			flux_errors.ErrInvalidRequest,
+			dbMatch.MatchNumber,
+			dbMatch.RoundNumber,
+		)
+	}
+
+	standings, err := t.ContestServiceConfig.GetFinalStandings(ctx, contest.ID)
+	if err != nil {
+		return err
+	}
+
+	dbParticipants, err := t.DB.GetBracketParticipants(ctx, dbBracket.TournamentID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch participants of bracket of tournament %v", dbBracket.TournamentID),
+		)
+		return err
+	}
+
+	// a player missing from the standings has not scored at all
+	player1 := MatchResult{UserID: *dbMatch.Player1ID}
+	player2 := MatchResult{UserID: *dbMatch.Player2ID}
+	for _, dbParticipant := range dbParticipants {
+		switch dbParticipant.UserID {
+		case player1.UserID:
+			player1.Seed = dbParticipant.Seed
+		case player2.UserID:
+			player2.Seed = dbParticipant.Seed
+		}
+	}
+	for _, row := range standings.Rows {
+		if row.IsVirtual {
+			continue
+		}
+		switch row.UserID {
+		case player1.UserID:
+			player1.Score, player1.Penalty = row.Score, row.Penalty
+		case player2.UserID:
+			player2.Score, player2.Penalty = row.Score, row.Penalty
+		}
+	}
+	winner := MatchWinner(player1, player2)
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
+	// matches of a round may end together
+	_, err = qtx.LockBracket(ctx, dbBracket.TournamentID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot lock bracket of tournament %v", dbBracket.TournamentID),
+		)
+		return err
+	}
+
+	roundMatches, err := qtx.GetBracketRoundMatches(
+		ctx,
+		database.GetBracketRoundMatchesParams{
+			TournamentID: dbBracket.TournamentID,
+			RoundNumber:  dbMatch.RoundNumber,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch round %d of bracket of tournament %v", dbMatch.RoundNumber, dbBracket.TournamentID),
+		)
+		return err
+	}
+
+	finished := true
+	winners := make([]*uuid.UUID, len(roundMatches))
+	for i, roundMatch := range roundMatches {
+		if roundMatch.ID == dbMatch.ID {
+			// resolved by someone else in the meantime
+			if roundMatch.Status != database.BracketMatchStatusScheduled {
+				return nil
+			}
+			roundMatch.WinnerID = &winner
+			roundMatch.Status = database.BracketMatchStatusFinished
+		}
+		winners[i] = roundMatch.WinnerID
+		if roundMatch.Status != database.BracketMatchStatusFinished {
+			finished = false
+		}
+	}
+
+	err = qtx.FinishBracketMatch(
+		ctx,
+		database.FinishBracketMatchParams{
+			ID:           dbMatch.ID,
+			Player1Score: &player1.Score,
+			Player2Score: &player2.Score,
+			WinnerID:     &winner,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot finish bracket match %v", dbMatch.ID),
+		)
+		return err
+	}
+
+	// draw the next round, winners of matches 2k-1 and 2k meet in match k
+	if finished && dbMatch.RoundNumber < bracketRounds(dbBracket.Size) {
+		for i := 0; i+1 < len(winners); i += 2 {
+			err = qtx.CreateBracketMatch(
+				ctx,
+				database.CreateBracketMatchParams{
+					TournamentID: dbBracket.TournamentID,
+					RoundNumber:  dbMatch.RoundNumber + 1,
+					MatchNumber:  int32(i/2 + 1),
+					Player1ID:    winners[i],
+					Player2ID:    winners[i+1],
+					Status:       database.BracketMatchStatusPending,
+				},
+			)
+			if err != nil {
+				err = flux_errors.HandleDBErrors(
+					err,
+					errMsgs,
+					fmt.Sprintf(
+						"cannot create round %d of bracket of tournament %v",
+						dbMatch.RoundNumber+1,
+						dbBracket.TournamentID,
+					),
+				)
+				return err
+			}
+		}
+	}
+
+	// record the change
+	finishedMatch := BracketMatch{
+		ID:           dbMatch.ID,
+		RoundNumber:  dbMatch.RoundNumber,
+		MatchNumber:  dbMatch.MatchNumber,
+		Player1ID:    dbMatch.Player1ID,
+		Player2ID:    dbMatch.Player2ID,
+		ContestID:    dbMatch.ContestID,
+		Player1Score: &player1.Score,
+		Player2Score: &player2.Score,
+		WinnerID:     &winner,
+		Status:       string(database.BracketMatchStatusFinished),
+	}
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionTournamentResolveMatch,
+		EntityType: auditEntityTournament,
+		EntityID:   dbBracket.TournamentID,
+		After:      finishedMatch,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf(
+			"%w, cannot commit transaction after finishing bracket match %v, %w",
+			flux_errors.ErrInternal,
+			dbMatch.ID,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// getBracketPlayers resolves the user names to their ids, keeping their order
+func (t *TournamentService) getBracketPlayers(
+	ctx context.Context,
+	userNames []string,
+) ([]uuid.UUID, error) {
+	seen := make(map[string]bool, len(userNames))
+	for _, userName := range userNames {
+		if seen[userName] {
+			return nil, fmt.Errorf(
+				"%w, user %s is given more than once",
+				flux_errors.ErrInvalidRequest,
+				userName,
+			)
+		}
+		seen[userName] = true
+	}
+
+	users, err := t.UserServiceConfig.GetUsersByFilters(
+		ctx,
+		user_service.GetUsersRequest{
+			UserNames:  userNames,
+			PageNumber: 1,
+			PageSize:   int32(len(userNames)),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]uuid.UUID, len(users))
+	for _, user := range users {
+		ids[user.UserName] = user.UserID
+	}
+
+	players := make([]uuid.UUID, 0, len(userNames))
+	for _, userName := range userNames {
+		id, ok := ids[userName]
+		if !ok {
+			return nil, fmt.Errorf(
+				"%w, user %s does not exist",
+				flux_errors.ErrInvalidRequest,
+				userName,
+			)
+		}
+		players = append(players, id)
+	}
+
+	return players, nil
+}
+
+// getCurrentRating returns the rating of the user, or the initial rating if
+// they have not been rated yet
+func (t *TournamentService) getCurrentRating(
+	ctx context.Context,
+	userID uuid.UUID,
+) (int32, error) {
+	rating, err := t.DB.GetUserCurrentRating(ctx, userID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch rating of user %v", userID),
+		)
+		if errors.Is(err, flux_errors.ErrNotFound) {
+			return rating_service.InitialRating, nil
+		}
+		return 0, err
+	}
+	return rating, nil
+}
+
+// getBracketRoundMatches returns the matches of a drawn round of the bracket
+func (t *TournamentService) getBracketRoundMatches(
+	ctx context.Context,
+	dbBracket database.TournamentBracket,
+	roundNumber int32,
+) ([]database.BracketMatch, error) {
+	if roundNumber < 1 || roundNumber > bracketRounds(dbBracket.Size) {
+		return nil, fmt.Errorf(
+			"%w, bracket has no round %d",
+			flux_errors.ErrInvalidRequest,
+			roundNumber,
+		)
+	}
+
+	dbMatches, err := t.DB.GetBracketRoundMatches(
+		ctx,
+		database.GetBracketRoundMatchesParams{
+			TournamentID: dbBracket.TournamentID,
+			RoundNumber:  roundNumber,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch round %d of bracket of tournament %v", roundNumber, dbBracket.TournamentID),
+		)
+		return nil, err
+	}
+	if len(dbMatches) == 0 {
+		return nil, fmt.Errorf(
+			"%w, round %d is not drawn yet, previous round has not finished",
+			flux_errors.ErrInvalidRequest,
+			roundNumber,
+		)
+	}
+
+	return dbMatches, nil
+}
+
+// bracketRounds returns the number of rounds of a bracket of the given size
+func bracketRounds(size int32) int32 {
+	rounds := int32(0)
+	for ; size > 1; size /= 2 {
+		rounds++
+	}
+	return rounds
+}
+
+// bracketMatchTitle names the contest of a match, fitting the title limit of
+// the contests
+func bracketMatchTitle(tournamentTitle string, roundNumber, matchNumber int32) string {
+	suffix := fmt.Sprintf(" - R%d M%d", roundNumber, matchNumber)
+	title := []rune(tournamentTitle)
+	if maxLen := 100 - len(suffix); len(title) > maxLen {
+		title = title[:maxLen]
+	}
+	return string(title) + suffix
+}
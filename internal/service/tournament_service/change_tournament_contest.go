@@ -18,22 +18,8 @@ func (t *TournamentService) ChangeTournamentContests(
 	ctx context.Context,
 	request ChangeTournamentContestsRequest,
 ) ([]contest_service.Contest, error) {
-	// fetch claims
-	claims, err := service.GetClaimsFromContext(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	// authorize (only managers can add contests to a tournament)
-	err = t.UserServiceConfig.AuthorizeUserRole(
-		ctx, user_service.RoleManager,
-		fmt.Sprintf(
-			"user %s tried to add a contest to a tournament %v in round %v",
-			claims.UserName,
-			request.TournamentID,
-			request.RoundNumber,
-		),
-	)
+	err := t.UserServiceConfig.Authorize(ctx, user_service.PermTournamentManage, user_service.Resource{})
 	if err != nil {
 		return nil, err
 	}
@@ -94,6 +80,17 @@ func (t *TournamentService) ChangeTournamentContests(
 	// prepare a new query tool
 	qtx := t.DB.WithTx(tx)
 
+	// fetch previous contests for the audit log
+	prevContestIDs, err := qtx.GetTournamentContests(ctx, latestRound.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch contests of tournament round with id %v", latestRound.ID),
+		)
+		return nil, err
+	}
+
 	// delete previous contests
 	err = qtx.DeleteTournamentContests(ctx, latestRound.ID)
 	if err != nil {
@@ -128,6 +125,23 @@ func (t *TournamentService) ChangeTournamentContests(
 		}
 	}
 
+	// record the change
+	prevContests := ChangeTournamentContestsRequest{
+		TournamentID: request.TournamentID,
+		RoundNumber:  request.RoundNumber,
+		ContestIDs:   prevContestIDs,
+	}
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionTournamentChangeContests,
+		EntityType: auditEntityTournament,
+		EntityID:   request.TournamentID,
+		Before:     prevContests,
+		After:      request,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	// commit the transaction
 	if err = tx.Commit(ctx); err != nil {
 		err = fmt.Errorf(
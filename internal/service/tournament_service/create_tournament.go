@@ -21,10 +21,7 @@ func (t *TournamentService) CreateTournament(
 	}
 
 	// authorize (only managers can create a tournament)
-	err = t.UserServiceConfig.AuthorizeUserRole(
-		ctx, user_service.RoleManager,
-		fmt.Sprintf("user %s tried to create a tournament", claims.UserName),
-	)
+	err = t.UserServiceConfig.Authorize(ctx, user_service.PermTournamentManage, user_service.Resource{})
 	if err != nil {
 		return Tournament{}, err
 	}
@@ -35,8 +32,20 @@ func (t *TournamentService) CreateTournament(
 		return Tournament{}, err
 	}
 
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return Tournament{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
 	// create tournament
-	dbTour, err := t.DB.CreateTournament(ctx, database.CreateTournamentParams{
+	dbTour, err := qtx.CreateTournament(ctx, database.CreateTournamentParams{
 		Title:       tournament.Title,
 		CreatedBy:   claims.UserId,
 		IsPublished: tournament.IsPublished,
@@ -50,12 +59,33 @@ func (t *TournamentService) CreateTournament(
 		return Tournament{}, err
 	}
 
-	// convert and return
-	return Tournament{
+	// convert
+	newTournament := Tournament{
 		Title:       dbTour.Title,
 		CreatedBy:   dbTour.CreatedBy,
 		ID:          dbTour.ID,
 		IsPublished: dbTour.IsPublished,
 		Rounds:      0,
-	}, nil
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionTournamentCreate,
+		EntityType: auditEntityTournament,
+		EntityID:   newTournament.ID,
+		After:      newTournament,
+	})
+	if err != nil {
+		return Tournament{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Tournament{}, fmt.Errorf(
+			"%w, cannot commit transaction after creating tournament, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+	}
+
+	return newTournament, nil
 }
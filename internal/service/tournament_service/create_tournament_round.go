@@ -2,6 +2,7 @@ package tournament_service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -22,10 +23,7 @@ func (t *TournamentService) CreateTournamentRound(
 	}
 
 	// authorize (only managers can create a tournament round)
-	err = t.UserServiceConfig.AuthorizeUserRole(
-		ctx, user_service.RoleManager,
-		fmt.Sprintf("user %s tried to create a tournament round", claims.UserName),
-	)
+	err = t.UserServiceConfig.Authorize(ctx, user_service.PermTournamentManage, user_service.Resource{})
 	if err != nil {
 		return TournamentRound{}, err
 	}
@@ -36,6 +34,23 @@ func (t *TournamentService) CreateTournamentRound(
 		return TournamentRound{}, err
 	}
 
+	// knockout tournaments are played in their bracket
+	_, err = t.DB.GetBracket(ctx, tournament.ID)
+	if err == nil {
+		return TournamentRound{}, fmt.Errorf(
+			"%w, tournament with a bracket cannot have rounds",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+	err = flux_errors.HandleDBErrors(
+		err,
+		errMsgs,
+		fmt.Sprintf("cannot fetch bracket of tournament %v", tournament.ID),
+	)
+	if !errors.Is(err, flux_errors.ErrNotFound) {
+		return TournamentRound{}, err
+	}
+
 	// check if previous round ended
 	endTime, err := t.DB.GetTournamentLatestRoundEndTime(ctx, tournamentRound.TournamentID)
 	if err != nil {
@@ -74,8 +89,20 @@ func (t *TournamentService) CreateTournamentRound(
 		return TournamentRound{}, err
 	}
 
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return TournamentRound{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
 	// create tournament round
-	dbRound, err := t.DB.CreateTournamentRound(ctx,
+	dbRound, err := qtx.CreateTournamentRound(ctx,
 		database.CreateTournamentRoundParams{
 			TournamentID: tournamentRound.TournamentID,
 			LockID:       tournamentRound.LockID,
@@ -93,13 +120,35 @@ func (t *TournamentService) CreateTournamentRound(
 		return TournamentRound{}, err
 	}
 
-	// return response
-	return TournamentRound{
+	round := TournamentRound{
 		ID:           dbRound.ID,
 		TournamentID: dbRound.TournamentID,
 		Title:        dbRound.Title,
 		RoundNumber:  dbRound.RoundNumber,
 		LockID:       dbRound.LockID,
 		CreatedBy:    dbRound.CreatedBy,
-	}, nil
+		IsPublished:  dbRound.IsPublished,
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionTournamentCreateRound,
+		EntityType: auditEntityTournament,
+		EntityID:   tournament.ID,
+		After:      round,
+	})
+	if err != nil {
+		return TournamentRound{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return TournamentRound{}, fmt.Errorf(
+			"%w, cannot commit transaction after creating round of tournament %v, %w",
+			flux_errors.ErrInternal,
+			tournament.ID,
+			err,
+		)
+	}
+
+	return round, nil
 }
@@ -0,0 +1,227 @@
+package tournament_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// DeleteTournament deletes the tournament with its rounds and bracket. It is
+// refused while any contest is still attached to the tournament.
+func (t *TournamentService) DeleteTournament(
+	ctx context.Context,
+	tournamentID uuid.UUID,
+) error {
+	// fetch claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// authorize (only managers can delete a tournament)
+	err = t.UserServiceConfig.Authorize(ctx, user_service.PermTournamentManage, user_service.Resource{})
+	if err != nil {
+		return err
+	}
+
+	// check if the tournament exists
+	tournament, err := t.GetTournamentByID(ctx, tournamentID)
+	if err != nil {
+		return err
+	}
+
+	// contests must be detached first
+	contests, err := t.DB.CountTournamentAttachedContests(ctx, tournament.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot count contests of tournament %v", tournament.ID),
+		)
+		return err
+	}
+	if contests > 0 {
+		return fmt.Errorf(
+			"%w, tournament still has %d contests attached",
+			flux_errors.ErrInvalidRequest,
+			contests,
+		)
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
+	// rounds restrict the deletion of the tournament
+	err = qtx.DeleteTournamentRounds(ctx, tournament.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot delete rounds of tournament %v", tournament.ID),
+		)
+		return err
+	}
+
+	err = qtx.DeleteTournament(ctx, tournament.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot delete tournament %v", tournament.ID),
+		)
+		return err
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionTournamentDelete,
+		EntityType: auditEntityTournament,
+		EntityID:   tournament.ID,
+		Before:     tournament,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction while deleting tournament %v, %w",
+			flux_errors.ErrInternal,
+			tournament.ID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	log.Infof("user %s deleted tournament %v", claims.UserName, tournament.ID)
+	return nil
+}
+
+// DeleteTournamentRound deletes a round without contests and moves the later
+// rounds up by one
+func (t *TournamentService) DeleteTournamentRound(
+	ctx context.Context,
+	tournamentID uuid.UUID,
+	roundNumber int32,
+) error {
+	// fetch claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// authorize (only managers can delete a tournament round)
+	err = t.UserServiceConfig.Authorize(ctx, user_service.PermTournamentManage, user_service.Resource{})
+	if err != nil {
+		return err
+	}
+
+	// get the round
+	round, err := t.getRound(ctx, tournamentID, roundNumber)
+	if err != nil {
+		return err
+	}
+
+	// contests must be detached first
+	contestIDs, err := t.DB.GetTournamentContests(ctx, round.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch contests of tournament round with id %v", round.ID),
+		)
+		return err
+	}
+	if len(contestIDs) > 0 {
+		return fmt.Errorf(
+			"%w, round still has %d contests attached",
+			flux_errors.ErrInvalidRequest,
+			len(contestIDs),
+		)
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
+	err = qtx.DeleteTournamentRound(ctx, round.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot delete tournament round with id %v", round.ID),
+		)
+		return err
+	}
+
+	// close the gap in the round numbers
+	err = qtx.ShiftTournamentRoundsDown(
+		ctx,
+		database.ShiftTournamentRoundsDownParams{
+			TournamentID: round.TournamentID,
+			RoundNumber:  round.RoundNumber,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot renumber rounds of tournament %v", round.TournamentID),
+		)
+		return err
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionTournamentDeleteRound,
+		EntityType: auditEntityTournament,
+		EntityID:   round.TournamentID,
+		Before:     round,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction while deleting tournament round %v, %w",
+			flux_errors.ErrInternal,
+			round.ID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	log.Infof(
+		"user %s deleted round %v of tournament %v",
+		claims.UserName,
+		round.RoundNumber,
+		round.TournamentID,
+	)
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service/contest_service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 func (t *TournamentService) GetTournamentRound(
@@ -41,6 +42,7 @@ func (t *TournamentService) GetTournamentRound(
 		RoundNumber:  round.RoundNumber,
 		LockID:       round.LockID,
 		CreatedBy:    round.CreatedBy,
+		IsPublished:  round.IsPublished,
 		LockAccess:   round.Access,
 		LockTimeout:  round.Timeout,
 	}
@@ -61,6 +63,7 @@ func (t *TournamentService) GetTournamentRound(
 		// authourize
 		if err = t.LockServiceConfig.AuthorizeLock(
 			ctx,
+			*serviceTournamentRound.LockID,
 			serviceTournamentRound.LockTimeout,
 			*serviceTournamentRound.LockAccess,
 			"",
@@ -69,6 +72,19 @@ func (t *TournamentService) GetTournamentRound(
 		}
 	}
 
+	// contests of unpublished rounds are visible only to managers
+	if !round.IsPublished {
+		allowed, err := t.UserServiceConfig.IsAllowed(
+			ctx, user_service.PermTournamentManage, user_service.Resource{},
+		)
+		if err != nil {
+			return TournamentRound{}, nil, err
+		}
+		if !allowed {
+			return serviceTournamentRound, nil, nil
+		}
+	}
+
 	// fetch contests
 	contestIDs, err := t.DB.GetTournamentContests(ctx, round.ID)
 	if err != nil {
@@ -16,11 +16,38 @@ var (
 		"fk_rounds_tournament": "Tournament does not exist.",
 	}
 
+	msgUniqueConstraint = map[string]string{
+		"tournaments_title_key":    "Tournament with the same title already exists.",
+		"tournament_brackets_pkey": "Tournament already has a bracket.",
+	}
+
 	errMsgs = map[string]map[string]string{
 		flux_errors.CodeForeignKeyConstraint: msgForeignKey,
+		flux_errors.CodeUniqueConstraint:     msgUniqueConstraint,
 	}
 )
 
+// actions recorded in the audit log
+const (
+	auditEntityTournament                   = "tournament"
+	auditActionTournamentCreate             = "tournament.create"
+	auditActionTournamentUpdate             = "tournament.update"
+	auditActionTournamentPublish            = "tournament.publish"
+	auditActionTournamentDelete             = "tournament.delete"
+	auditActionTournamentCreateRound        = "tournament.create_round"
+	auditActionTournamentUpdateRound        = "tournament.update_round"
+	auditActionTournamentPublishRound       = "tournament.publish_round"
+	auditActionTournamentDeleteRound        = "tournament.delete_round"
+	auditActionTournamentReorderRounds      = "tournament.reorder_rounds"
+	auditActionTournamentChangeContests     = "tournament.change_contests"
+	auditActionTournamentSetScoring         = "tournament.set_scoring"
+	auditActionTournamentSetAdvancementRule = "tournament.set_advancement_rule"
+	auditActionTournamentAdvance            = "tournament.advance"
+	auditActionTournamentCreateBracket      = "tournament.create_bracket"
+	auditActionTournamentStartBracketRound  = "tournament.start_bracket_round"
+	auditActionTournamentResolveMatch       = "tournament.resolve_match"
+)
+
 type TournamentService struct {
 	DB                   *database.Queries
 	ContestServiceConfig *contest_service.ContestService
@@ -43,6 +70,7 @@ type TournamentRound struct {
 	RoundNumber  int32      `json:"round_no"`
 	LockID       *uuid.UUID `json:"lock_id"`
 	CreatedBy    uuid.UUID  `json:"created_by"`
+	IsPublished  bool       `json:"is_published"`
 
 	// fields used internally
 	LockAccess *string `json:"-"`
@@ -56,9 +84,202 @@ type ChangeTournamentContestsRequest struct {
 	ContestIDs   []uuid.UUID `json:"contest_ids"`
 }
 
+type UpdateTournamentRequest struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	Title        string    `json:"title" validate:"min=5,max=100"`
+}
+
+type PublishTournamentRequest struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	IsPublished  bool      `json:"is_published"`
+}
+
+type UpdateTournamentRoundRequest struct {
+	TournamentID uuid.UUID  `json:"tournament_id"`
+	RoundNumber  int32      `json:"round_no"`
+	Title        string     `json:"title" validate:"min=5,max=100"`
+	LockID       *uuid.UUID `json:"lock_id"`
+}
+
+type PublishTournamentRoundRequest struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	RoundNumber  int32     `json:"round_no"`
+	IsPublished  bool      `json:"is_published"`
+}
+
+type ReorderTournamentRoundsRequest struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	// current numbers of the rounds in their new order
+	RoundNumbers []int32 `json:"round_nos" validate:"min=1"`
+}
+
 type GetTournamentRequest struct {
 	Title       string `json:"title"`
 	IsPublished *bool  `json:"is_published"`
 	PageSize    int32  `json:"page_size" validate:"min=0,max=10000"`
 	PageNumber  int32  `json:"page_number" validate:"min=1,max=10000"`
 }
+
+// rules deciding who advances from a round to the next one
+const (
+	AdvancementTopOverall     = string(database.AdvancementRuleTopOverall)
+	AdvancementTopPerContest  = string(database.AdvancementRuleTopPerContest)
+	AdvancementScoreThreshold = string(database.AdvancementRuleScoreThreshold)
+	AdvancementManual         = string(database.AdvancementRuleManual)
+)
+
+type AdvancementRule struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	RoundNumber  int32     `json:"round_no"`
+	Rule         string    `json:"rule" validate:"oneof=top_overall top_per_contest score_threshold manual"`
+	// participants ranked within top_k advance, ties included
+	TopK *int32 `json:"top_k" validate:"omitempty,min=1"`
+	// participants scoring at least min_score advance
+	MinScore *int32 `json:"min_score"`
+	// participants picked by the organizers
+	ManualUserNames []string   `json:"manual_user_names" validate:"max=10000"`
+	AdvancedAt      *time.Time `json:"advanced_at"`
+}
+
+type Qualifier struct {
+	UserID   uuid.UUID `json:"user_id"`
+	UserName string    `json:"user_name"`
+	// contest the user qualified from, nil for manual picks
+	ContestID *uuid.UUID `json:"contest_id"`
+	// overall rank for top_overall, rank in the contest otherwise
+	Rank    int32 `json:"rank"`
+	Score   int32 `json:"score"`
+	Penalty int64 `json:"penalty"`
+}
+
+type AdvancementPreview struct {
+	TournamentID      uuid.UUID   `json:"tournament_id"`
+	RoundNumber       int32       `json:"round_no"`
+	Rule              string      `json:"rule"`
+	Qualifiers        []Qualifier `json:"qualifiers"`
+	NextRoundContests []uuid.UUID `json:"next_round_contests"`
+}
+
+type AdvanceRoundRequest struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	RoundNumber  int32     `json:"round_no"`
+}
+
+type RoundScoring struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	RoundNumber  int32     `json:"round_no"`
+	// percentage of the contest scores added to the tournament points
+	Weight int32 `json:"weight" validate:"min=0,max=1000"`
+	// only the best contests of a participant in the round are counted
+	BestOf *int32 `json:"best_of" validate:"omitempty,min=1"`
+}
+
+// RoundStandings holds the final standings of the ended contests of a round
+// along with how the round is scored
+type RoundStandings struct {
+	RoundNumber int32
+	Weight      int32
+	BestOf      *int32
+	// participants of the next round, nil if the round has not advanced yet
+	Qualified map[uuid.UUID]bool
+	Contests  []contest_service.ContestStandings
+}
+
+type RoundResult struct {
+	RoundNumber int32   `json:"round_no"`
+	Points      float64 `json:"points"`
+	Penalty     int64   `json:"penalty"`
+	// contests counted towards the points
+	Contests int32 `json:"contests"`
+}
+
+type TournamentStandingsRow struct {
+	Rank     int32     `json:"rank"`
+	UserID   uuid.UUID `json:"user_id"`
+	UserName string    `json:"user_name"`
+	Points   float64   `json:"points"`
+	Penalty  int64     `json:"penalty"`
+	// last round the user took part in
+	RoundsReached int32 `json:"rounds_reached"`
+	// round after which the user did not advance, nil while still in
+	EliminatedInRound *int32        `json:"eliminated_in_round"`
+	Rounds            []RoundResult `json:"rounds"`
+
+	// points scaled by 100, compared instead of the float points
+	scaledPoints int64
+}
+
+type TournamentStandings struct {
+	TournamentID uuid.UUID                `json:"tournament_id"`
+	Rows         []TournamentStandingsRow `json:"rows"`
+}
+
+const (
+	BracketSeedingRating = string(database.BracketSeedingRating)
+	BracketSeedingManual = string(database.BracketSeedingManual)
+	// score of every problem of a match contest
+	bracketProblemScore = 100
+)
+
+type CreateBracketRequest struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	Seeding      string    `json:"seeding" validate:"oneof=rating manual"`
+	// participants, in the order of their seeds for manual seeding
+	UserNames            []string `json:"user_names" validate:"min=2,max=1024"`
+	ProblemPool          []int32  `json:"problem_pool" validate:"min=1"`
+	ProblemsPerMatch     int32    `json:"problems_per_match" validate:"min=1,max=26"`
+	MatchDurationMinutes int32    `json:"match_duration_minutes" validate:"min=1,max=1440"`
+}
+
+type StartBracketRoundRequest struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	RoundNumber  int32     `json:"round_no"`
+	StartTime    time.Time `json:"start_time"`
+}
+
+type ResolveBracketRoundRequest struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	RoundNumber  int32     `json:"round_no"`
+}
+
+type BracketParticipant struct {
+	UserID   uuid.UUID `json:"user_id"`
+	UserName string    `json:"user_name"`
+	Seed     int32     `json:"seed"`
+}
+
+type BracketMatch struct {
+	ID           uuid.UUID  `json:"match_id"`
+	RoundNumber  int32      `json:"round_no"`
+	MatchNumber  int32      `json:"match_no"`
+	Player1ID    *uuid.UUID `json:"player1_id"`
+	Player2ID    *uuid.UUID `json:"player2_id"`
+	ContestID    *uuid.UUID `json:"contest_id"`
+	Player1Score *int32     `json:"player1_score"`
+	Player2Score *int32     `json:"player2_score"`
+	WinnerID     *uuid.UUID `json:"winner_id"`
+	Status       string     `json:"status"`
+}
+
+// Bracket is the state of a knockout tournament. The winners of the matches
+// 2k-1 and 2k of a round meet in the match k of the next round.
+type Bracket struct {
+	TournamentID         uuid.UUID            `json:"tournament_id"`
+	Seeding              string               `json:"seeding"`
+	ProblemsPerMatch     int32                `json:"problems_per_match"`
+	MatchDurationMinutes int32                `json:"match_duration_minutes"`
+	Size                 int32                `json:"size"`
+	Rounds               int32                `json:"rounds"`
+	Participants         []BracketParticipant `json:"participants"`
+	Matches              []BracketMatch       `json:"matches"`
+	// winner of the final, nil until it is played
+	ChampionID *uuid.UUID `json:"champion_id"`
+}
+
+// MatchResult is the result of a player in the contest of a match
+type MatchResult struct {
+	UserID  uuid.UUID
+	Seed    int32
+	Score   int32
+	Penalty int64
+}
@@ -0,0 +1,378 @@
+package tournament_service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// SetRoundScoring sets how the results of the round count towards the
+// tournament standings
+func (t *TournamentService) SetRoundScoring(
+	ctx context.Context,
+	request RoundScoring,
+) (RoundScoring, error) {
+	// authorize (only managers can change the scoring)
+	err := t.UserServiceConfig.Authorize(ctx, user_service.PermTournamentManage, user_service.Resource{})
+	if err != nil {
+		return RoundScoring{}, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return RoundScoring{}, err
+	}
+
+	// get the round
+	round, err := t.getRound(ctx, request.TournamentID, request.RoundNumber)
+	if err != nil {
+		return RoundScoring{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return RoundScoring{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
+	dbScoring, err := qtx.SetTournamentRoundScoring(
+		ctx,
+		database.SetTournamentRoundScoringParams{
+			RoundID: round.ID,
+			Weight:  request.Weight,
+			BestOf:  request.BestOf,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot set scoring of tournament round %v", round.ID),
+		)
+		return RoundScoring{}, err
+	}
+
+	scoring := RoundScoring{
+		TournamentID: request.TournamentID,
+		RoundNumber:  request.RoundNumber,
+		Weight:       dbScoring.Weight,
+		BestOf:       dbScoring.BestOf,
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionTournamentSetScoring,
+		EntityType: auditEntityTournament,
+		EntityID:   request.TournamentID,
+		After:      scoring,
+	})
+	if err != nil {
+		return RoundScoring{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return RoundScoring{}, fmt.Errorf(
+			"%w, cannot commit transaction after setting scoring of tournament round %v, %w",
+			flux_errors.ErrInternal,
+			round.ID,
+			err,
+		)
+	}
+
+	return scoring, nil
+}
+
+// GetTournamentStandings aggregates the final standings of the ended contests
+// of every round. Rounds locked for the user are left out.
+func (t *TournamentService) GetTournamentStandings(
+	ctx context.Context,
+	tournamentID uuid.UUID,
+) (TournamentStandings, error) {
+	// check if the tournament exists
+	tournament, err := t.GetTournamentByID(ctx, tournamentID)
+	if err != nil {
+		return TournamentStandings{}, err
+	}
+
+	dbRounds, err := t.DB.GetTournamentRoundsWithScoring(ctx, tournament.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch rounds of tournament %v", tournament.ID),
+		)
+		return TournamentStandings{}, err
+	}
+
+	// unpublished rounds are left out for the users who are not managers
+	isManager, err := t.UserServiceConfig.IsAllowed(
+		ctx, user_service.PermTournamentManage, user_service.Resource{},
+	)
+	if err != nil {
+		return TournamentStandings{}, err
+	}
+
+	roundIDs := make(map[int32]uuid.UUID, len(dbRounds))
+	for _, dbRound := range dbRounds {
+		roundIDs[dbRound.RoundNumber] = dbRound.ID
+	}
+
+	rounds := make([]RoundStandings, 0, len(dbRounds))
+	for _, dbRound := range dbRounds {
+		if !dbRound.IsPublished && !isManager {
+			continue
+		}
+
+		// authorize if it has a lock
+		if dbRound.LockID != nil {
+			if dbRound.Access == nil {
+				err = fmt.Errorf(
+					"%w, tournament round with id %v has non-nil lock but access as nil",
+					flux_errors.ErrInternal,
+					dbRound.ID,
+				)
+				log.Error(err)
+				return TournamentStandings{}, err
+			}
+			if err = t.LockServiceConfig.AuthorizeLock(
+				ctx,
+				*dbRound.LockID,
+				dbRound.Timeout,
+				*dbRound.Access,
+				"",
+			); err != nil {
+				continue
+			}
+		}
+
+		round := RoundStandings{
+			RoundNumber: dbRound.RoundNumber,
+			Weight:      dbRound.Weight,
+			BestOf:      dbRound.BestOf,
+		}
+
+		// participants of the next round are the qualifiers of this one
+		nextRoundID, ok := roundIDs[dbRound.RoundNumber+1]
+		if dbRound.AdvancedAt != nil && ok {
+			participants, err := t.DB.GetRoundParticipantIDs(ctx, nextRoundID)
+			if err != nil {
+				err = flux_errors.HandleDBErrors(
+					err,
+					errMsgs,
+					fmt.Sprintf("cannot fetch participants of tournament round %v", nextRoundID),
+				)
+				return TournamentStandings{}, err
+			}
+			round.Qualified = make(map[uuid.UUID]bool, len(participants))
+			for _, participant := range participants {
+				round.Qualified[participant] = true
+			}
+		}
+
+		round.Contests, err = t.getEndedContestStandings(ctx, dbRound.ID)
+		if err != nil {
+			return TournamentStandings{}, err
+		}
+		rounds = append(rounds, round)
+	}
+
+	rows := AggregateStandings(rounds)
+
+	// team members do not have their names in the contest standings
+	var missing []uuid.UUID
+	for _, row := range rows {
+		if row.UserName == "" {
+			missing = append(missing, row.UserID)
+		}
+	}
+	names, err := t.getUserNames(ctx, missing)
+	if err != nil {
+		return TournamentStandings{}, err
+	}
+	for i := range rows {
+		if rows[i].UserName == "" {
+			rows[i].UserName = names[rows[i].UserID]
+		}
+	}
+
+	return TournamentStandings{
+		TournamentID: tournament.ID,
+		Rows:         rows,
+	}, nil
+}
+
+// AggregateStandings ranks the participants of the tournament. A participant
+// gets the weighted sum of their best contest scores of every round. Ties are
+// broken by the last round reached and then by the total penalty, rows equal
+// on all of them share the rank. Participants who took part in an advanced
+// round but are missing from its qualifiers are marked as eliminated.
+func AggregateStandings(rounds []RoundStandings) []TournamentStandingsRow {
+	sort.SliceStable(rounds, func(i, j int) bool {
+		return rounds[i].RoundNumber < rounds[j].RoundNumber
+	})
+
+	type contestResult struct {
+		score   int32
+		penalty int64
+	}
+
+	rowsByUser := make(map[uuid.UUID]*TournamentStandingsRow)
+	var order []uuid.UUID
+	qualified := make(map[int32]map[uuid.UUID]bool, len(rounds))
+
+	for _, round := range rounds {
+		qualified[round.RoundNumber] = round.Qualified
+
+		// collect the results of every participant in the round
+		results := make(map[uuid.UUID][]contestResult)
+		var roundOrder []uuid.UUID
+		addResult := func(userID uuid.UUID, userName string, result contestResult) {
+			if _, ok := rowsByUser[userID]; !ok {
+				rowsByUser[userID] = &TournamentStandingsRow{UserID: userID}
+				order = append(order, userID)
+			}
+			if rowsByUser[userID].UserName == "" {
+				rowsByUser[userID].UserName = userName
+			}
+			if _, ok := results[userID]; !ok {
+				roundOrder = append(roundOrder, userID)
+			}
+			results[userID] = append(results[userID], result)
+		}
+		for _, contestStandings := range round.Contests {
+			for _, row := range contestStandings.Rows {
+				if row.IsVirtual {
+					continue
+				}
+				result := contestResult{score: row.Score, penalty: row.Penalty}
+				if row.TeamID == nil {
+					addResult(row.UserID, row.UserName, result)
+					continue
+				}
+				for _, memberID := range row.MemberIDs {
+					addResult(memberID, "", result)
+				}
+			}
+		}
+
+		for _, userID := range roundOrder {
+			userResults := results[userID]
+
+			// count only the best contests
+			sort.SliceStable(userResults, func(i, j int) bool {
+				if userResults[i].score != userResults[j].score {
+					return userResults[i].score > userResults[j].score
+				}
+				return userResults[i].penalty < userResults[j].penalty
+			})
+			if round.BestOf != nil && int(*round.BestOf) < len(userResults) {
+				userResults = userResults[:*round.BestOf]
+			}
+
+			var score, penalty int64
+			for _, result := range userResults {
+				score += int64(result.score)
+				penalty += result.penalty
+			}
+			scaled := score * int64(round.Weight)
+
+			row := rowsByUser[userID]
+			row.scaledPoints += scaled
+			row.Penalty += penalty
+			row.RoundsReached = round.RoundNumber
+			row.Rounds = append(row.Rounds, RoundResult{
+				RoundNumber: round.RoundNumber,
+				Points:      float64(scaled) / 100,
+				Penalty:     penalty,
+				Contests:    int32(len(userResults)),
+			})
+		}
+	}
+
+	rows := make([]TournamentStandingsRow, 0, len(order))
+	for _, userID := range order {
+		row := rowsByUser[userID]
+		row.Points = float64(row.scaledPoints) / 100
+
+		// eliminated if the last round reached has advanced without them
+		lastRound := row.RoundsReached
+		if qualifiers := qualified[lastRound]; qualifiers != nil && !qualifiers[userID] {
+			row.EliminatedInRound = &lastRound
+		}
+		rows = append(rows, *row)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].scaledPoints != rows[j].scaledPoints {
+			return rows[i].scaledPoints > rows[j].scaledPoints
+		}
+		if rows[i].RoundsReached != rows[j].RoundsReached {
+			return rows[i].RoundsReached > rows[j].RoundsReached
+		}
+		return rows[i].Penalty < rows[j].Penalty
+	})
+	for i := range rows {
+		if i > 0 &&
+			rows[i].scaledPoints == rows[i-1].scaledPoints &&
+			rows[i].RoundsReached == rows[i-1].RoundsReached &&
+			rows[i].Penalty == rows[i-1].Penalty {
+			rows[i].Rank = rows[i-1].Rank
+		} else {
+			rows[i].Rank = int32(i + 1)
+		}
+	}
+
+	return rows
+}
+
+// getEndedContestStandings returns the final standings of the contests of the
+// round that have ended
+func (t *TournamentService) getEndedContestStandings(
+	ctx context.Context,
+	roundID uuid.UUID,
+) ([]contest_service.ContestStandings, error) {
+	contestIDs, err := t.DB.GetTournamentContests(ctx, roundID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch contests of tournament round with id %v", roundID),
+		)
+		return nil, err
+	}
+
+	var standings []contest_service.ContestStandings
+	for _, contestID := range contestIDs {
+		contest, err := t.ContestServiceConfig.GetContestByID(ctx, contestID)
+		if err != nil {
+			return nil, err
+		}
+		if !time.Now().After(contest.EndTime) {
+			continue
+		}
+
+		contestStandings, err := t.ContestServiceConfig.GetFinalStandings(ctx, contest.ID)
+		if err != nil {
+			return nil, err
+		}
+		standings = append(standings, contestStandings)
+	}
+
+	return standings, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 func (t *TournamentService) validateTournamentRoundLock(
@@ -20,12 +21,40 @@ func (t *TournamentService) validateTournamentRoundLock(
 	}
 
 	// check its type
-	if lock.Type != database.LockTypeManual {
+	if lock.Type != database.LockTypeManual && lock.Type != database.LockTypeComposite {
 		return fmt.Errorf(
-			"%w, can only use manual locks for tournament round creation",
+			"%w, can only use manual or composite locks for tournament rounds",
 			flux_errors.ErrInvalidRequest,
 		)
 	}
 
 	return nil
 }
+
+// getUserNames maps the given users to their user names
+func (t *TournamentService) getUserNames(
+	ctx context.Context,
+	userIDs []uuid.UUID,
+) (map[uuid.UUID]string, error) {
+	names := make(map[uuid.UUID]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return names, nil
+	}
+
+	users, err := t.UserServiceConfig.GetUsersByFilters(
+		ctx,
+		user_service.GetUsersRequest{
+			UserIDs:    userIDs,
+			PageNumber: 1,
+			PageSize:   int32(len(userIDs)),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		names[user.UserID] = user.UserName
+	}
+
+	return names, nil
+}
@@ -0,0 +1,461 @@
+package tournament_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+func (t *TournamentService) UpdateTournament(
+	ctx context.Context,
+	request UpdateTournamentRequest,
+) (Tournament, error) {
+	// authorize (only managers can update a tournament)
+	err := t.UserServiceConfig.Authorize(ctx, user_service.PermTournamentManage, user_service.Resource{})
+	if err != nil {
+		return Tournament{}, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return Tournament{}, err
+	}
+
+	// check if the tournament exists
+	tournament, err := t.GetTournamentByID(ctx, request.TournamentID)
+	if err != nil {
+		return Tournament{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return Tournament{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
+	dbTournament, err := qtx.UpdateTournament(
+		ctx,
+		database.UpdateTournamentParams{
+			ID:    tournament.ID,
+			Title: request.Title,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot update tournament with id %v", tournament.ID),
+		)
+		return Tournament{}, err
+	}
+
+	prevTournament := tournament
+	tournament.Title = dbTournament.Title
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionTournamentUpdate,
+		EntityType: auditEntityTournament,
+		EntityID:   tournament.ID,
+		Before:     prevTournament,
+		After:      tournament,
+	})
+	if err != nil {
+		return Tournament{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Tournament{}, fmt.Errorf(
+			"%w, cannot commit transaction after updating tournament %v, %w",
+			flux_errors.ErrInternal,
+			tournament.ID,
+			err,
+		)
+	}
+
+	return tournament, nil
+}
+
+func (t *TournamentService) SetTournamentPublished(
+	ctx context.Context,
+	request PublishTournamentRequest,
+) (Tournament, error) {
+	// authorize (only managers can publish a tournament)
+	err := t.UserServiceConfig.Authorize(ctx, user_service.PermTournamentManage, user_service.Resource{})
+	if err != nil {
+		return Tournament{}, err
+	}
+
+	// check if the tournament exists
+	tournament, err := t.GetTournamentByID(ctx, request.TournamentID)
+	if err != nil {
+		return Tournament{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return Tournament{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
+	err = qtx.SetTournamentPublished(
+		ctx,
+		database.SetTournamentPublishedParams{
+			ID:          tournament.ID,
+			IsPublished: request.IsPublished,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot publish tournament with id %v", tournament.ID),
+		)
+		return Tournament{}, err
+	}
+
+	prevTournament := tournament
+	tournament.IsPublished = request.IsPublished
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionTournamentPublish,
+		EntityType: auditEntityTournament,
+		EntityID:   tournament.ID,
+		Before:     prevTournament,
+		After:      tournament,
+	})
+	if err != nil {
+		return Tournament{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Tournament{}, fmt.Errorf(
+			"%w, cannot commit transaction after publishing tournament %v, %w",
+			flux_errors.ErrInternal,
+			tournament.ID,
+			err,
+		)
+	}
+
+	return tournament, nil
+}
+
+// UpdateTournamentRound changes the title and the lock of the round. The new
+// lock must be a manual lock, same as while creating the round.
+func (t *TournamentService) UpdateTournamentRound(
+	ctx context.Context,
+	request UpdateTournamentRoundRequest,
+) (TournamentRound, error) {
+	// authorize (only managers can update a tournament round)
+	err := t.UserServiceConfig.Authorize(ctx, user_service.PermTournamentManage, user_service.Resource{})
+	if err != nil {
+		return TournamentRound{}, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return TournamentRound{}, err
+	}
+	if request.LockID == nil {
+		return TournamentRound{}, fmt.Errorf(
+			"%w, round must be associated with a lock",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+	err = t.validateTournamentRoundLock(ctx, *request.LockID)
+	if err != nil {
+		return TournamentRound{}, err
+	}
+
+	// get the round
+	round, err := t.getRound(ctx, request.TournamentID, request.RoundNumber)
+	if err != nil {
+		return TournamentRound{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return TournamentRound{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
+	err = qtx.UpdateTournamentRound(
+		ctx,
+		database.UpdateTournamentRoundParams{
+			ID:     round.ID,
+			Title:  request.Title,
+			LockID: request.LockID,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot update tournament round with id %v", round.ID),
+		)
+		return TournamentRound{}, err
+	}
+
+	prevRound := TournamentRound{
+		ID:           round.ID,
+		TournamentID: round.TournamentID,
+		Title:        round.Title,
+		RoundNumber:  round.RoundNumber,
+		LockID:       round.LockID,
+		CreatedBy:    round.CreatedBy,
+		IsPublished:  round.IsPublished,
+	}
+	updatedRound := prevRound
+	updatedRound.Title = request.Title
+	updatedRound.LockID = request.LockID
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionTournamentUpdateRound,
+		EntityType: auditEntityTournament,
+		EntityID:   round.TournamentID,
+		Before:     prevRound,
+		After:      updatedRound,
+	})
+	if err != nil {
+		return TournamentRound{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return TournamentRound{}, fmt.Errorf(
+			"%w, cannot commit transaction after updating tournament round %v, %w",
+			flux_errors.ErrInternal,
+			round.ID,
+			err,
+		)
+	}
+
+	return updatedRound, nil
+}
+
+// SetTournamentRoundPublished shows or hides the contests of the round from
+// the users who are not managers
+func (t *TournamentService) SetTournamentRoundPublished(
+	ctx context.Context,
+	request PublishTournamentRoundRequest,
+) (TournamentRound, error) {
+	// authorize (only managers can publish a tournament round)
+	err := t.UserServiceConfig.Authorize(ctx, user_service.PermTournamentManage, user_service.Resource{})
+	if err != nil {
+		return TournamentRound{}, err
+	}
+
+	// get the round
+	round, err := t.getRound(ctx, request.TournamentID, request.RoundNumber)
+	if err != nil {
+		return TournamentRound{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return TournamentRound{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
+	err = qtx.SetTournamentRoundPublished(
+		ctx,
+		database.SetTournamentRoundPublishedParams{
+			ID:          round.ID,
+			IsPublished: request.IsPublished,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot publish tournament round with id %v", round.ID),
+		)
+		return TournamentRound{}, err
+	}
+
+	prevRound := TournamentRound{
+		ID:           round.ID,
+		TournamentID: round.TournamentID,
+		Title:        round.Title,
+		RoundNumber:  round.RoundNumber,
+		LockID:       round.LockID,
+		CreatedBy:    round.CreatedBy,
+		IsPublished:  round.IsPublished,
+	}
+	updatedRound := prevRound
+	updatedRound.IsPublished = request.IsPublished
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionTournamentPublishRound,
+		EntityType: auditEntityTournament,
+		EntityID:   round.TournamentID,
+		Before:     prevRound,
+		After:      updatedRound,
+	})
+	if err != nil {
+		return TournamentRound{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return TournamentRound{}, fmt.Errorf(
+			"%w, cannot commit transaction after publishing tournament round %v, %w",
+			flux_errors.ErrInternal,
+			round.ID,
+			err,
+		)
+	}
+
+	return updatedRound, nil
+}
+
+// ReorderTournamentRounds renumbers the rounds in the given order. Rounds
+// cannot be reordered once any of them has advanced its qualifiers.
+func (t *TournamentService) ReorderTournamentRounds(
+	ctx context.Context,
+	request ReorderTournamentRoundsRequest,
+) error {
+	// authorize (only managers can reorder the rounds)
+	err := t.UserServiceConfig.Authorize(ctx, user_service.PermTournamentManage, user_service.Resource{})
+	if err != nil {
+		return err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return err
+	}
+
+	// check if the tournament exists
+	tournament, err := t.GetTournamentByID(ctx, request.TournamentID)
+	if err != nil {
+		return err
+	}
+
+	dbRounds, err := t.DB.GetTournamentRoundsWithScoring(ctx, tournament.ID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch rounds of tournament %v", tournament.ID),
+		)
+		return err
+	}
+
+	// the new order must have every round exactly once
+	if len(request.RoundNumbers) != len(dbRounds) {
+		return fmt.Errorf(
+			"%w, tournament has %d rounds but %d are given",
+			flux_errors.ErrInvalidRequest,
+			len(dbRounds),
+			len(request.RoundNumbers),
+		)
+	}
+	roundIDs := make(map[int32]uuid.UUID, len(dbRounds))
+	for _, dbRound := range dbRounds {
+		if dbRound.AdvancedAt != nil {
+			return fmt.Errorf(
+				"%w, round %d has already advanced, rounds cannot be reordered",
+				flux_errors.ErrInvalidRequest,
+				dbRound.RoundNumber,
+			)
+		}
+		roundIDs[dbRound.RoundNumber] = dbRound.ID
+	}
+	orderedIDs := make([]uuid.UUID, 0, len(request.RoundNumbers))
+	for _, roundNumber := range request.RoundNumbers {
+		roundID, ok := roundIDs[roundNumber]
+		if !ok {
+			return fmt.Errorf(
+				"%w, round %d does not exist or is given more than once",
+				flux_errors.ErrInvalidRequest,
+				roundNumber,
+			)
+		}
+		delete(roundIDs, roundNumber)
+		orderedIDs = append(orderedIDs, roundID)
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
+	err = qtx.ReorderTournamentRounds(
+		ctx,
+		database.ReorderTournamentRoundsParams{
+			RoundIds:     orderedIDs,
+			TournamentID: tournament.ID,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot reorder rounds of tournament %v", tournament.ID),
+		)
+		return err
+	}
+
+	// record the change
+	err = service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     auditActionTournamentReorderRounds,
+		EntityType: auditEntityTournament,
+		EntityID:   tournament.ID,
+		After:      request.RoundNumbers,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf(
+			"%w, cannot commit transaction after reordering rounds of tournament %v, %w",
+			flux_errors.ErrInternal,
+			tournament.ID,
+			err,
+		)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package user_service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -44,7 +45,27 @@ func (u *UserService) GetMe(ctx context.Context) (User, error) {
 		return User{}, err
 	}
 
-	return u.GetUserProfile(ctx, claims.UserId)
+	user, err := u.GetUserProfile(ctx, claims.UserId)
+	if err != nil {
+		return User{}, err
+	}
+
+	// get the current rating
+	rating, err := u.DB.GetUserCurrentRating(ctx, claims.UserId)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch rating of user %s", claims.UserName),
+		)
+		if !errors.Is(err, flux_errors.ErrNotFound) {
+			return User{}, err
+		}
+	} else {
+		user.Rating = &rating
+	}
+
+	return user, nil
 }
 
 func (u *UserService) GetUsersByFilters(
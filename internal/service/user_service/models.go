@@ -1,25 +1,75 @@
 package user_service
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	lru "github.com/hashicorp/golang-lru/v2"
 	log "github.com/sirupsen/logrus"
 	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
 )
 
 var (
-	errMsgs = map[string]map[string]string{}
+	msgForeignKey = map[string]string{
+		"user_roles_role_name_fkey":       "Role does not exist.",
+		"fk_role_permissions_permission":  "Permission does not exist.",
+		"role_permissions_role_name_fkey": "Role does not exist.",
+	}
+
+	msgUniqueConstraint = map[string]string{
+		"roles_pkey":      "Role already exists.",
+		"user_roles_pkey": "User already has the role.",
+	}
+
+	errMsgs = map[string]map[string]string{
+		flux_errors.CodeForeignKeyConstraint: msgForeignKey,
+		flux_errors.CodeUniqueConstraint:     msgUniqueConstraint,
+	}
 )
 
 const (
 	RoleManager   = "role_manager"
 	RoleHC        = "role_hc"
 	cacheCapacity = 50
+	// entity type of role changes in the audit log
+	auditEntityRole = "role"
+	// audit actions
+	auditActionRoleCreate         = "role.create_role"
+	auditActionRoleGrant          = "role.grant"
+	auditActionRoleRevoke         = "role.revoke"
+	auditActionRoleSetPermissions = "role.set_permissions"
+)
+
+// permissions checked by the services, the roles holding them are stored in
+// role_permissions. Permissions ending with .any let the user act on the
+// resources created by others.
+const (
+	PermProblemCreate    = "problem.create"
+	PermProblemEditAny   = "problem.edit.any"
+	PermContestCreate    = "contest.create"
+	PermContestManage    = "contest.manage"
+	PermContestEditAny   = "contest.edit.any"
+	PermLockCreate       = "lock.create"
+	PermLockSearch       = "lock.search"
+	PermLockView         = "lock.view"
+	PermLockEditAny      = "lock.edit.any"
+	PermTournamentManage = "tournament.manage"
+	PermRatingManage     = "rating.manage"
+	PermBotManage        = "bot.manage"
+	PermLifecycleView    = "lifecycle.view"
+	PermRoleManage       = "role.manage"
+	PermAuditView        = "audit.view"
+	PermSessionManage    = "session.manage"
+	PermSubmissionJudge  = "submission.judge"
 )
 
 type UserService struct {
-	DB         *database.Queries
-	rolesCache *lru.Cache[uuid.UUID, []string]
+	DB               *database.Queries
+	rolesCache       *lru.Cache[uuid.UUID, []string]
+	permissionsCache *lru.Cache[uuid.UUID, []string]
+	// users holding TwoFactorRoles must log in with the second factor
+	RequireTwoFactor bool
 }
 
 func (u *UserService) IntializeUserServices() error {
@@ -29,9 +79,25 @@ func (u *UserService) IntializeUserServices() error {
 		return err
 	}
 	u.rolesCache = cache
+
+	log.Infof("intializing uuid->[]string (permissionsCache) cache with capacity %d", cacheCapacity)
+	permissionsCache, err := lru.New[uuid.UUID, []string](cacheCapacity)
+	if err != nil {
+		return err
+	}
+	u.permissionsCache = permissionsCache
 	return nil
 }
 
+// Resource is what a permission is checked on. The owner of a resource is
+// always allowed, anyone else needs the permission. Kind and ID are only
+// used for logging.
+type Resource struct {
+	Kind    string
+	ID      any
+	OwnerID *uuid.UUID
+}
+
 type GetUsersRequest struct {
 	UserIDs    []uuid.UUID `json:"user_ids"`
 	UserNames  []string    `json:"user_names"`
@@ -49,6 +115,9 @@ type User struct {
 	LastName     string    `json:"last_name"`
 	Email        string    `json:"email"`
 	PasswordHash string    `json:"-"`
+
+	// current rating, nil if the user has not taken part in any rated contest
+	Rating *int32 `json:"rating"`
 }
 
 // used as a dto between different layers
@@ -57,3 +126,55 @@ type UserMetaData struct {
 	UserName string    `json:"user_name"`
 	RollNo   string    `json:"roll_no"`
 }
+
+type NotificationSettings struct {
+	// contest registrations, reminders, results and broadcasts
+	ContestEmails bool `json:"contest_emails"`
+}
+
+type Role struct {
+	RoleName    string `json:"role_name"`
+	Description string `json:"description"`
+	// nil for the roles created without the api
+	CreatedBy   *uuid.UUID `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	MemberCount int64      `json:"member_count"`
+	Permissions []string   `json:"permissions"`
+}
+
+// custom roles are named like the built in ones, e.g. role_problem_tester
+type CreateRoleRequest struct {
+	RoleName    string `json:"role_name" validate:"min=6,max=50,startswith=role_,lowercase"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+type RoleAssignmentRequest struct {
+	UserName string `json:"user_name" validate:"required"`
+	RoleName string `json:"role_name" validate:"required"`
+}
+
+type RoleMember struct {
+	UserID   uuid.UUID `json:"user_id"`
+	UserName string    `json:"user_name"`
+	RollNo   string    `json:"roll_no"`
+	// nil for the roles granted without the api
+	GrantedBy *uuid.UUID `json:"granted_by"`
+	GrantedAt time.Time  `json:"granted_at"`
+}
+
+type GetRoleMembersRequest struct {
+	RoleName   string `json:"role_name" validate:"required"`
+	PageNumber int32  `json:"page_number" validate:"min=1,max=10000"`
+	PageSize   int32  `json:"page_size" validate:"min=1,max=100"`
+}
+
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// SetRolePermissionsRequest replaces all the permissions of the role
+type SetRolePermissionsRequest struct {
+	RoleName    string   `json:"role_name" validate:"required"`
+	Permissions []string `json:"permissions" validate:"max=100,dive,required"`
+}
@@ -0,0 +1,72 @@
+package user_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// GetNotificationSettings returns the notification settings of the user. Users
+// who never changed them receive every notification.
+func (u *UserService) GetNotificationSettings(ctx context.Context) (NotificationSettings, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return NotificationSettings{}, err
+	}
+
+	dbSettings, err := u.DB.GetNotificationSettings(ctx, claims.UserId)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch notification settings of user %s", claims.UserName),
+		)
+		if errors.Is(err, flux_errors.ErrNotFound) {
+			return NotificationSettings{ContestEmails: true}, nil
+		}
+		return NotificationSettings{}, err
+	}
+
+	return NotificationSettings{ContestEmails: dbSettings.ContestEmails}, nil
+}
+
+func (u *UserService) SetNotificationSettings(
+	ctx context.Context,
+	settings NotificationSettings,
+) (NotificationSettings, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return NotificationSettings{}, err
+	}
+
+	dbSettings, err := u.DB.UpsertNotificationSettings(
+		ctx,
+		database.UpsertNotificationSettingsParams{
+			UserID:        claims.UserId,
+			ContestEmails: settings.ContestEmails,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot update notification settings of user %s", claims.UserName),
+		)
+		return NotificationSettings{}, err
+	}
+
+	log.Infof(
+		"user %s updated notification settings, contest emails: %v",
+		claims.UserName,
+		dbSettings.ContestEmails,
+	)
+
+	return NotificationSettings{ContestEmails: dbSettings.ContestEmails}, nil
+}
@@ -0,0 +1,353 @@
+package user_service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// MissingPermissions returns the required permissions that are not held, in
+// the order they are required
+func MissingPermissions(held []string, required []string) []string {
+	missing := make([]string, 0)
+	for _, permission := range required {
+		if !slices.Contains(held, permission) && !slices.Contains(missing, permission) {
+			missing = append(missing, permission)
+		}
+	}
+	return missing
+}
+
+// ScopedPermissions returns the held permissions that are also in the scopes
+// of the access token
+func ScopedPermissions(held []string, scopes []string) []string {
+	scoped := make([]string, 0)
+	for _, permission := range held {
+		if slices.Contains(scopes, permission) {
+			scoped = append(scoped, permission)
+		}
+	}
+	return scoped
+}
+
+// OwnerAllowed reports if the owner of a resource may use the permission on
+// it. Access tokens reach only the permissions in their scopes, and sessions
+// that skipped a required second factor reach none.
+func OwnerAllowed(
+	claims service.UserCredentialClaims,
+	permission string,
+	lacksTwoFactor bool,
+) bool {
+	if claims.AccessTokenID != nil {
+		return slices.Contains(claims.Scopes, permission)
+	}
+	return !lacksTwoFactor
+}
+
+// FetchUserPermissions returns the permissions of all the roles of the user
+func (u *UserService) FetchUserPermissions(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]string, error) {
+	// try to get permissions from cache
+	permissions, ok := u.permissionsCache.Get(userID)
+	if ok {
+		log.Debugf("permissionsCache hit for user %v", userID)
+		return permissions, nil
+	}
+
+	// get from db
+	log.Debugf("permissionsCache miss for user %v", userID)
+	permissions, err := u.DB.GetUserPermissions(ctx, userID)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch permissions of user %v", userID),
+		)
+		return nil, err
+	}
+	if permissions == nil {
+		permissions = []string{}
+	}
+
+	evicted := u.permissionsCache.Add(userID, permissions)
+	log.Debugf("added permissions of %v to cache, evicted: %v", userID, evicted)
+	return permissions, nil
+}
+
+// IsAllowed reports if the user owns the resource or holds the permission
+func (u *UserService) IsAllowed(
+	ctx context.Context,
+	permission string,
+	resource Resource,
+) (bool, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// owners can act on their resources within what the request can use
+	if resource.OwnerID != nil && *resource.OwnerID == claims.UserId {
+		restricted, err := u.lacksTwoFactor(ctx, claims)
+		if err != nil {
+			return false, err
+		}
+		if OwnerAllowed(claims, permission, restricted) {
+			return true, nil
+		}
+	}
+
+	permissions, err := u.claimedPermissions(ctx, claims)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(permissions, permission), nil
+}
+
+// Authorize lets the user in if they own the resource or hold the
+// permission. Pass an empty resource for the actions not tied to one.
+func (u *UserService) Authorize(
+	ctx context.Context,
+	permission string,
+	resource Resource,
+) error {
+	allowed, err := u.IsAllowed(ctx, permission, resource)
+	if err != nil {
+		return err
+	}
+	if allowed {
+		return nil
+	}
+
+	// warn
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if resource.Kind != "" {
+		log.Warnf(
+			"user %s was denied %s on %s %v",
+			claims.UserName,
+			permission,
+			resource.Kind,
+			resource.ID,
+		)
+	} else {
+		log.Warnf("user %s was denied %s", claims.UserName, permission)
+	}
+
+	return flux_errors.ErrUnAuthorized
+}
+
+// GetMyPermissions lists the permissions of the user, so that the actions
+// they cannot take can be hidden
+func (u *UserService) GetMyPermissions(ctx context.Context) ([]string, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.claimedPermissions(ctx, claims)
+}
+
+// claimedPermissions returns the permissions the request can use, the ones in
+// the scopes of the access token or none if a required second factor was
+// skipped
+func (u *UserService) claimedPermissions(
+	ctx context.Context,
+	claims service.UserCredentialClaims,
+) ([]string, error) {
+	restricted, err := u.lacksTwoFactor(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if restricted {
+		return []string{}, nil
+	}
+
+	permissions, err := u.FetchUserPermissions(ctx, claims.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if claims.AccessTokenID != nil {
+		permissions = ScopedPermissions(permissions, claims.Scopes)
+	}
+
+	return permissions, nil
+}
+
+func (u *UserService) GetPermissions(ctx context.Context) ([]Permission, error) {
+	// authorize
+	err := u.Authorize(ctx, PermRoleManage, Resource{})
+	if err != nil {
+		return nil, err
+	}
+
+	dbPermissions, err := u.DB.GetPermissions(ctx)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			"cannot fetch permissions",
+		)
+		return nil, err
+	}
+
+	permissions := make([]Permission, 0, len(dbPermissions))
+	for _, dbPermission := range dbPermissions {
+		permissions = append(permissions, Permission{
+			Name:        dbPermission.Name,
+			Description: dbPermission.Description,
+		})
+	}
+
+	return permissions, nil
+}
+
+// SetRolePermissions replaces the permissions of the role. The user must
+// hold every permission the role has before and after the change.
+func (u *UserService) SetRolePermissions(
+	ctx context.Context,
+	request SetRolePermissionsRequest,
+) (Role, error) {
+	// validate
+	err := service.ValidateInput(request)
+	if err != nil {
+		return Role{}, err
+	}
+
+	// authorize
+	claims, err := u.authorizeRoleChange(ctx, request.RoleName, request.Permissions)
+	if err != nil {
+		return Role{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return Role{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := u.DB.WithTx(tx)
+
+	err = qtx.DeleteRolePermissions(ctx, request.RoleName)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot delete permissions of role %s", request.RoleName),
+		)
+		return Role{}, err
+	}
+
+	err = qtx.AddRolePermissions(
+		ctx,
+		database.AddRolePermissionsParams{
+			RoleName:    request.RoleName,
+			Permissions: request.Permissions,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot add permissions to role %s", request.RoleName),
+		)
+		return Role{}, err
+	}
+
+	err = auditRoleChange(ctx, qtx, auditActionRoleSetPermissions, request.RoleName, request)
+	if err != nil {
+		return Role{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Role{}, fmt.Errorf(
+			"%w, cannot commit transaction after setting permissions of role %s, %w",
+			flux_errors.ErrInternal,
+			request.RoleName,
+			err,
+		)
+	}
+
+	// permissions of every member of the role are stale now
+	u.permissionsCache.Purge()
+
+	log.Infof(
+		"user %s set permissions of role %s to %v",
+		claims.UserName,
+		request.RoleName,
+		request.Permissions,
+	)
+	return Role{
+		RoleName:    request.RoleName,
+		Permissions: slices.Clone(request.Permissions),
+	}, nil
+}
+
+// authorizeRoleChange lets a user with role.manage change a role only if they
+// can already use all of its permissions, and the ones it is about to get, so
+// that no one can hand out more than they have
+func (u *UserService) authorizeRoleChange(
+	ctx context.Context,
+	roleName string,
+	newPermissions []string,
+) (service.UserCredentialClaims, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return service.UserCredentialClaims{}, err
+	}
+
+	err = u.Authorize(ctx, PermRoleManage, Resource{Kind: "role", ID: roleName})
+	if err != nil {
+		return service.UserCredentialClaims{}, err
+	}
+
+	rolePermissions, err := u.DB.GetRolePermissions(ctx, roleName)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch permissions of role %s", roleName),
+		)
+		return service.UserCredentialClaims{}, err
+	}
+
+	// only what the request can use counts, scopes of access tokens included
+	held, err := u.claimedPermissions(ctx, claims)
+	if err != nil {
+		return service.UserCredentialClaims{}, err
+	}
+
+	missing := MissingPermissions(held, append(rolePermissions, newPermissions...))
+	if len(missing) > 0 {
+		log.Warnf(
+			"user %s tried to change role %s without holding %v",
+			claims.UserName,
+			roleName,
+			missing,
+		)
+		return service.UserCredentialClaims{}, fmt.Errorf(
+			"%w, role has permissions you do not hold: %v",
+			flux_errors.ErrUnAuthorized,
+			missing,
+		)
+	}
+
+	return claims, nil
+}
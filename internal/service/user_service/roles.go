@@ -0,0 +1,355 @@
+package user_service
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+func (u *UserService) GetRoles(ctx context.Context) ([]Role, error) {
+	// authorize
+	err := u.Authorize(ctx, PermRoleManage, Resource{})
+	if err != nil {
+		return nil, err
+	}
+
+	dbRoles, err := u.DB.GetRoles(ctx)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			"cannot fetch roles",
+		)
+		return nil, err
+	}
+
+	dbRolePermissions, err := u.DB.GetAllRolePermissions(ctx)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			"cannot fetch permissions of roles",
+		)
+		return nil, err
+	}
+	rolePermissions := make(map[string][]string)
+	for _, dbRolePermission := range dbRolePermissions {
+		rolePermissions[dbRolePermission.RoleName] = append(
+			rolePermissions[dbRolePermission.RoleName],
+			dbRolePermission.Permission,
+		)
+	}
+
+	roles := make([]Role, 0, len(dbRoles))
+	for _, dbRole := range dbRoles {
+		permissions := rolePermissions[dbRole.RoleName]
+		if permissions == nil {
+			permissions = []string{}
+		}
+		roles = append(roles, Role{
+			RoleName:    dbRole.RoleName,
+			Description: dbRole.Description,
+			CreatedBy:   dbRole.CreatedBy,
+			CreatedAt:   dbRole.CreatedAt,
+			MemberCount: dbRole.MemberCount,
+			Permissions: permissions,
+		})
+	}
+
+	return roles, nil
+}
+
+// CreateRole adds a custom role, which can then be used as the access of
+// locks and granted to users
+func (u *UserService) CreateRole(
+	ctx context.Context,
+	request CreateRoleRequest,
+) (Role, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return Role{}, err
+	}
+
+	// authorize
+	err = u.Authorize(ctx, PermRoleManage, Resource{})
+	if err != nil {
+		return Role{}, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return Role{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return Role{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := u.DB.WithTx(tx)
+
+	dbRole, err := qtx.CreateRole(
+		ctx,
+		database.CreateRoleParams{
+			RoleName:    request.RoleName,
+			Description: request.Description,
+			CreatedBy:   &claims.UserId,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot create role %s", request.RoleName),
+		)
+		return Role{}, err
+	}
+
+	err = auditRoleChange(ctx, qtx, auditActionRoleCreate, dbRole.RoleName, request)
+	if err != nil {
+		return Role{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return Role{}, fmt.Errorf(
+			"%w, cannot commit transaction after creating role %s, %w",
+			flux_errors.ErrInternal,
+			dbRole.RoleName,
+			err,
+		)
+	}
+
+	log.Infof("user %s created role %s", claims.UserName, dbRole.RoleName)
+	return Role{
+		RoleName:    dbRole.RoleName,
+		Description: dbRole.Description,
+		CreatedBy:   dbRole.CreatedBy,
+		CreatedAt:   dbRole.CreatedAt,
+		Permissions: []string{},
+	}, nil
+}
+
+func (u *UserService) GrantRole(
+	ctx context.Context,
+	request RoleAssignmentRequest,
+) (RoleMember, error) {
+	claims, user, err := u.authorizeRoleAssignment(ctx, request)
+	if err != nil {
+		return RoleMember{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return RoleMember{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := u.DB.WithTx(tx)
+
+	dbUserRole, err := qtx.AddUserRole(
+		ctx,
+		database.AddUserRoleParams{
+			UserID:    user.UserID,
+			RoleName:  request.RoleName,
+			GrantedBy: &claims.UserId,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot grant role %s to user %s", request.RoleName, user.UserName),
+		)
+		return RoleMember{}, err
+	}
+
+	err = auditRoleChange(ctx, qtx, auditActionRoleGrant, request.RoleName, request)
+	if err != nil {
+		return RoleMember{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return RoleMember{}, fmt.Errorf(
+			"%w, cannot commit transaction after granting role %s, %w",
+			flux_errors.ErrInternal,
+			request.RoleName,
+			err,
+		)
+	}
+
+	// the cached roles and permissions are stale now
+	u.rolesCache.Remove(user.UserID)
+	u.permissionsCache.Remove(user.UserID)
+
+	log.Infof("user %s granted role %s to user %s", claims.UserName, request.RoleName, user.UserName)
+	return RoleMember{
+		UserID:    user.UserID,
+		UserName:  user.UserName,
+		RollNo:    user.RollNo,
+		GrantedBy: dbUserRole.GrantedBy,
+		GrantedAt: dbUserRole.GrantedAt,
+	}, nil
+}
+
+func (u *UserService) RevokeRole(
+	ctx context.Context,
+	request RoleAssignmentRequest,
+) error {
+	claims, user, err := u.authorizeRoleAssignment(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := u.DB.WithTx(tx)
+
+	_, err = qtx.RemoveUserRole(
+		ctx,
+		database.RemoveUserRoleParams{
+			UserID:   user.UserID,
+			RoleName: request.RoleName,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot revoke role %s from user %s", request.RoleName, user.UserName),
+		)
+		return err
+	}
+
+	err = auditRoleChange(ctx, qtx, auditActionRoleRevoke, request.RoleName, request)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf(
+			"%w, cannot commit transaction after revoking role %s, %w",
+			flux_errors.ErrInternal,
+			request.RoleName,
+			err,
+		)
+	}
+
+	// the cached roles and permissions are stale now
+	u.rolesCache.Remove(user.UserID)
+	u.permissionsCache.Remove(user.UserID)
+
+	log.Infof("user %s revoked role %s from user %s", claims.UserName, request.RoleName, user.UserName)
+	return nil
+}
+
+func (u *UserService) GetRoleMembers(
+	ctx context.Context,
+	request GetRoleMembersRequest,
+) ([]RoleMember, error) {
+	// authorize
+	err := u.Authorize(ctx, PermRoleManage, Resource{})
+	if err != nil {
+		return nil, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return nil, err
+	}
+
+	dbMembers, err := u.DB.GetRoleMembers(
+		ctx,
+		database.GetRoleMembersParams{
+			RoleName: request.RoleName,
+			Offset:   (request.PageNumber - 1) * request.PageSize,
+			Limit:    request.PageSize,
+		},
+	)
+	if err != nil {
+		err = flux_errors.HandleDBErrors(
+			err,
+			errMsgs,
+			fmt.Sprintf("cannot fetch members of role %s", request.RoleName),
+		)
+		return nil, err
+	}
+
+	members := make([]RoleMember, 0, len(dbMembers))
+	for _, dbMember := range dbMembers {
+		members = append(members, RoleMember{
+			UserID:    dbMember.ID,
+			UserName:  dbMember.UserName,
+			RollNo:    dbMember.RollNo,
+			GrantedBy: dbMember.GrantedBy,
+			GrantedAt: dbMember.GrantedAt,
+		})
+	}
+
+	return members, nil
+}
+
+// authorizeRoleAssignment lets a user change the members of a role only if
+// they hold all of its permissions
+func (u *UserService) authorizeRoleAssignment(
+	ctx context.Context,
+	request RoleAssignmentRequest,
+) (service.UserCredentialClaims, UserMetaData, error) {
+	// validate
+	err := service.ValidateInput(request)
+	if err != nil {
+		return service.UserCredentialClaims{}, UserMetaData{}, err
+	}
+
+	// authorize
+	claims, err := u.authorizeRoleChange(ctx, request.RoleName, nil)
+	if err != nil {
+		return service.UserCredentialClaims{}, UserMetaData{}, err
+	}
+
+	user, err := u.FetchUserByUserName(ctx, request.UserName)
+	if err != nil {
+		return service.UserCredentialClaims{}, UserMetaData{}, err
+	}
+
+	return claims, user, nil
+}
+
+// auditRoleChange records the change of a role in the audit log
+func auditRoleChange(
+	ctx context.Context,
+	qtx *database.Queries,
+	action string,
+	roleName string,
+	after any,
+) error {
+	return service.RecordAudit(ctx, qtx, service.AuditEntry{
+		Action:     action,
+		EntityType: auditEntityRole,
+		EntityID:   roleName,
+		After:      after,
+	})
+}
@@ -0,0 +1,60 @@
+package user_service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// TwoFactorRoles are the roles that must log in with the second factor, if
+// RequireTwoFactor is set
+var TwoFactorRoles = []string{RoleManager, RoleHC}
+
+// HoldsTwoFactorRole reports if any of the roles must log in with the second
+// factor
+func HoldsTwoFactorRole(roles []string) bool {
+	return slices.ContainsFunc(roles, func(role string) bool {
+		return slices.Contains(TwoFactorRoles, role)
+	})
+}
+
+// IsTwoFactorRequired reports if the user must log in with the second factor
+// to use their permissions
+func (u *UserService) IsTwoFactorRequired(ctx context.Context, userID uuid.UUID) (bool, error) {
+	if !u.RequireTwoFactor {
+		return false, nil
+	}
+
+	roles, err := u.FetchUserRoles(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return HoldsTwoFactorRole(roles), nil
+}
+
+// lacksTwoFactor reports if the session skipped a required second factor.
+// Such sessions keep only what every user can do, until the user enables two
+// factor authentication and logs in again. Access tokens are let through,
+// they are scoped to what the session creating them could use.
+func (u *UserService) lacksTwoFactor(
+	ctx context.Context,
+	claims service.UserCredentialClaims,
+) (bool, error) {
+	if claims.TwoFactor || claims.AccessTokenID != nil {
+		return false, nil
+	}
+
+	required, err := u.IsTwoFactorRequired(ctx, claims.UserId)
+	if err != nil {
+		return false, err
+	}
+	if required {
+		log.Warnf("user %s has not logged in with the second factor", claims.UserName)
+	}
+
+	return required, nil
+}
@@ -130,6 +130,12 @@ func (u *UserService) AuthorizeUserRole(
 		return err
 	}
 
+	// roles cannot be scoped, access tokens act only through their permissions
+	if claims.AccessTokenID != nil {
+		log.Warnf("user %s used an access token to act as role %s", claims.UserName, role)
+		return flux_errors.ErrUnAuthorized
+	}
+
 	// get roles
 	roles, err := u.FetchUserRoles(ctx, claims.UserId)
 	if err != nil {
@@ -138,7 +144,14 @@ func (u *UserService) AuthorizeUserRole(
 
 	// access_role must be present in user_roles
 	if slices.Contains(roles, string(role)) {
-		return nil
+		// privileged roles need the second factor
+		restricted, err := u.lacksTwoFactor(ctx, claims)
+		if err != nil {
+			return err
+		}
+		if !restricted || !slices.Contains(TwoFactorRoles, role) {
+			return nil
+		}
 	}
 
 	// warn
@@ -149,33 +162,6 @@ func (u *UserService) AuthorizeUserRole(
 	return flux_errors.ErrUnAuthorized
 }
 
-func (u *UserService) AuthorizeCreatorAccess(
-	ctx context.Context,
-	creatorId uuid.UUID,
-	warnMessage string,
-) error {
-	claims, err := service.GetClaimsFromContext(ctx)
-	if err != nil {
-		return err
-	}
-
-	// check if they are hc
-	err = u.AuthorizeUserRole(
-		ctx,
-		RoleHC,
-		"",
-	)
-	if err == nil {
-		return nil
-	}
-
-	if claims.UserId != creatorId {
-		return flux_errors.ErrUnAuthorized
-	}
-
-	return nil
-}
-
 // func (u *UserService) IsUserIDValid(
 // 	ctx context.Context,
 // 	userID uuid.UUID,
@@ -2,14 +2,34 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/golang-jwt/jwt/v4"
 	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service"
 )
 
+// Authenticator checks the credentials that are not verified by the
+// middleware itself
+type Authenticator interface {
+	// CheckSession tells if the session of a valid token is still active
+	CheckSession(ctx context.Context, claims service.UserCredentialClaims) error
+	// AuthenticateAccessToken returns the claims of the owner of the token
+	AuthenticateAccessToken(ctx context.Context, token string) (service.UserCredentialClaims, error)
+}
+
+var authenticator Authenticator
+
+// SetAuthenticator sets the authenticator consulted on every authenticated
+// request. It must be set before the server starts.
+func SetAuthenticator(a Authenticator) {
+	authenticator = a
+}
+
 /*
 	jwt middleware is used to authenticate every endpoint that a user try to access
 	this can be used to avoid sending data manually everytime
@@ -20,30 +40,8 @@ import (
 
 func JWTMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// token will be passed as cookie
-		authCookie, err := r.Cookie(KeyJwtSessionCookieName)
-		if err != nil {
-			if err == http.ErrNoCookie {
-				// This is typical if the user is not logged in or their session expired.
-				log.Errorf("Error: JWT cookie '%s' not found.\n", KeyJwtSessionCookieName)
-				http.Error(
-					w, "Authentication required: JWT cookie not found.",
-					http.StatusUnauthorized,
-				)
-				return
-			}
-			// Other errors, potentially malformed cookie header
-			log.Errorf("Error reading JWT cookie '%s': %v\n", KeyJwtSessionCookieName, err)
-			http.Error(w, "Bad Request: Error processing cookies.", http.StatusBadRequest)
-			return
-		}
-
-		tokenString := authCookie.Value
-
-		// get jwt_secret key used during generation of token to parse it back
-		jwt_secret := os.Getenv(service.KeyJWTSecret)
-		if jwt_secret == "" {
-			log.Error("jwt secret key is not found")
+		if authenticator == nil {
+			log.Error("authenticator is not set")
 			http.Error(
 				w, "internal error. please try again later",
 				http.StatusInternalServerError,
@@ -51,35 +49,22 @@ func JWTMiddleware(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		// parse the token
-		claims := service.UserCredentialClaims{}
-		token, err := jwt.ParseWithClaims(
-			tokenString, &claims,
-			// function to extract the jwt_secret to parse the token
-			func(t *jwt.Token) (any, error) {
-				// Check the signing method to prevent algorithm confusion
-				if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-					log.Errorf("Unexpected signing method: %v", t.Header["alg"])
-					return nil, jwt.ErrSignatureInvalid
-				}
-				return []byte(jwt_secret), nil
-			},
-		)
-
-		// validate the token
-		if err != nil || !token.Valid {
-			// The `jwt` library now automatically checks the "exp" claim
-			if err != nil {
-				// error might be on server side also. log it for safety purpose
-				log.Errorf("Invalid Token: %v", err)
-			}
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		// scripts pass a personal access token instead of the cookie
+		var claims service.UserCredentialClaims
+		var ok bool
+		if authHeader := r.Header.Get(KeyAuthorizationHeaderName); authHeader != "" {
+			claims, ok = authenticateAccessToken(w, r, authHeader)
+		} else {
+			claims, ok = authenticateSessionCookie(w, r)
+		}
+		if !ok {
 			return
 		}
 
 		// log the endpoint user tyring to access
 		log.WithFields(log.Fields{
-			"user_name": claims.UserName,
+			"user_name":  claims.UserName,
+			"request_id": r.Context().Value(service.KeyCtxRequestID),
 		}).Infof("accessing %v[%v] endpoint", r.Method, r.URL.Path)
 
 		// pass the claims with context
@@ -89,3 +74,108 @@ func JWTMiddleware(next http.HandlerFunc) http.HandlerFunc {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	}
 }
+
+// authenticateSessionCookie verifies the jwt of the session cookie set on login
+func authenticateSessionCookie(
+	w http.ResponseWriter,
+	r *http.Request,
+) (claims service.UserCredentialClaims, ok bool) {
+	// token will be passed as cookie
+	authCookie, err := r.Cookie(KeyJwtSessionCookieName)
+	if err != nil {
+		if err == http.ErrNoCookie {
+			// This is typical if the user is not logged in or their session expired.
+			log.Errorf("Error: JWT cookie '%s' not found.\n", KeyJwtSessionCookieName)
+			http.Error(
+				w, "Authentication required: JWT cookie not found.",
+				http.StatusUnauthorized,
+			)
+			return claims, false
+		}
+		// Other errors, potentially malformed cookie header
+		log.Errorf("Error reading JWT cookie '%s': %v\n", KeyJwtSessionCookieName, err)
+		http.Error(w, "Bad Request: Error processing cookies.", http.StatusBadRequest)
+		return claims, false
+	}
+
+	tokenString := authCookie.Value
+
+	// get jwt_secret key used during generation of token to parse it back
+	jwt_secret := os.Getenv(service.KeyJWTSecret)
+	if jwt_secret == "" {
+		log.Error("jwt secret key is not found")
+		http.Error(
+			w, "internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return claims, false
+	}
+
+	// parse the token
+	token, err := jwt.ParseWithClaims(
+		tokenString, &claims,
+		// function to extract the jwt_secret to parse the token
+		func(t *jwt.Token) (any, error) {
+			// Check the signing method to prevent algorithm confusion
+			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+				log.Errorf("Unexpected signing method: %v", t.Header["alg"])
+				return nil, jwt.ErrSignatureInvalid
+			}
+			return []byte(jwt_secret), nil
+		},
+	)
+
+	// validate the token
+	if err != nil || !token.Valid {
+		// The `jwt` library now automatically checks the "exp" claim
+		if err != nil {
+			// error might be on server side also. log it for safety purpose
+			log.Errorf("Invalid Token: %v", err)
+		}
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return claims, false
+	}
+
+	// a logged out or revoked session must not be usable anymore
+	err = authenticator.CheckSession(r.Context(), claims)
+	if err != nil {
+		authenticationError(w, err)
+		return claims, false
+	}
+
+	return claims, true
+}
+
+// authenticateAccessToken verifies the personal access token sent as a
+// bearer token
+func authenticateAccessToken(
+	w http.ResponseWriter,
+	r *http.Request,
+	authHeader string,
+) (service.UserCredentialClaims, bool) {
+	tokenParts := strings.SplitN(authHeader, " ", 2)
+	if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+		http.Error(w, "malformed authorization header", http.StatusUnauthorized)
+		return service.UserCredentialClaims{}, false
+	}
+
+	claims, err := authenticator.AuthenticateAccessToken(r.Context(), tokenParts[1])
+	if err != nil {
+		authenticationError(w, err)
+		return service.UserCredentialClaims{}, false
+	}
+
+	return claims, true
+}
+
+func authenticationError(w http.ResponseWriter, err error) {
+	if errors.Is(err, flux_errors.ErrUnAuthorized) {
+		log.Warn(err)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	http.Error(
+		w, "internal error. please try again later",
+		http.StatusInternalServerError,
+	)
+}
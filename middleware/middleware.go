@@ -7,5 +7,8 @@ package middleware
 
 const (
 	KeyJwtSessionCookieName            = "jwt_session"
+	KeyOIDCStateCookieName             = "oidc_state"
+	KeyRequestIDHeaderName             = "X-Request-ID"
+	KeyAuthorizationHeaderName         = "Authorization"
 	
 )
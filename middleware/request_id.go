@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+const maxRequestIDLength = 64
+
+// RequestIDMiddleware tags every request with an id, the one sent by the client
+// in X-Request-ID if it is usable. The id is sent back with the response and
+// recorded in the audit log with the changes made by the request.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(KeyRequestIDHeaderName)
+		if !isValidRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		w.Header().Set(KeyRequestIDHeaderName, requestID)
+
+		ctx := context.WithValue(r.Context(), service.KeyCtxRequestID, requestID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range requestID {
+		isAlphaNumeric := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !isAlphaNumeric && c != '-' && c != '_' && c != '.' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+-- name: CreateAuditLog :exec
+INSERT INTO audit_logs (
+    actor_id,
+    actor_user_name,
+    action,
+    entity_type,
+    entity_id,
+    before,
+    after,
+    request_id
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6, -- before: null for creations
+    $7, -- after: null for deletions
+    $8
+);
+
+-- name: GetAuditLogs :many
+SELECT * FROM audit_logs
+WHERE
+    (sqlc.narg('actor_user_name')::text IS NULL OR actor_user_name = sqlc.narg('actor_user_name')::text) AND
+    (sqlc.narg('action')::text IS NULL OR action = sqlc.narg('action')::text) AND
+    (sqlc.narg('entity_type')::text IS NULL OR entity_type = sqlc.narg('entity_type')::text) AND
+    (sqlc.narg('entity_id')::text IS NULL OR entity_id = sqlc.narg('entity_id')::text) AND
+    (sqlc.narg('request_id')::text IS NULL OR request_id = sqlc.narg('request_id')::text) AND
+    (sqlc.narg('from')::timestamptz IS NULL OR created_at >= sqlc.narg('from')::timestamptz) AND
+    (sqlc.narg('to')::timestamptz IS NULL OR created_at < sqlc.narg('to')::timestamptz)
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
@@ -0,0 +1,30 @@
+-- name: RecordAuthAttempt :one
+INSERT INTO auth_attempts (
+    action,
+    subject
+) VALUES (
+    sqlc.arg('action'),
+    sqlc.arg('subject')
+)
+ON CONFLICT (action, subject) DO UPDATE
+SET attempts = CASE
+        WHEN auth_attempts.last_attempt_at < sqlc.arg('forget_before') THEN 1
+        ELSE auth_attempts.attempts + 1
+    END,
+    last_attempt_at = NOW()
+RETURNING *;
+
+-- name: BlockAuthAttempts :exec
+UPDATE auth_attempts SET blocked_until = $3
+WHERE action = $1 AND subject = $2;
+
+-- name: ClearAuthAttempts :exec
+DELETE FROM auth_attempts WHERE action = $1 AND subject = $2;
+
+-- name: RefundAuthAttempt :exec
+UPDATE auth_attempts SET attempts = attempts - 1
+WHERE action = $1 AND subject = $2 AND attempts > 0;
+
+-- name: DeleteStaleAuthAttempts :exec
+DELETE FROM auth_attempts
+WHERE last_attempt_at < $1 AND (blocked_until IS NULL OR blocked_until < NOW());
@@ -0,0 +1,67 @@
+-- name: CreateBracket :one
+INSERT INTO tournament_brackets (
+    tournament_id,
+    seeding,
+    problem_pool,
+    problems_per_match,
+    match_duration_minutes,
+    size,
+    created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetBracket :one
+SELECT * FROM tournament_brackets WHERE tournament_id=$1;
+
+-- name: LockBracket :one
+-- serializes the changes to the matches of the bracket
+SELECT * FROM tournament_brackets WHERE tournament_id=$1 FOR UPDATE;
+
+-- name: AddBracketParticipant :exec
+INSERT INTO bracket_participants (
+    tournament_id,
+    user_id,
+    seed
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: GetBracketParticipants :many
+SELECT * FROM bracket_participants WHERE tournament_id=$1 ORDER BY seed;
+
+-- name: CreateBracketMatch :exec
+INSERT INTO bracket_matches (
+    tournament_id,
+    round_number,
+    match_number,
+    player1_id,
+    player2_id,
+    winner_id,
+    status
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+);
+
+-- name: GetBracketMatches :many
+SELECT * FROM bracket_matches WHERE tournament_id=$1 ORDER BY round_number, match_number;
+
+-- name: GetBracketRoundMatches :many
+SELECT * FROM bracket_matches
+WHERE tournament_id=$1 AND round_number=$2
+ORDER BY match_number;
+
+-- name: GetBracketMatchByContest :one
+SELECT * FROM bracket_matches WHERE contest_id=$1;
+
+-- name: SetBracketMatchContest :exec
+UPDATE bracket_matches SET contest_id=$2, status='scheduled' WHERE id=$1;
+
+-- name: FinishBracketMatch :exec
+UPDATE bracket_matches SET
+    player1_score=$2,
+    player2_score=$3,
+    winner_id=$4,
+    status='finished'
+WHERE id=$1;
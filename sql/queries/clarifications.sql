@@ -0,0 +1,43 @@
+-- name: CreateClarification :one
+INSERT INTO clarifications (
+    contest_id,
+    problem_id,
+    asked_by,
+    question
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+RETURNING *;
+
+-- name: GetClarificationByID :one
+SELECT * FROM clarifications WHERE id=$1;
+
+-- name: AnswerClarification :one
+UPDATE clarifications SET
+    answer=$2,
+    answered_by=$3,
+    is_broadcast=$4,
+    answered_at=NOW()
+WHERE id=$1
+RETURNING *;
+
+-- name: GetContestClarifications :many
+SELECT * FROM clarifications
+WHERE
+    contest_id = sqlc.arg('contest_id')
+AND
+    -- Optional filter by the participant, broadcasts are visible to everyone
+    (sqlc.narg('asked_by')::uuid IS NULL OR asked_by = sqlc.narg('asked_by')::uuid OR is_broadcast)
+AND
+    -- Optional filter used for polling updates
+    (sqlc.narg('updated_since')::timestamptz IS NULL OR updated_at > sqlc.narg('updated_since')::timestamptz)
+ORDER BY
+    updated_at;
+
+-- name: GetUnansweredClarifications :many
+SELECT * FROM clarifications
+WHERE contest_id=$1 AND answer IS NULL
+ORDER BY created_at;
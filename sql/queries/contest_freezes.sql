@@ -0,0 +1,36 @@
+-- name: SetContestFreeze :one
+INSERT INTO contest_freezes (
+    contest_id,
+    freeze_minutes
+) VALUES (
+    $1,
+    $2
+)
+ON CONFLICT (contest_id) DO UPDATE SET
+    freeze_minutes = EXCLUDED.freeze_minutes,
+    unfrozen_at = NULL
+RETURNING *;
+
+-- name: GetContestFreeze :one
+SELECT * FROM contest_freezes WHERE contest_id=$1;
+
+-- name: DeleteContestFreeze :exec
+DELETE FROM contest_freezes WHERE contest_id=$1;
+
+-- name: UnfreezeContest :one
+UPDATE contest_freezes SET unfrozen_at=NOW() WHERE contest_id=$1 RETURNING *;
+
+-- name: RevealFrozenResult :exec
+INSERT INTO contest_freeze_reveals (
+    contest_id,
+    user_id,
+    problem_id
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+ON CONFLICT DO NOTHING;
+
+-- name: GetFrozenResultReveals :many
+SELECT user_id, problem_id FROM contest_freeze_reveals WHERE contest_id=$1;
@@ -0,0 +1,72 @@
+-- name: SetContestRegistrationSettings :one
+INSERT INTO contest_registration_settings (
+    contest_id,
+    registration_start,
+    registration_end,
+    capacity,
+    requires_approval
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT (contest_id) DO UPDATE SET
+    registration_start = EXCLUDED.registration_start,
+    registration_end = EXCLUDED.registration_end,
+    capacity = EXCLUDED.capacity,
+    requires_approval = EXCLUDED.requires_approval
+RETURNING *;
+
+-- name: GetContestRegistrationSettings :one
+SELECT * FROM contest_registration_settings WHERE contest_id=$1;
+
+-- name: LockContestRegistrationSettings :one
+SELECT * FROM contest_registration_settings WHERE contest_id=$1 FOR UPDATE;
+
+-- name: DeleteContestRegistrationSettings :exec
+DELETE FROM contest_registration_settings WHERE contest_id=$1;
+
+-- name: CountContestRegisteredUsers :one
+SELECT COUNT(*) FROM contest_registered_users WHERE contest_id=$1;
+
+-- name: UnregisterUserFromContest :exec
+DELETE FROM contest_registered_users WHERE contest_id=$1 AND user_id=$2;
+
+-- name: CreateRegistrationRequest :one
+INSERT INTO contest_registration_requests (
+    contest_id,
+    user_id,
+    status
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+RETURNING *;
+
+-- name: GetRegistrationRequest :one
+SELECT * FROM contest_registration_requests WHERE contest_id=$1 AND user_id=$2;
+
+-- name: GetContestRegistrationRequests :many
+SELECT * FROM contest_registration_requests
+WHERE
+    contest_id = sqlc.arg('contest_id')
+AND
+    -- Optional filter by status
+    (sqlc.narg('status')::registration_status IS NULL OR status = sqlc.narg('status')::registration_status)
+ORDER BY
+    requested_at;
+
+-- name: GetFirstWaitlistedRequest :one
+SELECT * FROM contest_registration_requests
+WHERE contest_id=$1 AND status='waitlisted'
+ORDER BY requested_at
+LIMIT 1;
+
+-- name: UpdateRegistrationRequestStatus :one
+UPDATE contest_registration_requests SET status=$3 WHERE contest_id=$1 AND user_id=$2 RETURNING *;
+
+-- name: DeleteRegistrationRequest :exec
+DELETE FROM contest_registration_requests WHERE contest_id=$1 AND user_id=$2;
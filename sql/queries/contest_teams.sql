@@ -0,0 +1,52 @@
+-- name: SetContestTeamSettings :one
+INSERT INTO contest_team_settings (
+    contest_id,
+    max_team_size
+) VALUES (
+    $1,
+    $2
+)
+ON CONFLICT (contest_id) DO UPDATE SET
+    max_team_size = EXCLUDED.max_team_size
+RETURNING *;
+
+-- name: GetContestTeamSettings :one
+SELECT * FROM contest_team_settings WHERE contest_id=$1;
+
+-- name: DeleteContestTeamSettings :exec
+DELETE FROM contest_team_settings WHERE contest_id=$1;
+
+-- name: AddContestTeamMember :exec
+INSERT INTO contest_team_members (
+    contest_id,
+    team_id,
+    user_id
+) VALUES (
+    $1,
+    $2,
+    $3
+);
+
+-- name: GetContestTeamMembers :many
+SELECT ctm.team_id, ctm.user_id, t.name FROM contest_team_members ctm
+JOIN teams t ON ctm.team_id = t.id
+WHERE ctm.contest_id=$1
+ORDER BY t.name;
+
+-- name: IsTeamRegisteredInContest :one
+SELECT EXISTS(
+    SELECT contest_id, team_id FROM
+     contest_team_members WHERE contest_id=$1 AND team_id=$2
+);
+
+-- name: IsUserInContestTeam :one
+SELECT EXISTS(
+    SELECT contest_id, user_id FROM
+     contest_team_members WHERE contest_id=$1 AND user_id=$2
+);
+
+-- name: DeleteContestTeam :exec
+DELETE FROM contest_team_members WHERE contest_id=$1 AND team_id=$2;
+
+-- name: DeleteContestTeams :exec
+DELETE FROM contest_team_members WHERE contest_id=$1;
@@ -34,7 +34,7 @@ RETURNING *;
 -- name: UnRegisterContestUsers :exec
 DELETE FROM contest_registered_users WHERE contest_id=$1;
 
--- name: RegisterUserToContest :one
+-- name: RegisterUserToContest :execrows
 INSERT INTO contest_registered_users (
     user_id,
     contest_id
@@ -42,7 +42,7 @@ INSERT INTO contest_registered_users (
     $1,
     $2
 )
-RETURNING *;
+ON CONFLICT (user_id, contest_id) DO NOTHING;
 
 -- name: DeleteProblemsByContestId :exec
 DELETE FROM contest_problems WHERE contest_id = $1;
@@ -53,7 +53,7 @@ DELETE FROM contest_registered_users WHERE contest_id = $1;
 -- name: IsUserRegisteredInContest :one
 SELECT EXISTS(
     SELECT contest_id, user_id FROM
-     contest_registered_users WHERE contest_id=$1 AND user_id=$1
+     contest_registered_users WHERE contest_id=$1 AND user_id=$2
 );
 
 -- name: GetContestByID :one
@@ -121,4 +121,7 @@ WHERE id=$4
 RETURNING *;
 
 -- name: DeleteContestByID :exec
-DELETE FROM contests WHERE id=$1;
\ No newline at end of file
+DELETE FROM contests WHERE id=$1;
+
+-- name: PublishContest :exec
+UPDATE contests SET is_published=true WHERE id=$1;
@@ -1,2 +1,9 @@
 -- name: GetManagerMailIDs :many
-SELECT email FROM users u JOIN user_roles ur ON u.id = ur.user_id WHERE role_name=$1;
\ No newline at end of file
+SELECT email FROM users u JOIN user_roles ur ON u.id = ur.user_id WHERE role_name=$1;
+
+-- name: GetContestParticipantMailIDs :many
+SELECT email FROM users u WHERE id IN (
+    SELECT user_id FROM contest_registered_users WHERE contest_id=$1
+    UNION
+    SELECT user_id FROM contest_team_members WHERE contest_id=$1
+) AND COALESCE((SELECT contest_emails FROM user_notification_settings s WHERE s.user_id = u.id), TRUE);
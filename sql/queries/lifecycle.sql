@@ -0,0 +1,58 @@
+-- name: SyncContestLifecycleJobs :exec
+-- schedules the jobs of the upcoming events and reschedules the existing ones
+-- whose time has changed
+INSERT INTO contest_lifecycle_jobs (
+    contest_id,
+    event,
+    run_at
+)
+SELECT e.contest_id, e.event, e.run_at FROM (
+    SELECT id AS contest_id, 'contest_start'::lifecycle_event AS event, start_time AS run_at
+    FROM contests WHERE start_time IS NOT NULL
+    UNION ALL
+    SELECT id, 'contest_end'::lifecycle_event, end_time FROM contests
+    UNION ALL
+    SELECT c.id, 'lock_timeout'::lifecycle_event, l.timeout
+    FROM contests c JOIN locks l ON c.lock_id = l.id
+    WHERE l.timeout IS NOT NULL
+) e
+WHERE e.run_at > NOW() OR EXISTS (
+    SELECT 1 FROM contest_lifecycle_jobs j
+    WHERE j.contest_id = e.contest_id AND j.event = e.event
+)
+ON CONFLICT (contest_id, event) DO UPDATE SET
+    run_at = EXCLUDED.run_at,
+    status = 'pending',
+    attempts = 0,
+    last_error = NULL
+WHERE contest_lifecycle_jobs.run_at <> EXCLUDED.run_at
+    AND contest_lifecycle_jobs.status <> 'running';
+
+-- name: ResetRunningLifecycleJobs :exec
+UPDATE contest_lifecycle_jobs SET status='pending' WHERE status='running';
+
+-- name: ClaimDueLifecycleJobs :many
+UPDATE contest_lifecycle_jobs SET
+    status='running',
+    attempts=attempts + 1
+WHERE id IN (
+    SELECT id FROM contest_lifecycle_jobs
+    WHERE status='pending' AND run_at <= NOW()
+    ORDER BY run_at
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteLifecycleJob :exec
+UPDATE contest_lifecycle_jobs SET status='done', last_error=NULL WHERE id=$1;
+
+-- name: FailLifecycleJob :exec
+UPDATE contest_lifecycle_jobs SET
+    status=$2,
+    run_at=$3,
+    last_error=$4
+WHERE id=$1;
+
+-- name: GetContestLifecycleJobs :many
+SELECT * FROM contest_lifecycle_jobs WHERE contest_id=$1 ORDER BY run_at;
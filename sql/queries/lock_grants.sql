@@ -0,0 +1,42 @@
+-- name: CreateLockGrant :one
+INSERT INTO lock_grants (
+    lock_id,
+    user_id,
+    team_id,
+    permission,
+    granted_by
+) VALUES (
+    $1, -- lock_id
+    $2, -- user_id: not null only if granted to a user
+    $3, -- team_id: not null only if granted to a team
+    $4, -- permission: view or edit
+    $5  -- granted_by
+)
+RETURNING *;
+
+-- name: UpdateLockGrantPermission :one
+UPDATE lock_grants SET permission = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: GetLockGrantByID :one
+SELECT * FROM lock_grants WHERE id = $1;
+
+-- name: GetLockGrants :many
+SELECT g.*, u.user_name, t.name AS team_name FROM lock_grants g
+LEFT JOIN users u ON g.user_id = u.id
+LEFT JOIN teams t ON g.team_id = t.id
+WHERE g.lock_id = $1
+ORDER BY g.created_at;
+
+-- name: GetUserLockGrantPermissions :many
+-- permissions the user holds on the lock, directly or through their teams
+SELECT permission FROM lock_grants
+WHERE lock_id = $1
+    AND (
+        user_id = $2
+        OR team_id IN (SELECT team_id FROM team_members WHERE team_members.user_id = $2)
+    );
+
+-- name: DeleteLockGrant :exec
+DELETE FROM lock_grants WHERE id = $1;
@@ -4,13 +4,15 @@ INSERT INTO locks (
     created_by,
     description,
     lock_type,
-    timeout
+    timeout,
+    operator
 ) VALUES (
     $1, -- name
     $2, -- created_by
     $3, -- description
-    $4, -- lock_type: either timer or manual
-    $5  -- timeout: null only if manual
+    $4, -- lock_type: timer, manual or composite
+    $5, -- timeout: not null only if timer
+    $6  -- operator: not null only if composite
 )
 RETURNING *;
 
@@ -43,4 +45,31 @@ OFFSET sqlc.arg('offset');
 
 -- name: DeleteLockById :exec
 DELETE FROM locks 
-WHERE id=$1;
\ No newline at end of file
+WHERE id=$1;
+
+-- name: ReleaseLock :one
+UPDATE locks SET released_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: AddLockChild :exec
+INSERT INTO lock_children (parent_id, child_id)
+VALUES ($1, $2);
+
+-- name: AddLockContestDependency :exec
+INSERT INTO lock_contest_dependencies (lock_id, contest_id)
+VALUES ($1, $2);
+
+-- name: GetLockChildren :many
+SELECT l.* FROM locks l
+JOIN lock_children lc ON l.id = lc.child_id
+WHERE lc.parent_id = $1;
+
+-- name: GetLockContestDependencies :many
+SELECT c.id, c.end_time FROM lock_contest_dependencies d
+JOIN contests c ON d.contest_id = c.id
+WHERE d.lock_id = $1;
+
+-- name: GetContestEndTimes :many
+SELECT id, end_time FROM contests
+WHERE id = ANY(sqlc.arg(contest_ids)::uuid[]);
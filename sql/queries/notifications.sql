@@ -0,0 +1,58 @@
+-- name: UpsertNotificationSettings :one
+INSERT INTO user_notification_settings (
+    user_id,
+    contest_emails
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (user_id) DO UPDATE SET
+    contest_emails = EXCLUDED.contest_emails
+RETURNING *;
+
+-- name: GetNotificationSettings :one
+SELECT * FROM user_notification_settings WHERE user_id=$1;
+
+-- name: GetContestParticipantRecipients :many
+-- users without settings have not opted out of the contest mails
+SELECT id, email FROM users u WHERE id IN (
+    SELECT user_id FROM contest_registered_users WHERE contest_id=$1
+    UNION
+    SELECT user_id FROM contest_team_members WHERE contest_id=$1
+) AND COALESCE((SELECT contest_emails FROM user_notification_settings s WHERE s.user_id = u.id), TRUE);
+
+-- name: GetRegisteredUserRecipients :many
+SELECT id, email FROM users u WHERE id IN (
+    SELECT user_id FROM contest_registered_users WHERE contest_id=$1
+) AND COALESCE((SELECT contest_emails FROM user_notification_settings s WHERE s.user_id = u.id), TRUE);
+
+-- name: GetUserRecipients :many
+SELECT id, email FROM users u WHERE id = ANY(sqlc.arg(user_ids)::UUID[])
+AND COALESCE((SELECT contest_emails FROM user_notification_settings s WHERE s.user_id = u.id), TRUE);
+
+-- name: CreateContestReminder :exec
+INSERT INTO contest_reminders (
+    contest_id,
+    offset_minutes
+) VALUES (
+    $1, $2
+);
+
+-- name: DeleteContestReminders :exec
+DELETE FROM contest_reminders WHERE contest_id=$1;
+
+-- name: GetContestReminders :many
+SELECT * FROM contest_reminders WHERE contest_id=$1 ORDER BY offset_minutes DESC;
+
+-- name: ClaimDueContestReminders :many
+-- marks the due reminders as sent, so that every reminder is mailed only once
+UPDATE contest_reminders r SET sent_at = NOW()
+FROM (
+    -- public contests start when their lock expires
+    SELECT c.id, COALESCE(l.timeout, c.start_time) AS start_time
+    FROM contests c LEFT JOIN locks l ON c.lock_id = l.id
+) c
+WHERE r.contest_id = c.id
+    AND r.sent_at IS NULL
+    AND c.start_time > NOW()
+    AND c.start_time - make_interval(mins => r.offset_minutes) <= NOW()
+RETURNING r.contest_id, r.offset_minutes;
@@ -0,0 +1,40 @@
+-- name: CreateOIDCLoginState :exec
+INSERT INTO oidc_login_states (
+    state,
+    code_verifier,
+    nonce,
+    remember_for_month,
+    expires_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+);
+
+-- name: ConsumeOIDCLoginState :one
+DELETE FROM oidc_login_states WHERE state = $1
+RETURNING *;
+
+-- name: DeleteExpiredOIDCLoginStates :exec
+DELETE FROM oidc_login_states WHERE expires_at < NOW();
+
+-- name: GetUserIdentity :one
+SELECT * FROM user_identities WHERE issuer = $1 AND subject = $2;
+
+-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (
+    issuer,
+    subject,
+    user_id,
+    email
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+);
+
+-- name: GetUserIDByEmail :one
+SELECT id FROM users WHERE LOWER(email) = LOWER(sqlc.arg('email'));
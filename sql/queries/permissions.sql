@@ -0,0 +1,23 @@
+-- name: GetPermissions :many
+SELECT * FROM permissions ORDER BY name;
+
+-- name: GetUserPermissions :many
+SELECT DISTINCT rp.permission FROM role_permissions rp
+JOIN user_roles ur ON rp.role_name = ur.role_name
+WHERE ur.user_id = $1
+ORDER BY rp.permission;
+
+-- name: GetRolePermissions :many
+SELECT permission FROM role_permissions
+WHERE role_name = $1
+ORDER BY permission;
+
+-- name: GetAllRolePermissions :many
+SELECT * FROM role_permissions ORDER BY role_name, permission;
+
+-- name: DeleteRolePermissions :exec
+DELETE FROM role_permissions WHERE role_name = $1;
+
+-- name: AddRolePermissions :exec
+INSERT INTO role_permissions (role_name, permission)
+SELECT sqlc.arg('role_name')::varchar, unnest(sqlc.arg('permissions')::varchar[]);
@@ -0,0 +1,34 @@
+-- name: CreatePersonalAccessToken :one
+INSERT INTO personal_access_tokens (
+    user_id,
+    name,
+    hashed_token,
+    scopes,
+    expires_at
+) VALUES (
+    $1,
+    $2,
+    $3, -- hashed_token: sha256 of the token
+    $4,
+    $5
+)
+RETURNING *;
+
+-- name: GetUserPersonalAccessTokens :many
+SELECT * FROM personal_access_tokens
+WHERE user_id = $1 AND revoked_at IS NULL
+ORDER BY created_at DESC;
+
+-- name: UsePersonalAccessToken :one
+UPDATE personal_access_tokens t SET last_used_at = NOW()
+FROM users u
+WHERE t.hashed_token = $1
+    AND t.user_id = u.id
+    AND t.revoked_at IS NULL
+    AND t.expires_at > NOW()
+RETURNING t.*, u.user_name;
+
+-- name: RevokePersonalAccessToken :one
+UPDATE personal_access_tokens SET revoked_at = NOW()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+RETURNING *;
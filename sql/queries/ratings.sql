@@ -0,0 +1,61 @@
+-- name: SetContestRated :exec
+INSERT INTO contest_ratings (
+    contest_id
+) VALUES (
+    $1
+)
+ON CONFLICT DO NOTHING;
+
+-- name: GetContestRating :one
+SELECT * FROM contest_ratings WHERE contest_id=$1;
+
+-- name: DeleteContestRating :exec
+DELETE FROM contest_ratings WHERE contest_id=$1;
+
+-- name: FinalizeContestRating :one
+UPDATE contest_ratings SET finalized_at=NOW() WHERE contest_id=$1 RETURNING *;
+
+-- name: GetFinalizedRatedContestsSince :many
+SELECT c.id, c.end_time FROM contest_ratings cr
+JOIN contests c ON cr.contest_id = c.id
+WHERE cr.finalized_at IS NOT NULL AND c.end_time >= $1
+ORDER BY c.end_time, c.id;
+
+-- name: DeleteRatingChangesSince :exec
+DELETE FROM rating_changes rc
+USING contests c
+WHERE rc.contest_id = c.id AND c.end_time >= $1;
+
+-- name: GetRatingsBefore :many
+SELECT DISTINCT ON (rc.user_id) rc.user_id, rc.new_rating FROM rating_changes rc
+JOIN contests c ON rc.contest_id = c.id
+WHERE c.end_time < $1
+ORDER BY rc.user_id, c.end_time DESC;
+
+-- name: CreateRatingChange :exec
+INSERT INTO rating_changes (
+    contest_id,
+    user_id,
+    old_rating,
+    new_rating,
+    rank
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+);
+
+-- name: GetUserRatingHistory :many
+SELECT rc.contest_id, c.title, c.end_time, rc.old_rating, rc.new_rating, rc.rank FROM rating_changes rc
+JOIN contests c ON rc.contest_id = c.id
+WHERE rc.user_id=$1
+ORDER BY c.end_time;
+
+-- name: GetUserCurrentRating :one
+SELECT rc.new_rating FROM rating_changes rc
+JOIN contests c ON rc.contest_id = c.id
+WHERE rc.user_id=$1
+ORDER BY c.end_time DESC
+LIMIT 1;
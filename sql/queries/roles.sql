@@ -1,2 +1,46 @@
 -- name: GetUserRolesByUserName :many
-SELECT * FROM user_roles WHERE user_id = $1;
\ No newline at end of file
+SELECT * FROM user_roles WHERE user_id = $1;
+
+-- name: GetRoles :many
+SELECT r.*, COUNT(ur.user_id) AS member_count FROM roles r
+LEFT JOIN user_roles ur ON r.role_name = ur.role_name
+GROUP BY r.role_name
+ORDER BY r.role_name;
+
+-- name: CreateRole :one
+INSERT INTO roles (
+    role_name,
+    description,
+    created_by
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+RETURNING *;
+
+-- name: AddUserRole :one
+INSERT INTO user_roles (
+    user_id,
+    role_name,
+    granted_by
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+RETURNING *;
+
+-- name: RemoveUserRole :one
+DELETE FROM user_roles
+WHERE user_id = $1 AND role_name = $2
+RETURNING *;
+
+-- name: GetRoleMembers :many
+SELECT u.id, u.user_name, u.roll_no, ur.granted_by, ur.granted_at FROM user_roles ur
+JOIN users u ON ur.user_id = u.id
+WHERE ur.role_name = sqlc.arg('role_name')
+ORDER BY ur.granted_at
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
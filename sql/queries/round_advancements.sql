@@ -0,0 +1,22 @@
+-- name: SetRoundAdvancement :one
+INSERT INTO round_advancements (
+    round_id,
+    rule,
+    top_k,
+    min_score,
+    manual_user_ids
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+ON CONFLICT (round_id) DO UPDATE SET
+    rule = EXCLUDED.rule,
+    top_k = EXCLUDED.top_k,
+    min_score = EXCLUDED.min_score,
+    manual_user_ids = EXCLUDED.manual_user_ids
+RETURNING *;
+
+-- name: GetRoundAdvancement :one
+SELECT * FROM round_advancements WHERE round_id=$1;
+
+-- name: MarkRoundAdvanced :exec
+UPDATE round_advancements SET advanced_at=NOW() WHERE round_id=$1;
@@ -20,13 +20,15 @@ INSERT INTO submissions (
     contest_id,
     problem_id,
     solution,
-    state
+    state,
+    virtual_participation_id
 ) VALUES (
     $1,
     $2,
     $3,
     $4,
-    $5
+    $5,
+    $6
 )
 RETURNING *;
 
@@ -39,6 +41,9 @@ WHERE s.id = $1;
 -- name: UpdateSubmissionByID :one
 UPDATE submissions SET state=$2 WHERE id=$1 RETURNING *;
 
+-- name: SetSubmissionScore :exec
+UPDATE submissions SET score=$2 WHERE id=$1;
+
 -- name: InsertCfSubmission :one
 INSERT INTO cf_submissions
     (
@@ -91,8 +96,24 @@ WHERE cf_submissions.cf_sub_id = data.id;
 -- name: PollPendingSubmissions :many
 SELECT * FROM submissions WHERE state = ANY(sqlc.arg(pending_states)::VARCHAR[]);
 
+-- name: LockBot :one
+SELECT * FROM bots WHERE name=$1 FOR UPDATE;
+
 -- name: UpdateBot :one
 UPDATE bots SET cookies=$2 WHERE name=$1 RETURNING *;
 
 -- name: DeleteBot :exec
-DELETE FROM bots WHERE name=$1;
\ No newline at end of file
+DELETE FROM bots WHERE name=$1;
+
+-- name: GetContestSubmissions :many
+SELECT
+    id,
+    submitted_by,
+    problem_id,
+    state,
+    submitted_at,
+    virtual_participation_id,
+    score
+FROM submissions
+WHERE contest_id=$1
+ORDER BY submitted_at;
\ No newline at end of file
@@ -0,0 +1,42 @@
+-- name: CreateProblemSubtask :exec
+INSERT INTO problem_subtasks (
+    problem_id,
+    subtask_id,
+    points,
+    testcases,
+    depends_on
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+);
+
+-- name: GetProblemSubtasks :many
+SELECT * FROM problem_subtasks WHERE problem_id=$1 ORDER BY subtask_id;
+
+-- name: DeleteProblemSubtasks :exec
+DELETE FROM problem_subtasks WHERE problem_id=$1;
+
+-- name: GetProblemsSubtaskPoints :many
+SELECT problem_id, SUM(points)::INTEGER AS total_points FROM problem_subtasks
+WHERE problem_id = ANY(sqlc.arg(problem_ids)::INTEGER[])
+GROUP BY problem_id;
+
+-- name: CreateSubtaskResult :exec
+INSERT INTO submission_subtask_results (
+    submission_id,
+    subtask_id,
+    passed
+) VALUES (
+    $1,
+    $2,
+    $3
+);
+
+-- name: GetSubtaskResults :many
+SELECT * FROM submission_subtask_results WHERE submission_id=$1 ORDER BY subtask_id;
+
+-- name: DeleteSubtaskResults :exec
+DELETE FROM submission_subtask_results WHERE submission_id=$1;
@@ -0,0 +1,67 @@
+-- name: CreateTeam :one
+INSERT INTO teams (
+    name,
+    captain_id
+) VALUES (
+    $1,
+    $2
+)
+RETURNING *;
+
+-- name: GetTeamByID :one
+SELECT * FROM teams WHERE id=$1;
+
+-- name: GetTeamsByUser :many
+SELECT t.* FROM teams t
+JOIN team_members tm ON t.id = tm.team_id
+WHERE tm.user_id=$1
+ORDER BY t.name;
+
+-- name: DeleteTeam :exec
+DELETE FROM teams WHERE id=$1;
+
+-- name: AddTeamMember :one
+INSERT INTO team_members (
+    team_id,
+    user_id
+) VALUES (
+    $1,
+    $2
+)
+RETURNING *;
+
+-- name: GetTeamMembers :many
+SELECT * FROM team_members WHERE team_id=$1 ORDER BY joined_at;
+
+-- name: IsUserTeamMember :one
+SELECT EXISTS(
+    SELECT team_id, user_id FROM
+     team_members WHERE team_id=$1 AND user_id=$2
+);
+
+-- name: RemoveTeamMember :exec
+DELETE FROM team_members WHERE team_id=$1 AND user_id=$2;
+
+-- name: CreateTeamInvitation :one
+INSERT INTO team_invitations (
+    team_id,
+    user_id,
+    invited_by
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+RETURNING *;
+
+-- name: GetTeamInvitation :one
+SELECT * FROM team_invitations WHERE team_id=$1 AND user_id=$2;
+
+-- name: GetTeamInvitations :many
+SELECT * FROM team_invitations WHERE team_id=$1 ORDER BY created_at;
+
+-- name: GetUserTeamInvitations :many
+SELECT * FROM team_invitations WHERE user_id=$1 ORDER BY created_at;
+
+-- name: DeleteTeamInvitation :exec
+DELETE FROM team_invitations WHERE team_id=$1 AND user_id=$2;
@@ -0,0 +1,50 @@
+-- name: SetTournamentRoundScoring :one
+INSERT INTO tournament_round_scoring (
+    round_id,
+    weight,
+    best_of
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (round_id) DO UPDATE SET
+    weight = EXCLUDED.weight,
+    best_of = EXCLUDED.best_of
+RETURNING *;
+
+-- name: GetTournamentRoundsWithScoring :many
+SELECT
+    tr.id,
+    tr.round_number,
+    tr.title,
+    tr.lock_id,
+    tr.is_published,
+
+    -- lock fields
+    l.access,
+    l.timeout,
+
+    -- scoring fields
+    COALESCE(s.weight, 100)::int AS weight,
+    s.best_of,
+
+    -- advancement fields
+    a.advanced_at
+FROM
+    tournament_rounds tr
+LEFT JOIN
+    locks l ON tr.lock_id = l.id
+LEFT JOIN
+    tournament_round_scoring s ON tr.id = s.round_id
+LEFT JOIN
+    round_advancements a ON tr.id = a.round_id
+WHERE
+    tr.tournament_id = $1
+ORDER BY
+    tr.round_number;
+
+-- name: GetRoundParticipantIDs :many
+SELECT user_id FROM contest_registered_users
+WHERE contest_id IN (SELECT contest_id FROM tournament_contests WHERE round_id=$1)
+UNION
+SELECT user_id FROM contest_team_members
+WHERE contest_id IN (SELECT contest_id FROM tournament_contests WHERE round_id=$1);
@@ -70,6 +70,7 @@ SELECT
     tr.title,
     tr.lock_id,
     tr.created_by,
+    tr.is_published,
 
     -- lock fields
     l.access,
@@ -120,4 +121,50 @@ ORDER BY
 LIMIT
     sqlc.arg('limit')
 OFFSET
-    sqlc.arg('offset');
\ No newline at end of file
+    sqlc.arg('offset');
+
+-- name: UpdateTournament :one
+UPDATE tournaments SET title = $2 WHERE id = $1
+RETURNING *;
+
+-- name: SetTournamentPublished :exec
+UPDATE tournaments SET is_published = $2 WHERE id = $1;
+
+-- name: DeleteTournament :exec
+DELETE FROM tournaments WHERE id = $1;
+
+-- name: CountTournamentAttachedContests :one
+-- contests of the rounds and of the bracket matches of the tournament
+SELECT (
+    SELECT COUNT(*)
+    FROM tournament_contests tc
+    JOIN tournament_rounds tr ON tc.round_id = tr.id
+    WHERE tr.tournament_id = $1
+) + (
+    SELECT COUNT(*)
+    FROM bracket_matches bm
+    WHERE bm.tournament_id = $1 AND bm.contest_id IS NOT NULL
+);
+
+-- name: UpdateTournamentRound :exec
+UPDATE tournament_rounds SET title = $2, lock_id = $3 WHERE id = $1;
+
+-- name: SetTournamentRoundPublished :exec
+UPDATE tournament_rounds SET is_published = $2 WHERE id = $1;
+
+-- name: DeleteTournamentRound :exec
+DELETE FROM tournament_rounds WHERE id = $1;
+
+-- name: DeleteTournamentRounds :exec
+DELETE FROM tournament_rounds WHERE tournament_id = $1;
+
+-- name: ShiftTournamentRoundsDown :exec
+-- closes the gap left by a deleted round
+UPDATE tournament_rounds SET round_number = round_number - 1
+WHERE tournament_id = $1 AND round_number > $2;
+
+-- name: ReorderTournamentRounds :exec
+-- the round at position i of the ids becomes the round i
+UPDATE tournament_rounds tr SET round_number = o.round_number
+FROM unnest(sqlc.arg(round_ids)::uuid[]) WITH ORDINALITY AS o(id, round_number)
+WHERE tr.id = o.id AND tr.tournament_id = sqlc.arg(tournament_id);
@@ -0,0 +1,45 @@
+-- name: CreatePendingUserTOTP :one
+INSERT INTO user_totp (
+    user_id,
+    secret
+) VALUES (
+    $1,
+    $2
+)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret, last_used_step = 0, created_at = NOW()
+WHERE user_totp.enabled_at IS NULL
+RETURNING *;
+
+-- name: GetUserTOTP :one
+SELECT * FROM user_totp WHERE user_id = $1;
+
+-- name: EnableUserTOTP :one
+UPDATE user_totp SET enabled_at = NOW(), last_used_step = $2
+WHERE user_id = $1 AND enabled_at IS NULL
+RETURNING *;
+
+-- name: UseUserTOTPStep :one
+UPDATE user_totp SET last_used_step = $2
+WHERE user_id = $1 AND enabled_at IS NOT NULL AND last_used_step < $2
+RETURNING *;
+
+-- name: DeleteUserTOTP :one
+DELETE FROM user_totp WHERE user_id = $1
+RETURNING *;
+
+-- name: AddUserRecoveryCodes :exec
+INSERT INTO user_recovery_codes (user_id, hashed_code)
+SELECT sqlc.arg('user_id')::uuid, unnest(sqlc.arg('hashed_codes')::varchar[]);
+
+-- name: DeleteUserRecoveryCodes :exec
+DELETE FROM user_recovery_codes WHERE user_id = $1;
+
+-- name: UseUserRecoveryCode :one
+UPDATE user_recovery_codes SET used_at = NOW()
+WHERE user_id = $1 AND hashed_code = $2 AND used_at IS NULL
+RETURNING *;
+
+-- name: CountUnusedUserRecoveryCodes :one
+SELECT COUNT(*) FROM user_recovery_codes
+WHERE user_id = $1 AND used_at IS NULL;
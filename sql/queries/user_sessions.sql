@@ -0,0 +1,33 @@
+-- name: CreateUserSession :one
+INSERT INTO user_sessions (
+    id,
+    user_id,
+    user_agent,
+    ip_address,
+    expires_at
+) VALUES (
+    $1, -- id: jti of the token
+    $2,
+    $3,
+    $4,
+    $5
+)
+RETURNING *;
+
+-- name: GetUserSessionByID :one
+SELECT * FROM user_sessions WHERE id = $1;
+
+-- name: GetActiveUserSessions :many
+SELECT * FROM user_sessions
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+ORDER BY created_at DESC;
+
+-- name: RevokeUserSession :one
+UPDATE user_sessions SET revoked_at = NOW()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeUserSessions :many
+UPDATE user_sessions SET revoked_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+RETURNING id;
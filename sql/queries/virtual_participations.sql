@@ -0,0 +1,28 @@
+-- name: CreateVirtualParticipation :one
+INSERT INTO virtual_participations (
+    contest_id,
+    user_id,
+    start_time,
+    end_time
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+RETURNING *;
+
+-- name: GetVirtualParticipationByID :one
+SELECT * FROM virtual_participations WHERE id=$1;
+
+-- name: GetUserVirtualParticipation :one
+SELECT * FROM virtual_participations WHERE contest_id=$1 AND user_id=$2;
+
+-- name: GetContestVirtualParticipations :many
+SELECT * FROM virtual_participations WHERE contest_id=$1;
+
+-- name: HasUserParticipatedInContest :one
+SELECT EXISTS(
+    SELECT 1 FROM submissions
+    WHERE contest_id=$1 AND submitted_by=$2 AND virtual_participation_id IS NULL
+);
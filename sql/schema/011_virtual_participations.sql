@@ -0,0 +1,33 @@
+-- +goose up
+-- Virtual Participations Table
+-- A virtual participation is a personal run of an already ended contest.
+-- It has the same duration as the original contest but starts whenever the user wants.
+CREATE TABLE virtual_participations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    contest_id UUID NOT NULL REFERENCES contests(id), -- The contest being replayed
+    user_id UUID NOT NULL REFERENCES users(id), -- The user who is participating virtually
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL, -- When the personal run begins
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL, -- When the personal run ends
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    -- a user can replay a contest only once
+    CONSTRAINT uq_virtual_participation_user UNIQUE (contest_id, user_id),
+    CONSTRAINT chk_virtual_participation_time CHECK (start_time < end_time)
+);
+
+-- indexes for common lookup fields
+CREATE INDEX idx_virtual_participations_contest_id ON virtual_participations(contest_id);
+CREATE INDEX idx_virtual_participations_user_id ON virtual_participations(user_id);
+
+-- submissions made during a virtual participation are tagged with it
+ALTER TABLE submissions
+    ADD COLUMN virtual_participation_id UUID REFERENCES virtual_participations(id) DEFAULT NULL;
+
+CREATE INDEX idx_submissions_virtual_participation_id ON submissions(virtual_participation_id);
+
+-- +goose Down
+DROP INDEX idx_submissions_virtual_participation_id;
+ALTER TABLE submissions DROP COLUMN virtual_participation_id;
+DROP INDEX idx_virtual_participations_user_id;
+DROP INDEX idx_virtual_participations_contest_id;
+DROP TABLE virtual_participations;
@@ -0,0 +1,34 @@
+-- +goose up
+-- Clarifications Table
+-- Questions asked by participants during a contest and the answers given by the jury.
+CREATE TABLE clarifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    contest_id UUID NOT NULL REFERENCES contests(id), -- The contest this question is asked in
+    problem_id INTEGER DEFAULT NULL, -- The problem this question is about (optional)
+    asked_by UUID NOT NULL REFERENCES users(id), -- The participant who asked the question
+    question TEXT NOT NULL,
+    answer TEXT DEFAULT NULL, -- NULL until the jury answers
+    answered_by UUID REFERENCES users(id) DEFAULT NULL,
+    is_broadcast BOOLEAN NOT NULL DEFAULT FALSE, -- Is the answer visible to every participant?
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    answered_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+
+    -- a question can only be tied to a problem of the same contest
+    CONSTRAINT fk_clarifications_contest_problem
+        FOREIGN KEY (contest_id, problem_id)
+        REFERENCES contest_problems(contest_id, problem_id)
+);
+
+-- indexes for common lookup fields
+CREATE INDEX idx_clarifications_contest_id ON clarifications(contest_id);
+CREATE INDEX idx_clarifications_asked_by ON clarifications(asked_by);
+
+-- A trigger to automatically update 'updated_at' on every row modification
+CREATE TRIGGER update_clarifications_updated_at BEFORE UPDATE ON clarifications FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- +goose Down
+DROP TRIGGER update_clarifications_updated_at ON clarifications;
+DROP INDEX idx_clarifications_asked_by;
+DROP INDEX idx_clarifications_contest_id;
+DROP TABLE clarifications;
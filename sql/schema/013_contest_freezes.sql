@@ -0,0 +1,33 @@
+-- +goose up
+-- Contest Freezes Table
+-- Standings of a contest are frozen for the last freeze_minutes of the contest.
+-- Verdicts of the submissions made after the freeze are hidden from the participants
+-- until the contest is unfrozen.
+CREATE TABLE contest_freezes (
+    contest_id UUID PRIMARY KEY REFERENCES contests(id),
+    freeze_minutes INTEGER NOT NULL, -- Minutes before the end of the contest when the standings freeze
+    unfrozen_at TIMESTAMP WITH TIME ZONE DEFAULT NULL, -- Set once every result is revealed
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_contest_freeze_minutes CHECK (freeze_minutes > 0)
+);
+
+-- A trigger to automatically update 'updated_at' on every row modification
+CREATE TRIGGER update_contest_freezes_updated_at BEFORE UPDATE ON contest_freezes FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Contest Freeze Reveals Table
+-- Results of a frozen standings revealed one (user, problem) pair at a time by the resolver.
+CREATE TABLE contest_freeze_reveals (
+    contest_id UUID NOT NULL REFERENCES contest_freezes(contest_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    problem_id INTEGER NOT NULL REFERENCES problems(id),
+    revealed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (contest_id, user_id, problem_id)
+);
+
+-- +goose Down
+DROP TABLE contest_freeze_reveals;
+DROP TRIGGER update_contest_freezes_updated_at ON contest_freezes;
+DROP TABLE contest_freezes;
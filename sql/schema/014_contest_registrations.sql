@@ -0,0 +1,41 @@
+-- +goose up
+CREATE TYPE registration_status AS ENUM ('pending', 'waitlisted', 'rejected');
+
+-- Contest Registration Settings Table
+-- A contest having these settings allows users to register themselves within the window
+CREATE TABLE contest_registration_settings (
+    contest_id UUID PRIMARY KEY REFERENCES contests(id) ON DELETE CASCADE,
+    registration_start TIMESTAMP WITH TIME ZONE NOT NULL, -- Self registration opens at
+    registration_end TIMESTAMP WITH TIME ZONE NOT NULL, -- Self registration closes at
+    capacity INTEGER DEFAULT NULL, -- Maximum registered users, NULL means unlimited
+    requires_approval BOOLEAN NOT NULL DEFAULT FALSE, -- Does the creator have to approve every request?
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_registration_window CHECK (registration_start < registration_end),
+    CONSTRAINT chk_registration_capacity CHECK (capacity IS NULL OR capacity > 0)
+);
+
+-- A trigger to automatically update 'updated_at' on every row modification
+CREATE TRIGGER update_contest_registration_settings_updated_at BEFORE UPDATE ON contest_registration_settings FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Contest Registration Requests Table
+-- Requests that are not yet turned into a registration. Approved requests are
+-- moved into contest_registered_users.
+CREATE TABLE contest_registration_requests (
+    contest_id UUID NOT NULL REFERENCES contests(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    status registration_status NOT NULL,
+    requested_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(), -- Used to promote the waitlist in order
+
+    PRIMARY KEY (contest_id, user_id)
+);
+
+CREATE INDEX idx_contest_registration_requests_status ON contest_registration_requests(contest_id, status, requested_at);
+
+-- +goose Down
+DROP INDEX idx_contest_registration_requests_status;
+DROP TABLE contest_registration_requests;
+DROP TRIGGER update_contest_registration_settings_updated_at ON contest_registration_settings;
+DROP TABLE contest_registration_settings;
+DROP TYPE registration_status;
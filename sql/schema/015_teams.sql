@@ -0,0 +1,80 @@
+-- +goose up
+-- Teams Table
+-- A group of users participating together under one name
+CREATE TABLE teams (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    captain_id UUID NOT NULL REFERENCES users(id), -- The member who manages the team
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_team_name UNIQUE (name)
+);
+
+-- A trigger to automatically update 'updated_at' on every row modification
+CREATE TRIGGER update_teams_updated_at BEFORE UPDATE ON teams FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Team Members Table
+-- The captain is also a member of the team
+CREATE TABLE team_members (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    joined_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX idx_team_members_user_id ON team_members(user_id);
+
+-- Team Invitations Table
+-- Users join a team only by accepting an invitation from its captain
+CREATE TABLE team_invitations (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id), -- The invited user
+    invited_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX idx_team_invitations_user_id ON team_invitations(user_id);
+
+-- Contest Team Settings Table
+-- A contest having these settings is a team contest, others are individual contests
+CREATE TABLE contest_team_settings (
+    contest_id UUID PRIMARY KEY REFERENCES contests(id) ON DELETE CASCADE,
+    max_team_size INTEGER NOT NULL, -- Maximum members of a team registered in the contest
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_contest_max_team_size CHECK (max_team_size > 0)
+);
+
+-- A trigger to automatically update 'updated_at' on every row modification
+CREATE TRIGGER update_contest_team_settings_updated_at BEFORE UPDATE ON contest_team_settings FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Contest Team Members Table
+-- Members of a team are captured when the team registers into a contest, so that
+-- later changes in the team do not affect the contest. A user can be a part of
+-- only one team in a contest.
+CREATE TABLE contest_team_members (
+    contest_id UUID NOT NULL REFERENCES contests(id) ON DELETE CASCADE,
+    team_id UUID NOT NULL REFERENCES teams(id),
+    user_id UUID NOT NULL REFERENCES users(id),
+
+    PRIMARY KEY (contest_id, user_id)
+);
+
+CREATE INDEX idx_contest_team_members_team_id ON contest_team_members(contest_id, team_id);
+
+-- +goose Down
+DROP INDEX idx_contest_team_members_team_id;
+DROP TABLE contest_team_members;
+DROP TRIGGER update_contest_team_settings_updated_at ON contest_team_settings;
+DROP TABLE contest_team_settings;
+DROP INDEX idx_team_invitations_user_id;
+DROP TABLE team_invitations;
+DROP INDEX idx_team_members_user_id;
+DROP TABLE team_members;
+DROP TRIGGER update_teams_updated_at ON teams;
+DROP TABLE teams;
@@ -0,0 +1,35 @@
+-- +goose up
+-- Contest Ratings Table
+-- Contests present in this table are rated. Rating changes are computed once
+-- a manager finalizes the contest.
+CREATE TABLE contest_ratings (
+    contest_id UUID PRIMARY KEY REFERENCES contests(id) ON DELETE CASCADE,
+    finalized_at TIMESTAMP WITH TIME ZONE DEFAULT NULL, -- Set once rating changes are computed
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- A trigger to automatically update 'updated_at' on every row modification
+CREATE TRIGGER update_contest_ratings_updated_at BEFORE UPDATE ON contest_ratings FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Rating Changes Table
+-- Rating history of the users. Current rating of a user is the new_rating of
+-- their latest rated contest.
+CREATE TABLE rating_changes (
+    contest_id UUID NOT NULL REFERENCES contest_ratings(contest_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    old_rating INTEGER NOT NULL,
+    new_rating INTEGER NOT NULL,
+    rank INTEGER NOT NULL, -- Rank of the user in the contest
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (contest_id, user_id)
+);
+
+CREATE INDEX idx_rating_changes_user_id ON rating_changes(user_id);
+
+-- +goose Down
+DROP INDEX idx_rating_changes_user_id;
+DROP TABLE rating_changes;
+DROP TRIGGER update_contest_ratings_updated_at ON contest_ratings;
+DROP TABLE contest_ratings;
@@ -0,0 +1,32 @@
+-- +goose up
+-- Problem Subtasks Table
+-- A subtask is a group of testcases worth some points. A subtask is awarded only
+-- if all its testcases pass and all the subtasks it depends on are awarded.
+CREATE TABLE problem_subtasks (
+    problem_id INTEGER NOT NULL REFERENCES problems(id) ON DELETE CASCADE,
+    subtask_id INTEGER NOT NULL, -- 1 based position of the subtask in the problem
+    points INTEGER NOT NULL,
+    testcases INTEGER[] NOT NULL, -- 1 based indices of the testcases in the group
+    depends_on INTEGER[] NOT NULL DEFAULT '{}', -- subtasks that must also be passed
+
+    PRIMARY KEY (problem_id, subtask_id)
+);
+
+-- Submission Subtask Results Table
+-- Per subtask verdicts reported by the evaluator
+CREATE TABLE submission_subtask_results (
+    submission_id UUID NOT NULL REFERENCES submissions(id) ON DELETE CASCADE,
+    subtask_id INTEGER NOT NULL,
+    passed BOOLEAN NOT NULL,
+
+    PRIMARY KEY (submission_id, subtask_id)
+);
+
+-- sum of the points of the awarded subtasks, null for problems without subtasks
+ALTER TABLE submissions
+    ADD COLUMN score INTEGER DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE submissions DROP COLUMN score;
+DROP TABLE submission_subtask_results;
+DROP TABLE problem_subtasks;
@@ -0,0 +1,34 @@
+-- +goose up
+CREATE TYPE lifecycle_event AS ENUM ('contest_start', 'contest_end', 'lock_timeout');
+CREATE TYPE lifecycle_job_status AS ENUM ('pending', 'running', 'done', 'failed');
+
+-- Contest Lifecycle Jobs Table
+-- Durable jobs run at the important moments of a contest. Jobs survive restarts
+-- and are rescheduled whenever the corresponding time of the contest changes.
+CREATE TABLE contest_lifecycle_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    contest_id UUID NOT NULL REFERENCES contests(id) ON DELETE CASCADE,
+    event lifecycle_event NOT NULL,
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status lifecycle_job_status NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    -- a contest has a single job for every event
+    CONSTRAINT uq_contest_lifecycle_job UNIQUE (contest_id, event)
+);
+
+-- index for polling the due jobs
+CREATE INDEX idx_contest_lifecycle_jobs_due ON contest_lifecycle_jobs(status, run_at);
+
+-- A trigger to automatically update 'updated_at' on every row modification
+CREATE TRIGGER update_contest_lifecycle_jobs_updated_at BEFORE UPDATE ON contest_lifecycle_jobs FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- +goose Down
+DROP TRIGGER update_contest_lifecycle_jobs_updated_at ON contest_lifecycle_jobs;
+DROP INDEX idx_contest_lifecycle_jobs_due;
+DROP TABLE contest_lifecycle_jobs;
+DROP TYPE lifecycle_job_status;
+DROP TYPE lifecycle_event;
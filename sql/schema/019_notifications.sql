@@ -0,0 +1,32 @@
+-- +goose up
+-- User Notification Settings Table
+-- Users without a row receive the contest notifications.
+CREATE TABLE user_notification_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    contest_emails BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- A trigger to automatically update 'updated_at' on every row modification
+CREATE TRIGGER update_user_notification_settings_updated_at BEFORE UPDATE ON user_notification_settings FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Contest Reminders Table
+-- Reminders are mailed to the participants offset_minutes before the start of the contest.
+CREATE TABLE contest_reminders (
+    contest_id UUID NOT NULL REFERENCES contests(id) ON DELETE CASCADE,
+    offset_minutes INTEGER NOT NULL CHECK (offset_minutes > 0),
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (contest_id, offset_minutes)
+);
+
+-- index for polling the unsent reminders
+CREATE INDEX idx_contest_reminders_unsent ON contest_reminders(contest_id) WHERE sent_at IS NULL;
+
+-- +goose Down
+DROP INDEX idx_contest_reminders_unsent;
+DROP TABLE contest_reminders;
+DROP TRIGGER update_user_notification_settings_updated_at ON user_notification_settings;
+DROP TABLE user_notification_settings;
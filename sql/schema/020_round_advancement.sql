@@ -0,0 +1,27 @@
+-- +goose up
+CREATE TYPE advancement_rule AS ENUM ('top_overall', 'top_per_contest', 'score_threshold', 'manual');
+
+-- Round Advancements Table
+-- Decides who qualifies from a round to the next one of the tournament.
+CREATE TABLE round_advancements (
+    round_id UUID PRIMARY KEY REFERENCES tournament_rounds(id) ON DELETE CASCADE,
+    rule advancement_rule NOT NULL,
+    -- used by the top_overall and top_per_contest rules
+    top_k INTEGER CHECK (top_k > 0),
+    -- used by the score_threshold rule
+    min_score INTEGER,
+    -- used by the manual rule
+    manual_user_ids UUID[] NOT NULL DEFAULT '{}',
+    -- set once the qualifiers are registered into the next round
+    advanced_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- A trigger to automatically update 'updated_at' on every row modification
+CREATE TRIGGER update_round_advancements_updated_at BEFORE UPDATE ON round_advancements FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- +goose Down
+DROP TRIGGER update_round_advancements_updated_at ON round_advancements;
+DROP TABLE round_advancements;
+DROP TYPE advancement_rule;
@@ -0,0 +1,20 @@
+-- +goose up
+-- Tournament Round Scoring Table
+-- How the results of a round count towards the tournament standings. Rounds
+-- without a row have a weight of 100 percent and count all their contests.
+CREATE TABLE tournament_round_scoring (
+    round_id UUID PRIMARY KEY REFERENCES tournament_rounds(id) ON DELETE CASCADE,
+    -- percentage of the contest scores added to the tournament points
+    weight INTEGER NOT NULL DEFAULT 100 CHECK (weight >= 0),
+    -- only the best contests of a participant in the round are counted
+    best_of INTEGER CHECK (best_of > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- A trigger to automatically update 'updated_at' on every row modification
+CREATE TRIGGER update_tournament_round_scoring_updated_at BEFORE UPDATE ON tournament_round_scoring FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- +goose Down
+DROP TRIGGER update_tournament_round_scoring_updated_at ON tournament_round_scoring;
+DROP TABLE tournament_round_scoring;
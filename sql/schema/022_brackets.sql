@@ -0,0 +1,70 @@
+-- +goose up
+CREATE TYPE bracket_seeding AS ENUM ('rating', 'manual');
+CREATE TYPE bracket_match_status AS ENUM ('pending', 'scheduled', 'finished');
+
+-- Tournament Brackets Table
+-- A tournament played as a knockout bracket of 1v1 matches instead of rounds.
+CREATE TABLE tournament_brackets (
+    tournament_id UUID PRIMARY KEY REFERENCES tournaments(id) ON DELETE CASCADE,
+    seeding bracket_seeding NOT NULL,
+    -- problems the contests of the matches are picked from
+    problem_pool INTEGER[] NOT NULL,
+    problems_per_match INTEGER NOT NULL CHECK (problems_per_match > 0),
+    match_duration_minutes INTEGER NOT NULL CHECK (match_duration_minutes > 0),
+    -- number of slots in the first round, a power of two
+    size INTEGER NOT NULL CHECK (size >= 2),
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- A trigger to automatically update 'updated_at' on every row modification
+CREATE TRIGGER update_tournament_brackets_updated_at BEFORE UPDATE ON tournament_brackets FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Bracket Participants Table
+CREATE TABLE bracket_participants (
+    tournament_id UUID NOT NULL REFERENCES tournament_brackets(tournament_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- 1 is the strongest seed
+    seed INTEGER NOT NULL CHECK (seed > 0),
+
+    PRIMARY KEY (tournament_id, user_id),
+    CONSTRAINT uq_bracket_seed UNIQUE (tournament_id, seed)
+);
+
+-- Bracket Matches Table
+-- A match with a single player is a bye and is finished on creation.
+CREATE TABLE bracket_matches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tournament_id UUID NOT NULL REFERENCES tournament_brackets(tournament_id) ON DELETE CASCADE,
+    round_number INTEGER NOT NULL CHECK (round_number > 0),
+    -- position of the match in its round, starting from 1
+    match_number INTEGER NOT NULL CHECK (match_number > 0),
+    player1_id UUID REFERENCES users(id),
+    player2_id UUID REFERENCES users(id),
+    contest_id UUID REFERENCES contests(id) ON DELETE SET NULL,
+    player1_score INTEGER,
+    player2_score INTEGER,
+    winner_id UUID REFERENCES users(id),
+    status bracket_match_status NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_bracket_match UNIQUE (tournament_id, round_number, match_number)
+);
+
+-- index for finding the match of an ended contest
+CREATE INDEX idx_bracket_matches_contest_id ON bracket_matches(contest_id);
+
+-- A trigger to automatically update 'updated_at' on every row modification
+CREATE TRIGGER update_bracket_matches_updated_at BEFORE UPDATE ON bracket_matches FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- +goose Down
+DROP TRIGGER update_bracket_matches_updated_at ON bracket_matches;
+DROP INDEX idx_bracket_matches_contest_id;
+DROP TABLE bracket_matches;
+DROP TABLE bracket_participants;
+DROP TRIGGER update_tournament_brackets_updated_at ON tournament_brackets;
+DROP TABLE tournament_brackets;
+DROP TYPE bracket_match_status;
+DROP TYPE bracket_seeding;
@@ -0,0 +1,15 @@
+-- +goose up
+-- Unpublished rounds are visible only to the managers. Existing rounds stay visible.
+ALTER TABLE tournament_rounds ADD COLUMN is_published BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Rounds are renumbered with a single statement on reordering and deletion, so
+-- the uniqueness of the round numbers is checked at the end of the statement.
+ALTER TABLE tournament_rounds DROP CONSTRAINT tournament_rounds_tournament_id_round_number_key;
+ALTER TABLE tournament_rounds ADD CONSTRAINT uq_tournament_round_number
+    UNIQUE (tournament_id, round_number) DEFERRABLE INITIALLY IMMEDIATE;
+
+-- +goose Down
+ALTER TABLE tournament_rounds DROP CONSTRAINT uq_tournament_round_number;
+ALTER TABLE tournament_rounds ADD CONSTRAINT tournament_rounds_tournament_id_round_number_key
+    UNIQUE (tournament_id, round_number);
+ALTER TABLE tournament_rounds DROP COLUMN is_published;
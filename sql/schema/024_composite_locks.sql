@@ -0,0 +1,56 @@
+-- +goose NO TRANSACTION
+-- +goose up
+-- a new enum value cannot be used in the transaction that adds it
+ALTER TYPE lock_type ADD VALUE IF NOT EXISTS 'composite';
+
+-- manual locks open for everyone once they are released
+ALTER TABLE locks ADD COLUMN released_at TIMESTAMP WITH TIME ZONE;
+
+-- composite locks combine their conditions with either 'and' or 'or'
+ALTER TABLE locks ADD COLUMN operator VARCHAR(3) CHECK (operator IN ('and', 'or'));
+
+ALTER TABLE locks DROP CONSTRAINT locks_check;
+ALTER TABLE locks ADD CONSTRAINT locks_check CHECK (
+    (lock_type = 'manual' AND timeout IS NULL AND operator IS NULL)
+    OR
+    (lock_type = 'timer' AND timeout IS NOT NULL AND operator IS NULL AND released_at IS NULL)
+    OR
+    (lock_type = 'composite' AND timeout IS NULL AND operator IS NOT NULL AND released_at IS NULL)
+);
+
+-- Lock Children Table
+-- Locks combined by a composite lock. A lock in use cannot be deleted.
+CREATE TABLE lock_children (
+    parent_id UUID NOT NULL REFERENCES locks(id) ON DELETE CASCADE,
+    child_id UUID NOT NULL,
+    CONSTRAINT fk_lock_children_child FOREIGN KEY (child_id) REFERENCES locks(id) ON DELETE RESTRICT,
+    PRIMARY KEY (parent_id, child_id),
+    CHECK (parent_id <> child_id)
+);
+
+CREATE INDEX idx_lock_children_child_id ON lock_children(child_id);
+
+-- Lock Contest Dependencies Table
+-- Contests that must end for the condition of a composite lock to hold
+CREATE TABLE lock_contest_dependencies (
+    lock_id UUID NOT NULL REFERENCES locks(id) ON DELETE CASCADE,
+    contest_id UUID NOT NULL,
+    CONSTRAINT fk_lock_dependencies_contest FOREIGN KEY (contest_id) REFERENCES contests(id) ON DELETE RESTRICT,
+    PRIMARY KEY (lock_id, contest_id)
+);
+
+CREATE INDEX idx_lock_contest_dependencies_contest_id ON lock_contest_dependencies(contest_id);
+
+-- +goose Down
+DROP TABLE lock_contest_dependencies;
+DROP TABLE lock_children;
+DELETE FROM locks WHERE lock_type = 'composite';
+ALTER TABLE locks DROP CONSTRAINT locks_check;
+ALTER TABLE locks ADD CONSTRAINT locks_check CHECK (
+    (lock_type = 'manual' AND timeout IS NULL)
+    OR
+    (lock_type = 'timer' AND timeout IS NOT NULL)
+);
+ALTER TABLE locks DROP COLUMN operator;
+ALTER TABLE locks DROP COLUMN released_at;
+-- enum values cannot be dropped, 'composite' stays unused in lock_type
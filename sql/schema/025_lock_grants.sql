@@ -0,0 +1,34 @@
+-- +goose up
+CREATE TYPE lock_grant_permission AS ENUM ('view', 'edit');
+
+-- Lock Grants Table
+-- Access to a closed lock given by its creator to a user or to all the members
+-- of a team, without giving them the access role of the lock. Edit implies view.
+CREATE TABLE lock_grants (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    lock_id UUID NOT NULL REFERENCES locks(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    team_id UUID REFERENCES teams(id) ON DELETE CASCADE,
+    permission lock_grant_permission NOT NULL,
+    granted_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    -- a grant is either for a user or for a team
+    CONSTRAINT chk_lock_grant_grantee CHECK ((user_id IS NULL) <> (team_id IS NULL)),
+    CONSTRAINT uq_lock_grant_user UNIQUE (lock_id, user_id),
+    CONSTRAINT uq_lock_grant_team UNIQUE (lock_id, team_id)
+);
+
+-- A trigger to automatically update 'updated_at' on every row modification
+CREATE TRIGGER update_lock_grants_updated_at BEFORE UPDATE ON lock_grants FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE INDEX idx_lock_grants_user_id ON lock_grants(user_id);
+CREATE INDEX idx_lock_grants_team_id ON lock_grants(team_id);
+
+-- +goose Down
+DROP INDEX idx_lock_grants_team_id;
+DROP INDEX idx_lock_grants_user_id;
+DROP TRIGGER update_lock_grants_updated_at ON lock_grants;
+DROP TABLE lock_grants;
+DROP TYPE lock_grant_permission;
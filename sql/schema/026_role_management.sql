@@ -0,0 +1,33 @@
+-- +goose up
+ALTER TABLE roles ADD COLUMN description TEXT NOT NULL DEFAULT '';
+ALTER TABLE roles ADD COLUMN created_by UUID REFERENCES users(id) ON DELETE SET NULL; -- null for the roles seeded with sql
+ALTER TABLE roles ADD COLUMN created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+ALTER TABLE user_roles ADD COLUMN granted_by UUID REFERENCES users(id) ON DELETE SET NULL; -- null for the roles granted with sql
+ALTER TABLE user_roles ADD COLUMN granted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+CREATE TYPE role_audit_action AS ENUM ('create_role', 'grant', 'revoke');
+
+-- Role Audit Logs Table
+-- Every change made to the roles through the api. Rows are kept even if the
+-- role is deleted later, so the role is not referenced.
+CREATE TABLE role_audit_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    action role_audit_action NOT NULL,
+    role_name VARCHAR(50) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL, -- the user whose role changed, null for create_role
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL, -- the manager who made the change
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_role_audit_logs_role_name ON role_audit_logs(role_name, created_at);
+
+-- +goose Down
+DROP INDEX idx_role_audit_logs_role_name;
+DROP TABLE role_audit_logs;
+DROP TYPE role_audit_action;
+ALTER TABLE user_roles DROP COLUMN granted_at;
+ALTER TABLE user_roles DROP COLUMN granted_by;
+ALTER TABLE roles DROP COLUMN created_at;
+ALTER TABLE roles DROP COLUMN created_by;
+ALTER TABLE roles DROP COLUMN description;
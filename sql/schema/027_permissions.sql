@@ -0,0 +1,57 @@
+-- +goose NO TRANSACTION
+-- +goose up
+-- a new enum value cannot be used in the transaction that adds it
+ALTER TYPE role_audit_action ADD VALUE IF NOT EXISTS 'set_permissions';
+
+-- Permissions Table
+-- Named actions checked by the services, e.g. contest.create. Permissions
+-- ending with .any let the user act on the resources created by others.
+CREATE TABLE permissions (
+    name VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL
+);
+
+-- Role Permissions Table
+-- A user holds every permission of each of their roles
+CREATE TABLE role_permissions (
+    role_name VARCHAR(50) NOT NULL REFERENCES roles(role_name) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL,
+    CONSTRAINT fk_role_permissions_permission FOREIGN KEY (permission) REFERENCES permissions(name) ON DELETE CASCADE,
+    PRIMARY KEY (role_name, permission)
+);
+
+CREATE INDEX idx_role_permissions_permission ON role_permissions(permission);
+
+INSERT INTO permissions (name, description) VALUES
+    ('problem.create', 'Add problems'),
+    ('problem.edit.any', 'Edit problems created by others'),
+    ('contest.create', 'Create public contests'),
+    ('contest.manage', 'Manage public contests, their registrations, notifications and standings'),
+    ('contest.edit.any', 'Manage private contests created by others'),
+    ('lock.create', 'Create locks'),
+    ('lock.search', 'Search all the locks'),
+    ('lock.edit.any', 'Edit, release and delete locks created by others'),
+    ('tournament.manage', 'Create and manage tournaments'),
+    ('rating.manage', 'Change the rated status and compute the ratings of contests'),
+    ('bot.manage', 'Add, update and delete bots'),
+    ('lifecycle.view', 'View the lifecycle jobs of contests'),
+    ('role.manage', 'Create roles and change the roles of users');
+
+-- the built in roles keep the access they had with the role checks
+INSERT INTO roles (role_name, description) VALUES
+    ('role_manager', 'Manages the public contests, problems and tournaments'),
+    ('role_hc', 'Head coordinator, can act on everything')
+ON CONFLICT (role_name) DO NOTHING;
+
+INSERT INTO role_permissions (role_name, permission)
+SELECT 'role_manager', name FROM permissions WHERE name NOT LIKE '%.any';
+
+INSERT INTO role_permissions (role_name, permission)
+SELECT 'role_hc', name FROM permissions;
+
+-- +goose Down
+DROP INDEX idx_role_permissions_permission;
+DROP TABLE role_permissions;
+DROP TABLE permissions;
+DELETE FROM role_audit_logs WHERE action = 'set_permissions';
+-- enum values cannot be dropped, 'set_permissions' stays unused in role_audit_action
@@ -0,0 +1,53 @@
+-- +goose up
+-- Audit Logs Table
+-- Every change made to contests, problems, locks, tournaments, bots and roles
+-- through the api. Rows are never updated or deleted, so the actor is not a
+-- reference to users and their user name is copied.
+CREATE TABLE audit_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id UUID, -- null for the changes made by the server itself
+    actor_user_name VARCHAR(255) NOT NULL,
+    action VARCHAR(50) NOT NULL, -- e.g. contest.update
+    entity_type VARCHAR(30) NOT NULL,
+    entity_id TEXT NOT NULL,
+    before JSONB, -- null for creations
+    after JSONB, -- null for deletions
+    request_id VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_logs_entity ON audit_logs(entity_type, entity_id, created_at);
+CREATE INDEX idx_audit_logs_actor_id ON audit_logs(actor_id, created_at);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs(created_at);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION prevent_audit_log_change()
+RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit logs are append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER prevent_audit_logs_change
+BEFORE UPDATE OR DELETE ON audit_logs
+FOR EACH ROW
+EXECUTE PROCEDURE prevent_audit_log_change();
+-- +goose StatementEnd
+
+INSERT INTO permissions (name, description) VALUES
+    ('audit.view', 'Search the audit log');
+
+INSERT INTO role_permissions (role_name, permission) VALUES
+    ('role_manager', 'audit.view'),
+    ('role_hc', 'audit.view');
+
+-- +goose Down
+DELETE FROM permissions WHERE name = 'audit.view';
+DROP TRIGGER prevent_audit_logs_change ON audit_logs;
+DROP FUNCTION prevent_audit_log_change();
+DROP INDEX idx_audit_logs_created_at;
+DROP INDEX idx_audit_logs_actor_id;
+DROP INDEX idx_audit_logs_entity;
+DROP TABLE audit_logs;
@@ -0,0 +1,27 @@
+-- +goose up
+-- User Sessions Table
+-- One row for every login. The id is the jti claim of the issued jwt, the
+-- middleware rejects tokens whose session is revoked or missing.
+CREATE TABLE user_sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE -- null while the session is active
+);
+
+CREATE INDEX idx_user_sessions_user_id ON user_sessions(user_id);
+
+INSERT INTO permissions (name, description) VALUES
+    ('session.manage', 'Revoke the sessions of any user');
+
+INSERT INTO role_permissions (role_name, permission) VALUES
+    ('role_manager', 'session.manage'),
+    ('role_hc', 'session.manage');
+
+-- +goose Down
+DELETE FROM permissions WHERE name = 'session.manage';
+DROP INDEX idx_user_sessions_user_id;
+DROP TABLE user_sessions;
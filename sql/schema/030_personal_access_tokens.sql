@@ -0,0 +1,21 @@
+-- +goose up
+-- Personal Access Tokens Table
+-- Tokens used by scripts in the Authorization header instead of the session
+-- cookie. Only the sha256 of the token is stored, the token is shown once.
+CREATE TABLE personal_access_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    hashed_token VARCHAR(255) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{}', -- permissions the token may use
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE -- null while the token is active
+);
+
+CREATE INDEX idx_personal_access_tokens_user_id ON personal_access_tokens(user_id);
+
+-- +goose Down
+DROP INDEX idx_personal_access_tokens_user_id;
+DROP TABLE personal_access_tokens;
@@ -0,0 +1,31 @@
+-- +goose up
+-- OIDC Login States Table
+-- A row for every started openid connect login, consumed by the callback.
+-- The pkce verifier and the nonce never leave the server.
+CREATE TABLE oidc_login_states (
+    state VARCHAR(64) PRIMARY KEY,
+    code_verifier VARCHAR(128) NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    remember_for_month BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- User Identities Table
+-- Accounts of the identity providers linked to the users. A user is linked
+-- on the first login by the verified email.
+CREATE TABLE user_identities (
+    issuer TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (issuer, subject)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+
+-- +goose Down
+DROP INDEX idx_user_identities_user_id;
+DROP TABLE user_identities;
+DROP TABLE oidc_login_states;
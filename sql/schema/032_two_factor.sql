@@ -0,0 +1,27 @@
+-- +goose up
+-- User TOTP Table
+-- The totp secret of a user. The secret is pending until the user confirms
+-- it with a code, only then the login asks for the second step.
+CREATE TABLE user_totp (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL, -- base32, as shown to authenticator apps
+    enabled_at TIMESTAMP WITH TIME ZONE, -- null while pending
+    last_used_step BIGINT NOT NULL DEFAULT 0, -- codes cannot be used twice
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- User Recovery Codes Table
+-- Single use codes replacing the totp code when the device is lost. Only the
+-- sha256 of the code is stored, the codes are shown once.
+CREATE TABLE user_recovery_codes (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    hashed_code VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, hashed_code)
+);
+
+-- +goose Down
+DROP TABLE user_recovery_codes;
+DROP TABLE user_totp;
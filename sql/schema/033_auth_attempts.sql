@@ -0,0 +1,21 @@
+-- +goose up
+-- Auth Attempts Table
+-- Recent attempts of the throttled auth actions, like failed logins, counted
+-- per subject, e.g. user:<id> or ip:<address>. The subject is blocked until
+-- blocked_until, growing with every attempt. Attempts are forgotten after a
+-- quiet period.
+CREATE TABLE auth_attempts (
+    action VARCHAR(50) NOT NULL,
+    subject VARCHAR(320) NOT NULL,
+    attempts INT NOT NULL DEFAULT 1,
+    last_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    blocked_until TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (action, subject)
+);
+
+CREATE INDEX idx_auth_attempts_last_attempt_at ON auth_attempts(last_attempt_at);
+
+-- +goose Down
+DROP INDEX idx_auth_attempts_last_attempt_at;
+DROP TABLE auth_attempts;
@@ -0,0 +1,11 @@
+-- +goose up
+-- the local judge reports the subtask verdicts with an access token scoped to
+-- this permission
+INSERT INTO permissions (name, description) VALUES
+    ('submission.judge', 'Report the subtask verdicts of submissions');
+
+INSERT INTO role_permissions (role_name, permission) VALUES
+    ('role_hc', 'submission.judge');
+
+-- +goose Down
+DELETE FROM permissions WHERE name = 'submission.judge';
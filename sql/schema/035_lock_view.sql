@@ -0,0 +1,12 @@
+-- +goose up
+-- locks were shown to the users holding their access role, the built in
+-- roles keep seeing them
+INSERT INTO permissions (name, description) VALUES
+    ('lock.view', 'View the locks created by others');
+
+INSERT INTO role_permissions (role_name, permission) VALUES
+    ('role_manager', 'lock.view'),
+    ('role_hc', 'lock.view');
+
+-- +goose Down
+DELETE FROM permissions WHERE name = 'lock.view';
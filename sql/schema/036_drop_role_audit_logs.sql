@@ -0,0 +1,49 @@
+-- +goose up
+-- role changes are only kept in audit_logs, the ones made before it are
+-- copied over. Rows written to both tables share their created_at.
+INSERT INTO audit_logs (
+    actor_id,
+    actor_user_name,
+    action,
+    entity_type,
+    entity_id,
+    after,
+    created_at
+)
+SELECT
+    l.actor_id,
+    COALESCE(a.user_name, ''),
+    'role.' || l.action::text,
+    'role',
+    l.role_name,
+    jsonb_strip_nulls(jsonb_build_object('role_name', l.role_name, 'user_name', u.user_name)),
+    l.created_at
+FROM role_audit_logs l
+LEFT JOIN users u ON l.user_id = u.id
+LEFT JOIN users a ON l.actor_id = a.id
+WHERE NOT EXISTS (
+    SELECT 1 FROM audit_logs al
+    WHERE al.entity_type = 'role'
+        AND al.entity_id = l.role_name
+        AND al.action = 'role.' || l.action::text
+        AND al.created_at = l.created_at
+);
+
+DROP INDEX idx_role_audit_logs_role_name;
+DROP TABLE role_audit_logs;
+DROP TYPE role_audit_action;
+
+-- +goose Down
+-- the copied rows stay in audit_logs
+CREATE TYPE role_audit_action AS ENUM ('create_role', 'grant', 'revoke', 'set_permissions');
+
+CREATE TABLE role_audit_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    action role_audit_action NOT NULL,
+    role_name VARCHAR(50) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_role_audit_logs_role_name ON role_audit_logs(role_name, created_at);
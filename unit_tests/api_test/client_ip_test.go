@@ -0,0 +1,79 @@
+package apitest_test
+
+import (
+	"testing"
+
+	"github.com/tcp_snm/flux/internal/api"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted, err := api.ParseTrustedProxies("10.0.0.0/8, 192.168.1.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		expected     string
+	}{
+		{
+			name:       "direct client",
+			remoteAddr: "203.0.113.7:4312",
+			expected:   "203.0.113.7",
+		},
+		{
+			name:         "untrusted peer cannot forward",
+			remoteAddr:   "203.0.113.7:4312",
+			forwardedFor: []string{"198.51.100.1"},
+			expected:     "203.0.113.7",
+		},
+		{
+			name:         "client behind the proxy",
+			remoteAddr:   "10.0.0.2:4312",
+			forwardedFor: []string{"198.51.100.1"},
+			expected:     "198.51.100.1",
+		},
+		{
+			// the client may send any address, only the hop added by the
+			// proxy is taken
+			name:         "spoofed hops are skipped",
+			remoteAddr:   "10.0.0.2:4312",
+			forwardedFor: []string{"1.1.1.1, 198.51.100.1"},
+			expected:     "198.51.100.1",
+		},
+		{
+			name:         "chained proxies",
+			remoteAddr:   "10.0.0.2:4312",
+			forwardedFor: []string{"1.1.1.1, 198.51.100.1", "192.168.1.1"},
+			expected:     "198.51.100.1",
+		},
+		{
+			name:         "malformed hop",
+			remoteAddr:   "10.0.0.2:4312",
+			forwardedFor: []string{"198.51.100.1, unknown"},
+			expected:     "10.0.0.2",
+		},
+		{
+			name:       "trusted peer without the header",
+			remoteAddr: "10.0.0.2:4312",
+			expected:   "10.0.0.2",
+		},
+	} {
+		got := api.ClientIP(tc.remoteAddr, tc.forwardedFor, trusted)
+		if got != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestParseTrustedProxiesRejectsGarbage(t *testing.T) {
+	if _, err := api.ParseTrustedProxies("10.0.0.0/8,proxy"); err == nil {
+		t.Error("expected an invalid proxy to be rejected")
+	}
+	proxies, err := api.ParseTrustedProxies("")
+	if err != nil || len(proxies) != 0 {
+		t.Errorf("expected no proxies, got %v (%v)", proxies, err)
+	}
+}
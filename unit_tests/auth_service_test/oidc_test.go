@@ -0,0 +1,311 @@
+package auth_servicetest_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service/auth_service"
+)
+
+const (
+	clientID     = "flux-client"
+	clientSecret = "flux-secret"
+	redirectURL  = "https://flux.example.com/v1/auth/oidc/callback"
+	keyID        = "key-1"
+)
+
+// identityProvider is a stand-in for a real provider. It hands out a code for
+// every authorization request and signs id tokens with its own key.
+type identityProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu     sync.Mutex
+	codes  map[string]authRequest
+	claims jwt.MapClaims // claims of the next id token, on top of the defaults
+	signer *rsa.PrivateKey
+}
+
+type authRequest struct {
+	challenge string
+	nonce     string
+}
+
+func newIdentityProvider(t *testing.T) *identityProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &identityProvider{key: key, codes: map[string]authRequest{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kid": keyID,
+				"kty": "RSA",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", idp.handleToken)
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+// authorize does what the provider does when the user logs in
+func (idp *identityProvider) authorize(t *testing.T, authURL string) string {
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := parsed.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("client_id") != clientID ||
+		query.Get("redirect_uri") != redirectURL || query.Get("response_type") != "code" {
+		t.Fatalf("unexpected authorization request %v", query)
+	}
+
+	code := "code-" + query.Get("state")
+	idp.mu.Lock()
+	idp.codes[code] = authRequest{challenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	idp.mu.Unlock()
+	return code
+}
+
+func (idp *identityProvider) handleToken(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+	if !ok || id != clientID || secret != clientSecret {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	idp.mu.Lock()
+	request, ok := idp.codes[r.FormValue("code")]
+	delete(idp.codes, r.FormValue("code"))
+	extraClaims, signer := idp.claims, idp.signer
+	idp.mu.Unlock()
+
+	// the verifier must match the challenge of the authorization request
+	if !ok || auth_service.PKCEChallenge(r.FormValue("code_verifier")) != request.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":            idp.server.URL,
+		"aud":            clientID,
+		"sub":            "subject-1",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          request.nonce,
+		"email":          "21bcs001@college.edu",
+		"email_verified": true,
+		"given_name":     "Alice",
+		"family_name":    "Smith",
+	}
+	for name, value := range extraClaims {
+		claims[name] = value
+	}
+	if signer == nil {
+		signer = idp.key
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
+	idToken, err := token.SignedString(signer)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"id_token": idToken, "token_type": "Bearer"})
+}
+
+func newProvider(idp *identityProvider, allowedDomain string) *auth_service.OIDCProvider {
+	return auth_service.NewOIDCProvider(auth_service.OIDCConfig{
+		Issuer:        idp.server.URL,
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+		RedirectURL:   redirectURL,
+		AllowedDomain: allowedDomain,
+	})
+}
+
+// login runs the whole flow and returns the identity, or the error of the
+// step that failed
+func login(t *testing.T, idp *identityProvider, provider *auth_service.OIDCProvider) (auth_service.OIDCIdentity, error) {
+	ctx := context.Background()
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := idp.authorize(t, authURL)
+
+	idToken, err := provider.Exchange(ctx, code, "verifier-1")
+	if err != nil {
+		return auth_service.OIDCIdentity{}, err
+	}
+	return provider.VerifyIDToken(ctx, idToken, "nonce-1")
+}
+
+func TestOIDCLogin(t *testing.T) {
+	idp := newIdentityProvider(t)
+	provider := newProvider(idp, "college.edu")
+
+	identity, err := login(t, idp, provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := auth_service.OIDCIdentity{
+		Issuer:     idp.server.URL,
+		Subject:    "subject-1",
+		Email:      "21bcs001@college.edu",
+		GivenName:  "Alice",
+		FamilyName: "Smith",
+	}
+	if identity != want {
+		t.Errorf("expected %+v, got %+v", want, identity)
+	}
+}
+
+func TestOIDCExchangeWrongVerifier(t *testing.T) {
+	idp := newIdentityProvider(t)
+	provider := newProvider(idp, "")
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := idp.authorize(t, authURL)
+
+	// a stolen code is useless without the verifier
+	_, err = provider.Exchange(ctx, code, "verifier-2")
+	if !errors.Is(err, flux_errors.ErrInvalidRequest) {
+		t.Errorf("expected invalid request, got %v", err)
+	}
+}
+
+func TestOIDCVerifyIDTokenWrongNonce(t *testing.T) {
+	idp := newIdentityProvider(t)
+	provider := newProvider(idp, "")
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	idToken, err := provider.Exchange(ctx, idp.authorize(t, authURL), "verifier-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// replayed id token of another login
+	_, err = provider.VerifyIDToken(ctx, idToken, "nonce-2")
+	if !errors.Is(err, flux_errors.ErrInvalidRequestCredentials) {
+		t.Errorf("expected invalid credentials, got %v", err)
+	}
+}
+
+func TestOIDCRejectedIDTokens(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name          string
+		claims        jwt.MapClaims
+		signer        *rsa.PrivateKey
+		allowedDomain string
+	}{
+		{name: "other audience", claims: jwt.MapClaims{"aud": "other-client"}},
+		{name: "other issuer", claims: jwt.MapClaims{"iss": "https://evil.example.com"}},
+		{name: "expired", claims: jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}},
+		{name: "unverified email", claims: jwt.MapClaims{"email_verified": false}},
+		{name: "no email", claims: jwt.MapClaims{"email": ""}},
+		{name: "forged signature", signer: otherKey},
+		{name: "other domain", claims: jwt.MapClaims{"email": "alice@gmail.com"}, allowedDomain: "college.edu"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			idp := newIdentityProvider(t)
+			idp.claims = tc.claims
+			idp.signer = tc.signer
+
+			_, err := login(t, idp, newProvider(idp, tc.allowedDomain))
+			if !errors.Is(err, flux_errors.ErrInvalidRequestCredentials) {
+				t.Errorf("expected invalid credentials, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOIDCDiscoveryIssuerMismatch(t *testing.T) {
+	idp := newIdentityProvider(t)
+	provider := auth_service.NewOIDCProvider(auth_service.OIDCConfig{
+		Issuer:   idp.server.URL + "/",
+		ClientID: clientID,
+	})
+
+	// the provider must be the issuer it was configured with
+	_, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", "verifier-1")
+	if !errors.Is(err, flux_errors.ErrInternal) {
+		t.Errorf("expected internal error, got %v", err)
+	}
+}
+
+func TestPKCEChallenge(t *testing.T) {
+	// example of RFC 7636 appendix B
+	challenge := auth_service.PKCEChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if challenge != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Errorf("unexpected challenge %s", challenge)
+	}
+}
+
+func TestOIDCRollNo(t *testing.T) {
+	rollNo, ok := auth_service.OIDCRollNo("B220123CS@College.Example.com", "college.example.com")
+	if !ok || rollNo != "b220123cs" {
+		t.Errorf("expected roll number b220123cs, got %q, %v", rollNo, ok)
+	}
+
+	for _, email := range []string{
+		"b220123cs@gmail.com",
+		"b220123cs@college.example.com.evil.com",
+		"@college.example.com",
+		"b220123cs",
+	} {
+		if rollNo, ok := auth_service.OIDCRollNo(email, "college.example.com"); ok {
+			t.Errorf("expected no roll number for %s, got %q", email, rollNo)
+		}
+	}
+
+	if rollNo, ok := auth_service.OIDCRollNo("b220123cs@college.example.com", ""); ok {
+		t.Errorf("expected no roll number without a college domain, got %q", rollNo)
+	}
+}
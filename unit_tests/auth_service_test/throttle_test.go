@@ -0,0 +1,62 @@
+package auth_servicetest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tcp_snm/flux/internal/service/auth_service"
+)
+
+func TestThrottlePolicyBlockFor(t *testing.T) {
+	policy := auth_service.ThrottlePolicy{
+		FreeAttempts: 3,
+		BaseDelay:    2 * time.Second,
+		MaxDelay:     10 * time.Second,
+		LockoutAfter: 8,
+		LockoutFor:   time.Hour,
+	}
+
+	for _, tc := range []struct {
+		attempts int
+		blockFor time.Duration
+		lockout  bool
+	}{
+		{attempts: 1},
+		{attempts: 3},
+		// backoff doubles after the free attempts
+		{attempts: 4, blockFor: 2 * time.Second},
+		{attempts: 5, blockFor: 4 * time.Second},
+		{attempts: 6, blockFor: 8 * time.Second},
+		// capped
+		{attempts: 7, blockFor: 10 * time.Second},
+		// locked out from then on
+		{attempts: 8, blockFor: time.Hour, lockout: true},
+		{attempts: 20, blockFor: time.Hour, lockout: true},
+	} {
+		blockFor, lockout := policy.BlockFor(tc.attempts)
+		if blockFor != tc.blockFor || lockout != tc.lockout {
+			t.Errorf(
+				"after %d attempts expected %v (lockout %v), got %v (lockout %v)",
+				tc.attempts,
+				tc.blockFor,
+				tc.lockout,
+				blockFor,
+				lockout,
+			)
+		}
+	}
+}
+
+func TestThrottlePolicyWithoutLockout(t *testing.T) {
+	policy := auth_service.ThrottlePolicy{
+		FreeAttempts: 1,
+		BaseDelay:    time.Second,
+		MaxDelay:     time.Minute,
+	}
+
+	// the delay must not overflow after many attempts
+	blockFor, lockout := policy.BlockFor(1000)
+	if blockFor != time.Minute || lockout {
+		t.Errorf("expected the max delay without lockout, got %v (lockout %v)", blockFor, lockout)
+	}
+}
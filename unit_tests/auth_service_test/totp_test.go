@@ -0,0 +1,97 @@
+package auth_servicetest_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/tcp_snm/flux/internal/service/auth_service"
+)
+
+// base32 of the sha1 seed of RFC 6238 appendix B
+const totpSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	// the test vectors of RFC 6238, with 6 digits
+	for unix, want := range map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	} {
+		code, err := auth_service.TOTPCode(totpSecret, auth_service.TOTPStep(time.Unix(unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != want {
+			t.Errorf("at %d expected %s, got %s", unix, want, code)
+		}
+	}
+
+	// apps show the secret in lower case too
+	code, err := auth_service.TOTPCode("gezdgnbvgy3tqojqgezdgnbvgy3tqojq", 1)
+	if err != nil || code != "287082" {
+		t.Errorf("lower case secret gave %s, %v", code, err)
+	}
+
+	_, err = auth_service.TOTPCode("not base32!", 1)
+	if err == nil {
+		t.Error("invalid secret should fail")
+	}
+}
+
+func TestMatchTOTPStep(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := auth_service.TOTPStep(now)
+	code := func(step int64) string {
+		code, err := auth_service.TOTPCode(totpSecret, step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	if got := auth_service.MatchTOTPStep(totpSecret, code(step), now, 0); got != step {
+		t.Errorf("current code should match step %d, got %d", step, got)
+	}
+
+	// one step of clock drift either way
+	if got := auth_service.MatchTOTPStep(totpSecret, code(step-1), now, 0); got != step-1 {
+		t.Errorf("previous code should match step %d, got %d", step-1, got)
+	}
+	if got := auth_service.MatchTOTPStep(totpSecret, code(step+1), now, 0); got != step+1 {
+		t.Errorf("next code should match step %d, got %d", step+1, got)
+	}
+	if got := auth_service.MatchTOTPStep(totpSecret, code(step-2), now, 0); got != 0 {
+		t.Errorf("old code should not match, got %d", got)
+	}
+
+	// used codes cannot be replayed
+	if got := auth_service.MatchTOTPStep(totpSecret, code(step), now, step); got != 0 {
+		t.Errorf("used code should not match, got %d", got)
+	}
+	if got := auth_service.MatchTOTPStep(totpSecret, code(step-1), now, step); got != 0 {
+		t.Errorf("code older than the used one should not match, got %d", got)
+	}
+
+	if got := auth_service.MatchTOTPStep(totpSecret, "", now, 0); got != 0 {
+		t.Errorf("empty code should not match, got %d", got)
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri, err := url.Parse(auth_service.TOTPProvisioningURI(totpSecret, "alice123"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Flux:alice123" {
+		t.Errorf("unexpected uri %s", uri)
+	}
+	query := uri.Query()
+	if query.Get("secret") != totpSecret || query.Get("issuer") != "Flux" ||
+		query.Get("digits") != "6" || query.Get("period") != "30" {
+		t.Errorf("unexpected parameters %v", query)
+	}
+}
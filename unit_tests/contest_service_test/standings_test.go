@@ -0,0 +1,200 @@
+package contest_servicetest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+	"github.com/tcp_snm/flux/internal/service/problem_service"
+)
+
+var (
+	alice = uuid.New()
+	bob   = uuid.New()
+	start = time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+)
+
+// problem 1 has subtasks worth 100 points in all, problem 2 is judged as a whole
+var (
+	contest = contest_service.Contest{
+		ID:        uuid.New(),
+		StartTime: &start,
+		EndTime:   start.Add(2 * time.Hour),
+	}
+	problems = []database.ContestProblem{
+		{ContestID: contest.ID, ProblemID: 1, Score: 500},
+		{ContestID: contest.ID, ProblemID: 2, Score: 300},
+	}
+	subtasks = []problem_service.Subtask{
+		{SubtaskID: 1, Points: 20, Testcases: []int32{1}},
+		{SubtaskID: 2, Points: 30, Testcases: []int32{2}, DependsOn: []int32{1}},
+		{SubtaskID: 3, Points: 50, Testcases: []int32{3}, DependsOn: []int32{2}},
+	}
+	subtaskPoints = map[int32]int32{1: 100}
+)
+
+// submission as returned by the db after the judge has reported its subtasks
+func judged(userID uuid.UUID, minutes int, passed map[int32]bool) database.GetContestSubmissionsRow {
+	score, _ := problem_service.ScoreSubtasks(subtasks, passed)
+	return database.GetContestSubmissionsRow{
+		ID:          uuid.New(),
+		SubmittedBy: userID,
+		ProblemID:   1,
+		State:       "WRONG_ANSWER",
+		SubmittedAt: start.Add(time.Duration(minutes) * time.Minute),
+		Score:       &score,
+	}
+}
+
+func rowOf(t *testing.T, rows []contest_service.StandingsRow, userID uuid.UUID) contest_service.StandingsRow {
+	t.Helper()
+	for _, row := range rows {
+		if row.UserID == userID {
+			return row
+		}
+	}
+	t.Fatalf("no row for user %v", userID)
+	return contest_service.StandingsRow{}
+}
+
+func TestSubtaskScoreReachesStandings(t *testing.T) {
+	submissions := []database.GetContestSubmissionsRow{
+		// 20 + 30 points of 100
+		judged(alice, 10, map[int32]bool{1: true, 2: true}),
+		// subtask 2 is not awarded without subtask 1
+		judged(bob, 15, map[int32]bool{2: true, 3: true}),
+	}
+
+	rows := contest_service.ComputeStandings(
+		contest, problems, submissions, nil, nil, subtaskPoints, nil,
+	)
+
+	if got := rowOf(t, rows, alice).Score; got != 250 {
+		t.Errorf("expected alice to score 250, got %d", got)
+	}
+	if got := rowOf(t, rows, bob).Score; got != 0 {
+		t.Errorf("expected bob to score 0, got %d", got)
+	}
+}
+
+func TestBestSubtaskScoreIsKept(t *testing.T) {
+	submissions := []database.GetContestSubmissionsRow{
+		judged(alice, 10, map[int32]bool{1: true, 2: true}),
+		judged(alice, 20, map[int32]bool{1: true}),
+	}
+
+	rows := contest_service.ComputeStandings(
+		contest, problems, submissions, nil, nil, subtaskPoints, nil,
+	)
+
+	row := rowOf(t, rows, alice)
+	if row.Score != 250 {
+		t.Errorf("expected the best score 250 to be kept, got %d", row.Score)
+	}
+	if row.Problems[0].Score != 250 || row.Problems[0].Accepted {
+		t.Errorf("expected a partial 250 on problem 1, got %+v", row.Problems[0])
+	}
+}
+
+func TestAcceptanceReplacesSubtaskScore(t *testing.T) {
+	accepted := judged(alice, 30, map[int32]bool{1: true, 2: true, 3: true})
+	accepted.State = contest_service.SubStateAccepted
+	submissions := []database.GetContestSubmissionsRow{
+		judged(alice, 10, map[int32]bool{1: true}),
+		accepted,
+	}
+
+	rows := contest_service.ComputeStandings(
+		contest, problems, submissions, nil, nil, subtaskPoints, nil,
+	)
+
+	row := rowOf(t, rows, alice)
+	if row.Score != 500 || !row.Problems[0].Accepted {
+		t.Errorf("expected the full 500 after acceptance, got %+v", row)
+	}
+}
+
+// submission made at the given minutes from the start of the virtual run
+func virtual(participation database.VirtualParticipation, minutes int, state string) database.GetContestSubmissionsRow {
+	return database.GetContestSubmissionsRow{
+		ID:                     uuid.New(),
+		SubmittedBy:            participation.UserID,
+		ProblemID:              2,
+		State:                  state,
+		SubmittedAt:            participation.StartTime.Add(time.Duration(minutes) * time.Minute),
+		VirtualParticipationID: &participation.ID,
+	}
+}
+
+func TestVirtualRowsAreTimedFromTheirRun(t *testing.T) {
+	participation := database.VirtualParticipation{
+		ID:        uuid.New(),
+		ContestID: contest.ID,
+		UserID:    bob,
+		StartTime: start.Add(24 * time.Hour),
+		EndTime:   start.Add(26 * time.Hour),
+	}
+	submissions := []database.GetContestSubmissionsRow{
+		{
+			ID:          uuid.New(),
+			SubmittedBy: alice,
+			ProblemID:   2,
+			State:       contest_service.SubStateAccepted,
+			SubmittedAt: start.Add(30 * time.Minute),
+		},
+		virtual(participation, 30, contest_service.SubStateAccepted),
+		// after the end of the run
+		virtual(participation, 150, contest_service.SubStateAccepted),
+	}
+
+	rows := contest_service.ComputeStandings(
+		contest,
+		problems,
+		submissions,
+		map[uuid.UUID]database.VirtualParticipation{participation.ID: participation},
+		nil,
+		subtaskPoints,
+		nil,
+	)
+	contest_service.RankStandings(rows)
+
+	if len(rows) != 2 {
+		t.Fatalf("expected a real and a virtual row, got %d rows", len(rows))
+	}
+	if rows[0].UserID != alice || rows[0].IsVirtual {
+		t.Errorf("expected the real row to be listed first, got %+v", rows[0])
+	}
+	row := rows[1]
+	if !row.IsVirtual || row.UserID != bob {
+		t.Fatalf("expected the virtual row of bob, got %+v", row)
+	}
+	if row.Rank != rows[0].Rank || row.Score != 300 || row.Penalty != 30 {
+		t.Errorf("expected the virtual row to tie with the real one, got %+v", row)
+	}
+	if at := row.Problems[1].AcceptedAt; at == nil || *at != 30*60 {
+		t.Errorf("expected acceptance 30 minutes into the run, got %v", at)
+	}
+}
+
+func TestVirtualRowsNeedTheirParticipation(t *testing.T) {
+	participation := database.VirtualParticipation{
+		ID:        uuid.New(),
+		ContestID: contest.ID,
+		UserID:    bob,
+		StartTime: start.Add(24 * time.Hour),
+		EndTime:   start.Add(26 * time.Hour),
+	}
+	submissions := []database.GetContestSubmissionsRow{
+		virtual(participation, 30, contest_service.SubStateAccepted),
+	}
+
+	// virtual participants are not requested
+	rows := contest_service.ComputeStandings(
+		contest, problems, submissions, nil, nil, subtaskPoints, nil,
+	)
+	if len(rows) != 0 {
+		t.Errorf("expected no rows without the participation, got %+v", rows)
+	}
+}
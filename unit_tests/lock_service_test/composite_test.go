@@ -0,0 +1,102 @@
+package lock_servicetest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/service/lock_service"
+)
+
+var now = time.Date(2025, 1, 1, 18, 0, 0, 0, time.UTC)
+
+func at(minutes int) *time.Time {
+	t := now.Add(time.Duration(minutes) * time.Minute)
+	return &t
+}
+
+func timer(minutes int) lock_service.LockCondition {
+	return lock_service.LockCondition{Type: database.LockTypeTimer, Timeout: at(minutes)}
+}
+
+func manual(releasedAt *time.Time) lock_service.LockCondition {
+	return lock_service.LockCondition{Type: database.LockTypeManual, ReleasedAt: releasedAt}
+}
+
+func TestTimerAndManualLocks(t *testing.T) {
+	if timer(10).IsOpenAt(now) {
+		t.Error("timer lock should be closed before its timeout")
+	}
+	if !timer(-10).IsOpenAt(now) {
+		t.Error("timer lock should be open after its timeout")
+	}
+	if manual(nil).IsOpenAt(now) {
+		t.Error("unreleased manual lock should be closed")
+	}
+	if !manual(at(-1)).IsOpenAt(now) {
+		t.Error("released manual lock should be open")
+	}
+	if manual(at(5)).IsOpenAt(now) {
+		t.Error("manual lock should be closed before it is released")
+	}
+}
+
+func TestCompositeAnd(t *testing.T) {
+	// visible after 18:00 and after the previous round's contest ends
+	lock := lock_service.LockCondition{
+		Type:            database.LockTypeComposite,
+		Operator:        lock_service.OperatorAnd,
+		Children:        []lock_service.LockCondition{timer(0)},
+		ContestEndTimes: []time.Time{*at(30)},
+	}
+	if lock.IsOpenAt(now.Add(time.Minute)) {
+		t.Error("lock should stay closed until the contest ends")
+	}
+	if !lock.IsOpenAt(now.Add(31 * time.Minute)) {
+		t.Error("lock should open once every condition holds")
+	}
+}
+
+func TestCompositeOr(t *testing.T) {
+	// manual release or timer, whichever comes first
+	lock := lock_service.LockCondition{
+		Type:     database.LockTypeComposite,
+		Operator: lock_service.OperatorOr,
+		Children: []lock_service.LockCondition{manual(nil), timer(60)},
+	}
+	if lock.IsOpenAt(now) {
+		t.Error("lock should be closed while no condition holds")
+	}
+	if !lock.IsOpenAt(now.Add(61 * time.Minute)) {
+		t.Error("lock should open with the timer")
+	}
+
+	lock.Children[0] = manual(at(-1))
+	if !lock.IsOpenAt(now) {
+		t.Error("lock should open with the manual release")
+	}
+}
+
+func TestNestedComposite(t *testing.T) {
+	inner := lock_service.LockCondition{
+		Type:     database.LockTypeComposite,
+		Operator: lock_service.OperatorOr,
+		Children: []lock_service.LockCondition{manual(nil), timer(10)},
+	}
+	outer := lock_service.LockCondition{
+		Type:     database.LockTypeComposite,
+		Operator: lock_service.OperatorAnd,
+		Children: []lock_service.LockCondition{inner, timer(20)},
+	}
+	if outer.IsOpenAt(now.Add(15 * time.Minute)) {
+		t.Error("outer lock should wait for its own timer")
+	}
+	if !outer.IsOpenAt(now.Add(21 * time.Minute)) {
+		t.Error("outer lock should open once both children are open")
+	}
+
+	empty := lock_service.LockCondition{Type: database.LockTypeComposite, Operator: lock_service.OperatorAnd}
+	if empty.IsOpenAt(now) {
+		t.Error("composite lock without conditions should never open")
+	}
+}
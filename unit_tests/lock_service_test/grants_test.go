@@ -0,0 +1,30 @@
+package lock_servicetest_test
+
+import (
+	"testing"
+
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/service/lock_service"
+)
+
+func TestGrantAllows(t *testing.T) {
+	view := database.LockGrantPermissionView
+	edit := database.LockGrantPermissionEdit
+
+	if lock_service.GrantAllows(nil, view) {
+		t.Error("no grants should allow nothing")
+	}
+	if !lock_service.GrantAllows([]database.LockGrantPermission{view}, view) {
+		t.Error("view grant should allow view")
+	}
+	if lock_service.GrantAllows([]database.LockGrantPermission{view}, edit) {
+		t.Error("view grant should not allow edit")
+	}
+	if !lock_service.GrantAllows([]database.LockGrantPermission{edit}, view) {
+		t.Error("edit grant should also allow view")
+	}
+	// a user and their team can both hold grants on the same lock
+	if !lock_service.GrantAllows([]database.LockGrantPermission{view, edit}, edit) {
+		t.Error("edit grant through a team should allow edit")
+	}
+}
@@ -0,0 +1,54 @@
+package problem_servicetest_test
+
+import (
+	"testing"
+
+	"github.com/tcp_snm/flux/internal/service/problem_service"
+)
+
+var subtasks = []problem_service.Subtask{
+	{SubtaskID: 1, Points: 10, Testcases: []int32{1, 2}},
+	{SubtaskID: 2, Points: 30, Testcases: []int32{3, 4}, DependsOn: []int32{1}},
+	{SubtaskID: 3, Points: 60, Testcases: []int32{5, 6}, DependsOn: []int32{2}},
+}
+
+func TestAllPassed(t *testing.T) {
+	score, awarded := problem_service.ScoreSubtasks(
+		subtasks, map[int32]bool{1: true, 2: true, 3: true},
+	)
+	if score != 100 {
+		t.Errorf("expected 100, got %d", score)
+	}
+	if len(awarded) != 3 {
+		t.Errorf("expected all subtasks to be awarded, got %v", awarded)
+	}
+}
+
+func TestPartial(t *testing.T) {
+	score, _ := problem_service.ScoreSubtasks(
+		subtasks, map[int32]bool{1: true, 2: true, 3: false},
+	)
+	if score != 40 {
+		t.Errorf("expected 40, got %d", score)
+	}
+}
+
+func TestFailedDependency(t *testing.T) {
+	// subtask 3 depends on the failed subtask 2
+	score, awarded := problem_service.ScoreSubtasks(
+		subtasks, map[int32]bool{1: true, 2: false, 3: true},
+	)
+	if score != 10 {
+		t.Errorf("expected 10, got %d", score)
+	}
+	if awarded[3] {
+		t.Errorf("subtask 3 must not be awarded when subtask 2 fails")
+	}
+}
+
+func TestNothingReported(t *testing.T) {
+	score, _ := problem_service.ScoreSubtasks(subtasks, nil)
+	if score != 0 {
+		t.Errorf("expected 0, got %d", score)
+	}
+}
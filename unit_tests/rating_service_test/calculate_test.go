@@ -0,0 +1,87 @@
+package rating_servicetest_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/service/rating_service"
+)
+
+func newParticipants(ranks []int32, ratings []int32) []rating_service.RatingParticipant {
+	participants := make([]rating_service.RatingParticipant, 0, len(ranks))
+	for i := range ranks {
+		participants = append(participants, rating_service.RatingParticipant{
+			UserID: uuid.New(),
+			Rank:   ranks[i],
+			Rating: ratings[i],
+		})
+	}
+	return participants
+}
+
+func TestSingleParticipant(t *testing.T) {
+	deltas := rating_service.CalculateRatingChanges(
+		newParticipants([]int32{1}, []int32{rating_service.InitialRating}),
+	)
+	if len(deltas) != 1 || deltas[0] != 0 {
+		t.Fatalf("expected no change for a single participant, got %v", deltas)
+	}
+}
+
+func TestEqualRatings(t *testing.T) {
+	ranks := []int32{1, 2, 3, 4, 5}
+	ratings := []int32{1500, 1500, 1500, 1500, 1500}
+	deltas := rating_service.CalculateRatingChanges(newParticipants(ranks, ratings))
+
+	if deltas[0] <= 0 {
+		t.Errorf("winner must gain rating, got %d", deltas[0])
+	}
+	if deltas[len(deltas)-1] >= 0 {
+		t.Errorf("last participant must lose rating, got %d", deltas[len(deltas)-1])
+	}
+	for i := 1; i < len(deltas); i++ {
+		if deltas[i] > deltas[i-1] {
+			t.Errorf("rank %d gained more than rank %d, %v", ranks[i], ranks[i-1], deltas)
+		}
+	}
+}
+
+func TestNoInflation(t *testing.T) {
+	ranks := []int32{1, 2, 3, 4, 5, 6, 7, 8}
+	ratings := []int32{1200, 1900, 1500, 1650, 1400, 2100, 1500, 1300}
+	deltas := rating_service.CalculateRatingChanges(newParticipants(ranks, ratings))
+
+	var sum int32
+	for _, delta := range deltas {
+		sum += delta
+	}
+	if sum > 0 {
+		t.Errorf("total rating must not increase, got %d from %v", sum, deltas)
+	}
+}
+
+func TestUpset(t *testing.T) {
+	// a low rated participant beating a high rated one gains more than
+	// the high rated one would have by winning
+	upset := rating_service.CalculateRatingChanges(
+		newParticipants([]int32{1, 2}, []int32{1200, 1800}),
+	)
+	expected := rating_service.CalculateRatingChanges(
+		newParticipants([]int32{1, 2}, []int32{1800, 1200}),
+	)
+	if upset[0] <= expected[0] {
+		t.Errorf("upset winner gained %d, expected winner gained %d", upset[0], expected[0])
+	}
+	if upset[1] >= expected[1] {
+		t.Errorf("upset loser lost %d, expected loser lost %d", upset[1], expected[1])
+	}
+}
+
+func TestTies(t *testing.T) {
+	deltas := rating_service.CalculateRatingChanges(
+		newParticipants([]int32{1, 1, 3}, []int32{1500, 1500, 1500}),
+	)
+	if deltas[0] != deltas[1] {
+		t.Errorf("tied participants with same rating must change equally, got %v", deltas)
+	}
+}
@@ -0,0 +1,139 @@
+package tournament_servicetest_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+	"github.com/tcp_snm/flux/internal/service/tournament_service"
+)
+
+var (
+	alice   = uuid.New()
+	bob     = uuid.New()
+	charlie = uuid.New()
+	dave    = uuid.New()
+	erin    = uuid.New()
+	teamID  = uuid.New()
+)
+
+// two contests of a round, dave and erin are a team in the second one
+var standings = []contest_service.ContestStandings{
+	{
+		ContestID: uuid.New(),
+		Rows: []contest_service.StandingsRow{
+			{Rank: 1, UserID: alice, UserName: "alice", Score: 300, Penalty: 40},
+			{Rank: 2, UserID: bob, UserName: "bob", Score: 200, Penalty: 10},
+			{Rank: 2, UserID: uuid.New(), UserName: "virtual", Score: 250, IsVirtual: true},
+		},
+	},
+	{
+		ContestID: uuid.New(),
+		Rows: []contest_service.StandingsRow{
+			{Rank: 1, TeamID: &teamID, MemberIDs: []uuid.UUID{dave, erin}, Score: 250, Penalty: 30},
+			{Rank: 2, UserID: charlie, UserName: "charlie", Score: 200, Penalty: 10},
+		},
+	},
+}
+
+func ptr(v int32) *int32 {
+	return &v
+}
+
+func userIDs(qualifiers []tournament_service.Qualifier) map[uuid.UUID]int32 {
+	res := make(map[uuid.UUID]int32, len(qualifiers))
+	for _, qualifier := range qualifiers {
+		res[qualifier.UserID] = qualifier.Rank
+	}
+	return res
+}
+
+func TestTopOverall(t *testing.T) {
+	qualifiers := tournament_service.SelectQualifiers(
+		tournament_service.AdvancementRule{Rule: tournament_service.AdvancementTopOverall, TopK: ptr(3)},
+		standings,
+	)
+
+	// alice 1, dave and erin share 2, bob and charlie share 4
+	got := userIDs(qualifiers)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 qualifiers, got %v", qualifiers)
+	}
+	if got[alice] != 1 || got[dave] != 2 || got[erin] != 2 {
+		t.Errorf("unexpected ranks %v", qualifiers)
+	}
+}
+
+func TestTopOverallTies(t *testing.T) {
+	qualifiers := tournament_service.SelectQualifiers(
+		tournament_service.AdvancementRule{Rule: tournament_service.AdvancementTopOverall, TopK: ptr(4)},
+		standings,
+	)
+
+	// bob and charlie tie at rank 4, so both advance
+	if len(qualifiers) != 5 {
+		t.Errorf("expected 5 qualifiers, got %v", qualifiers)
+	}
+}
+
+func TestTopPerContest(t *testing.T) {
+	qualifiers := tournament_service.SelectQualifiers(
+		tournament_service.AdvancementRule{Rule: tournament_service.AdvancementTopPerContest, TopK: ptr(1)},
+		standings,
+	)
+
+	got := userIDs(qualifiers)
+	if len(got) != 3 {
+		t.Fatalf("expected alice, dave and erin, got %v", qualifiers)
+	}
+	for _, id := range []uuid.UUID{alice, dave, erin} {
+		if _, ok := got[id]; !ok {
+			t.Errorf("expected %v to qualify", id)
+		}
+	}
+}
+
+func TestScoreThreshold(t *testing.T) {
+	qualifiers := tournament_service.SelectQualifiers(
+		tournament_service.AdvancementRule{Rule: tournament_service.AdvancementScoreThreshold, MinScore: ptr(250)},
+		standings,
+	)
+
+	// the virtual participant is never considered
+	got := userIDs(qualifiers)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 qualifiers, got %v", qualifiers)
+	}
+	if _, ok := got[bob]; ok {
+		t.Errorf("bob is below the threshold")
+	}
+}
+
+func TestQualifiesOnce(t *testing.T) {
+	// alice takes part in both the contests of the round
+	twice := append([]contest_service.ContestStandings{}, standings...)
+	twice = append(twice, contest_service.ContestStandings{
+		ContestID: uuid.New(),
+		Rows: []contest_service.StandingsRow{
+			{Rank: 1, UserID: alice, UserName: "alice", Score: 100},
+		},
+	})
+
+	qualifiers := tournament_service.SelectQualifiers(
+		tournament_service.AdvancementRule{Rule: tournament_service.AdvancementTopPerContest, TopK: ptr(1)},
+		twice,
+	)
+
+	count := 0
+	for _, qualifier := range qualifiers {
+		if qualifier.UserID == alice {
+			count++
+			if qualifier.Score != 300 {
+				t.Errorf("expected the best result of alice, got %v", qualifier)
+			}
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected alice once, got %d times", count)
+	}
+}
@@ -0,0 +1,57 @@
+package tournament_servicetest_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/tcp_snm/flux/internal/service/tournament_service"
+)
+
+func TestBracketSeedOrder(t *testing.T) {
+	tests := map[int][]int32{
+		1: {1},
+		2: {1, 2},
+		4: {1, 4, 2, 3},
+		8: {1, 8, 4, 5, 2, 7, 3, 6},
+	}
+	for size, want := range tests {
+		got := tournament_service.BracketSeedOrder(size)
+		if !slices.Equal(got, want) {
+			t.Errorf("size %d: expected %v, got %v", size, want, got)
+		}
+	}
+}
+
+func TestBracketSeedOrderPairs(t *testing.T) {
+	// every first round match pairs seeds adding up to size + 1
+	size := 16
+	order := tournament_service.BracketSeedOrder(size)
+	for i := 0; i < len(order); i += 2 {
+		if order[i]+order[i+1] != int32(size+1) {
+			t.Errorf("match %d pairs seeds %d and %d", i/2+1, order[i], order[i+1])
+		}
+	}
+}
+
+func TestMatchWinner(t *testing.T) {
+	a := tournament_service.MatchResult{UserID: alice, Seed: 2, Score: 200, Penalty: 50}
+	b := tournament_service.MatchResult{UserID: bob, Seed: 1, Score: 100, Penalty: 10}
+	if got := tournament_service.MatchWinner(a, b); got != alice {
+		t.Errorf("higher score should win, got %v", got)
+	}
+
+	// equal scores, lower penalty wins
+	b.Score = 200
+	if got := tournament_service.MatchWinner(a, b); got != bob {
+		t.Errorf("lower penalty should win, got %v", got)
+	}
+
+	// equal scores and penalties, better seed wins
+	b.Penalty = 50
+	if got := tournament_service.MatchWinner(a, b); got != bob {
+		t.Errorf("better seed should win, got %v", got)
+	}
+	if got := tournament_service.MatchWinner(b, a); got != bob {
+		t.Errorf("winner should not depend on the order, got %v", got)
+	}
+}
@@ -0,0 +1,132 @@
+package tournament_servicetest_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+	"github.com/tcp_snm/flux/internal/service/tournament_service"
+)
+
+func row(userID uuid.UUID, score int32, penalty int64) contest_service.StandingsRow {
+	return contest_service.StandingsRow{UserID: userID, Score: score, Penalty: penalty}
+}
+
+func findRow(rows []tournament_service.TournamentStandingsRow, userID uuid.UUID) tournament_service.TournamentStandingsRow {
+	for _, r := range rows {
+		if r.UserID == userID {
+			return r
+		}
+	}
+	return tournament_service.TournamentStandingsRow{}
+}
+
+func TestAggregateWeights(t *testing.T) {
+	rows := tournament_service.AggregateStandings([]tournament_service.RoundStandings{
+		{
+			RoundNumber: 1,
+			Weight:      50,
+			Contests: []contest_service.ContestStandings{
+				{Rows: []contest_service.StandingsRow{row(alice, 100, 10), row(bob, 300, 10)}},
+			},
+		},
+		{
+			RoundNumber: 2,
+			Weight:      200,
+			Contests: []contest_service.ContestStandings{
+				{Rows: []contest_service.StandingsRow{row(alice, 100, 10)}},
+			},
+		},
+	})
+
+	// alice 50 + 200, bob 150
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %v", rows)
+	}
+	if rows[0].UserID != alice || rows[0].Points != 250 || rows[0].Rank != 1 {
+		t.Errorf("expected alice first with 250 points, got %v", rows[0])
+	}
+	if rows[1].UserID != bob || rows[1].Points != 150 {
+		t.Errorf("expected bob second with 150 points, got %v", rows[1])
+	}
+}
+
+func TestAggregateBestOf(t *testing.T) {
+	bestOf := int32(2)
+	rows := tournament_service.AggregateStandings([]tournament_service.RoundStandings{
+		{
+			RoundNumber: 1,
+			Weight:      100,
+			BestOf:      &bestOf,
+			Contests: []contest_service.ContestStandings{
+				{Rows: []contest_service.StandingsRow{row(alice, 100, 5)}},
+				{Rows: []contest_service.StandingsRow{row(alice, 50, 5)}},
+				{Rows: []contest_service.StandingsRow{row(alice, 300, 5)}},
+			},
+		},
+	})
+
+	got := findRow(rows, alice)
+	if got.Points != 400 || got.Penalty != 10 || got.Rounds[0].Contests != 2 {
+		t.Errorf("expected the best two contests to count, got %v", got)
+	}
+}
+
+func TestAggregateTieBreakers(t *testing.T) {
+	rows := tournament_service.AggregateStandings([]tournament_service.RoundStandings{
+		{
+			RoundNumber: 1,
+			Weight:      100,
+			Contests: []contest_service.ContestStandings{
+				{Rows: []contest_service.StandingsRow{
+					row(alice, 100, 10), row(bob, 100, 5), row(charlie, 100, 10), row(dave, 100, 10),
+				}},
+			},
+		},
+		{
+			RoundNumber: 2,
+			Weight:      100,
+			Contests: []contest_service.ContestStandings{
+				{Rows: []contest_service.StandingsRow{row(dave, 0, 0)}},
+			},
+		},
+	})
+
+	// dave reached further, bob has less penalty, alice and charlie tie
+	if rows[0].UserID != dave || rows[1].UserID != bob {
+		t.Fatalf("unexpected order %v", rows)
+	}
+	if rows[2].Rank != 3 || rows[3].Rank != 3 {
+		t.Errorf("expected alice and charlie to share rank 3, got %v", rows)
+	}
+}
+
+func TestAggregateElimination(t *testing.T) {
+	rows := tournament_service.AggregateStandings([]tournament_service.RoundStandings{
+		{
+			RoundNumber: 1,
+			Weight:      100,
+			Qualified:   map[uuid.UUID]bool{alice: true, erin: true},
+			Contests: []contest_service.ContestStandings{
+				{Rows: []contest_service.StandingsRow{
+					row(alice, 100, 0),
+					row(bob, 50, 0),
+					{TeamID: &teamID, MemberIDs: []uuid.UUID{dave, erin}, Score: 70},
+				}},
+			},
+		},
+	})
+
+	if findRow(rows, alice).EliminatedInRound != nil {
+		t.Errorf("alice qualified")
+	}
+	if r := findRow(rows, bob); r.EliminatedInRound == nil || *r.EliminatedInRound != 1 {
+		t.Errorf("bob should be eliminated in round 1, got %v", r)
+	}
+	if r := findRow(rows, dave); r.EliminatedInRound == nil || r.Points != 70 {
+		t.Errorf("dave should be eliminated with the points of the team, got %v", r)
+	}
+	if findRow(rows, erin).EliminatedInRound != nil {
+		t.Errorf("erin qualified")
+	}
+}
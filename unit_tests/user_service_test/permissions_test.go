@@ -0,0 +1,105 @@
+package user_servicetest_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+func TestMissingPermissions(t *testing.T) {
+	held := []string{user_service.PermContestCreate, user_service.PermContestManage}
+
+	missing := user_service.MissingPermissions(held, nil)
+	if len(missing) != 0 {
+		t.Errorf("nothing required should miss nothing, got %v", missing)
+	}
+
+	missing = user_service.MissingPermissions(held, []string{user_service.PermContestManage})
+	if len(missing) != 0 {
+		t.Errorf("held permission should not be missing, got %v", missing)
+	}
+
+	// order is kept and duplicates are reported once
+	missing = user_service.MissingPermissions(held, []string{
+		user_service.PermRoleManage,
+		user_service.PermContestCreate,
+		user_service.PermLockEditAny,
+		user_service.PermRoleManage,
+	})
+	want := []string{user_service.PermRoleManage, user_service.PermLockEditAny}
+	if !slices.Equal(missing, want) {
+		t.Errorf("expected %v, got %v", want, missing)
+	}
+
+	// no permissions held
+	missing = user_service.MissingPermissions(nil, []string{user_service.PermBotManage})
+	if !slices.Equal(missing, []string{user_service.PermBotManage}) {
+		t.Errorf("expected %v to be missing, got %v", user_service.PermBotManage, missing)
+	}
+}
+
+func TestScopedPermissions(t *testing.T) {
+	held := []string{
+		user_service.PermProblemCreate,
+		user_service.PermBotManage,
+		user_service.PermContestManage,
+	}
+
+	// scopes not held are not granted
+	scoped := user_service.ScopedPermissions(held, []string{
+		user_service.PermContestManage,
+		user_service.PermRoleManage,
+		user_service.PermProblemCreate,
+	})
+	want := []string{user_service.PermProblemCreate, user_service.PermContestManage}
+	if !slices.Equal(scoped, want) {
+		t.Errorf("expected %v, got %v", want, scoped)
+	}
+
+	// a token without scopes has no permissions
+	scoped = user_service.ScopedPermissions(held, nil)
+	if len(scoped) != 0 {
+		t.Errorf("expected no permissions without scopes, got %v", scoped)
+	}
+}
+
+func TestHoldsTwoFactorRole(t *testing.T) {
+	if user_service.HoldsTwoFactorRole([]string{"User"}) {
+		t.Error("users without privileged roles should not need the second factor")
+	}
+	if !user_service.HoldsTwoFactorRole([]string{"User", user_service.RoleManager}) {
+		t.Error("managers should need the second factor")
+	}
+	if !user_service.HoldsTwoFactorRole([]string{user_service.RoleHC}) {
+		t.Error("head coordinators should need the second factor")
+	}
+}
+
+func TestOwnerAllowed(t *testing.T) {
+	tokenID := uuid.New()
+	session := service.UserCredentialClaims{UserId: uuid.New()}
+
+	if !user_service.OwnerAllowed(session, user_service.PermProblemEditAny, false) {
+		t.Error("owner session should be allowed")
+	}
+	if user_service.OwnerAllowed(session, user_service.PermProblemEditAny, true) {
+		t.Error("owner session without a required second factor should be denied")
+	}
+
+	token := session
+	token.AccessTokenID = &tokenID
+	if user_service.OwnerAllowed(token, user_service.PermProblemEditAny, false) {
+		t.Error("owner token without scopes should be denied")
+	}
+
+	token.Scopes = []string{user_service.PermContestEditAny}
+	if user_service.OwnerAllowed(token, user_service.PermProblemEditAny, false) {
+		t.Error("owner token should be denied permissions outside its scopes")
+	}
+	if !user_service.OwnerAllowed(token, user_service.PermContestEditAny, false) {
+		t.Error("owner token should be allowed permissions in its scopes")
+	}
+}